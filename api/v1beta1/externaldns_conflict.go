@@ -0,0 +1,269 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Overlap returns the description of the DNS names which are managed
+// by both the given ExternalDNS instances. An empty string is returned
+// if the instances don't overlap.
+//
+// The instances overlap if they use the same provider, list the same zones,
+// have the overlapping include domain filters and publish the source resources
+// of the same namespaces.
+// An empty list of zones is not considered as overlapping:
+// the zones accessible with the credentials of the instances are not known, see PossibleOverlap.
+// Neither are the source namespaces selected by a namespace selector:
+// the labels of the namespaces change over time, see PossibleOverlap.
+// The instances in the dry run mode don't overlap with any other instance
+// as they don't change the DNS records.
+func (r *ExternalDNS) Overlap(other *ExternalDNS) string {
+	if r.Name == other.Name || r.Spec.Provider.Type != other.Spec.Provider.Type {
+		return ""
+	}
+
+	if r.Spec.DryRun || other.Spec.DryRun {
+		return ""
+	}
+
+	if overlap, known := r.sourceNamespacesOverlap(other); !overlap || !known {
+		return ""
+	}
+
+	zones := commonValues(r.Spec.Zones, other.Spec.Zones)
+	if len(zones) == 0 {
+		return ""
+	}
+
+	domains, overlap := overlappingDomains(includeDomains(r.Spec.Domains), includeDomains(other.Spec.Domains))
+	if !overlap {
+		return ""
+	}
+
+	return fmt.Sprintf("%s in zones %s", domains, quoteAll(zones))
+}
+
+// PossibleOverlap returns the description of the DNS names which may be managed
+// by both the given ExternalDNS instances. An empty string is returned
+// if the instances cannot overlap or if they overlap for sure, see Overlap.
+//
+// An instance with an empty list of zones manages all the zones accessible
+// with its credentials: the instances of the same provider which use
+// the same credentials may manage the same zones.
+// The instances which list the same zones may manage the same DNS names
+// if a namespace selector may select the source namespaces of the other instance.
+func (r *ExternalDNS) PossibleOverlap(other *ExternalDNS) string {
+	if r.Name == other.Name || r.Spec.Provider.Type != other.Spec.Provider.Type {
+		return ""
+	}
+
+	if r.Spec.DryRun || other.Spec.DryRun {
+		return ""
+	}
+
+	namespacesOverlap, namespacesKnown := r.sourceNamespacesOverlap(other)
+	if !namespacesOverlap {
+		return ""
+	}
+
+	domains, overlap := overlappingDomains(includeDomains(r.Spec.Domains), includeDomains(other.Spec.Domains))
+	if !overlap {
+		return ""
+	}
+
+	if zones := commonValues(r.Spec.Zones, other.Spec.Zones); len(zones) > 0 {
+		if namespacesKnown {
+			// overlap for sure
+			return ""
+		}
+		return fmt.Sprintf("%s in zones %s of the source namespaces selected by both instances", domains, quoteAll(zones))
+	}
+
+	if len(r.Spec.Zones) > 0 && len(other.Spec.Zones) > 0 {
+		return ""
+	}
+
+	if r.credentialsName() != other.credentialsName() {
+		return ""
+	}
+
+	return fmt.Sprintf("%s in all the zones accessible with the same credentials", domains)
+}
+
+// sourceNamespacesOverlap returns true if the given ExternalDNS instances
+// may publish the source resources of the same namespaces.
+// The second value is false if the overlap depends on the labels of the namespaces:
+// the namespaces selected by a namespace selector are not known in advance.
+func (r *ExternalDNS) sourceNamespacesOverlap(other *ExternalDNS) (bool, bool) {
+	rSource, otherSource := r.Spec.Source, other.Spec.Source
+	if (len(rSource.Namespaces) == 0 && rSource.NamespaceSelector == nil) || (len(otherSource.Namespaces) == 0 && otherSource.NamespaceSelector == nil) {
+		// all the namespaces
+		return true, true
+	}
+	if len(commonValues(rSource.Namespaces, otherSource.Namespaces)) > 0 {
+		return true, true
+	}
+	if rSource.NamespaceSelector != nil || otherSource.NamespaceSelector != nil {
+		return true, false
+	}
+	return false, true
+}
+
+// credentialsName returns the name of the credentials secret of the provider,
+// an empty string if the credentials are requested from the platform.
+func (r *ExternalDNS) credentialsName() string {
+	switch provider := r.Spec.Provider; provider.Type {
+	case ProviderTypeAWS:
+		if provider.AWS != nil {
+			return provider.AWS.Credentials.Name
+		}
+	case ProviderTypeAzure:
+		if provider.Azure != nil && provider.Azure.ConfigFile != nil {
+			return provider.Azure.ConfigFile.Name
+		}
+	case ProviderTypeGCP:
+		if provider.GCP != nil {
+			return provider.GCP.Credentials.Name
+		}
+	case ProviderTypeBlueCat:
+		if provider.BlueCat != nil {
+			if provider.BlueCat.ConfigFile != nil && provider.BlueCat.ConfigFile.Name != "" {
+				return provider.BlueCat.ConfigFile.Name
+			}
+			if provider.BlueCat.Credentials != nil {
+				return provider.BlueCat.Credentials.Name
+			}
+		}
+	case ProviderTypeInfoblox:
+		if provider.Infoblox != nil {
+			return provider.Infoblox.Credentials.Name
+		}
+	}
+	return ""
+}
+
+// commonValues returns the values present in both the given lists.
+func commonValues(a, b []string) []string {
+	common := []string{}
+	for _, va := range a {
+		for _, vb := range b {
+			if va == vb {
+				common = append(common, va)
+				break
+			}
+		}
+	}
+	return common
+}
+
+// includeDomains returns the include domain filters.
+func includeDomains(domains []ExternalDNSDomain) []ExternalDNSDomainUnion {
+	includes := []ExternalDNSDomainUnion{}
+	for _, d := range domains {
+		if d.FilterType == FilterTypeInclude {
+			includes = append(includes, d.ExternalDNSDomainUnion)
+		}
+	}
+	return includes
+}
+
+// overlappingDomains returns the description of the overlapping include domain filters.
+// No include filters mean all the domains.
+func overlappingDomains(a, b []ExternalDNSDomainUnion) (string, bool) {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return "all domains", true
+	case len(a) == 0:
+		return "domains " + describeDomains(b), true
+	case len(b) == 0:
+		return "domains " + describeDomains(a), true
+	}
+
+	overlapping := []ExternalDNSDomainUnion{}
+	for _, da := range a {
+		for _, db := range b {
+			if domainsOverlap(da, db) {
+				overlapping = append(overlapping, da)
+				break
+			}
+		}
+	}
+	if len(overlapping) == 0 {
+		return "", false
+	}
+	return "domains " + describeDomains(overlapping), true
+}
+
+// domainsOverlap returns true if the given domain filters may match the same DNS name.
+// ExternalDNS domain filters match the subdomains as well.
+// Two regular expressions are only considered as overlapping if they are the same.
+func domainsOverlap(a, b ExternalDNSDomainUnion) bool {
+	switch {
+	case a.MatchType == DomainMatchTypeExact && b.MatchType == DomainMatchTypeExact:
+		if a.Name == nil || b.Name == nil {
+			return false
+		}
+		na, nb := normalizeDomain(*a.Name), normalizeDomain(*b.Name)
+		return na == nb || strings.HasSuffix(na, "."+nb) || strings.HasSuffix(nb, "."+na)
+	case a.MatchType == DomainMatchTypeRegex && b.MatchType == DomainMatchTypeRegex:
+		return a.Pattern != nil && b.Pattern != nil && *a.Pattern == *b.Pattern
+	case a.MatchType == DomainMatchTypeRegex:
+		return regexMatchesDomain(a.Pattern, b.Name)
+	default:
+		return regexMatchesDomain(b.Pattern, a.Name)
+	}
+}
+
+func regexMatchesDomain(pattern, name *string) bool {
+	if pattern == nil || name == nil {
+		return false
+	}
+	re, err := regexp.Compile(*pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(normalizeDomain(*name))
+}
+
+func normalizeDomain(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+func describeDomains(domains []ExternalDNSDomainUnion) string {
+	names := []string{}
+	for _, d := range domains {
+		switch {
+		case d.MatchType == DomainMatchTypeExact && d.Name != nil:
+			names = append(names, *d.Name)
+		case d.MatchType == DomainMatchTypeRegex && d.Pattern != nil:
+			names = append(names, *d.Pattern)
+		}
+	}
+	return quoteAll(names)
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+	return strings.Join(quoted, ", ")
+}
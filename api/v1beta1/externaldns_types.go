@@ -97,12 +97,104 @@ type ExternalDNSSpec struct {
 	// lookup a default set of zones e.g on OpenShift with its cluster
 	// DNS config
 	//
-	// +kubebuilder:validation:MaxItems=10
+	// At most 10 zones can be listed in the PerZone zones mode,
+	// every zone runs its own container. The Shared zones mode
+	// allows up to 100 zones.
+	//
+	// +kubebuilder:validation:MaxItems=100
 	// +kubebuilder:validation:Optional
 	// +optional
 	Zones []string `json:"zones,omitempty"`
+
+	// ZonesMode describes how the zones are distributed
+	// among the ExternalDNS containers:
+	//
+	// * PerZone: every zone is managed by its own container.
+	//
+	// * Shared: the zones are managed by a single container
+	//   which watches the source resources only once.
+	//   The zones which need a different provider configuration
+	//   are still split into separate containers:
+	//   Azure public and private zones,
+	//   AWS zones with different assume roles.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=PerZone;Shared
+	// +kubebuilder:default:=PerZone
+	// +optional
+	ZonesMode ZonesMode `json:"zonesMode,omitempty"`
+
+	// DryRun instructs ExternalDNS to compute the changes
+	// to the DNS records without applying them to the provider.
+	// The planned changes are summarized in the status
+	// and listed in the config map referenced by the status.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ManagementState describes how the operator manages
+	// the ExternalDNS deployment:
+	//
+	// * Managed: the deployment is reconciled by the operator.
+	//
+	// * Unmanaged: the deployment is left as it is,
+	//   it can be edited manually, e.g. for debugging.
+	//
+	// * Removed: the deployment is scaled down to zero replicas
+	//   and is not reconciled anymore. The DNS records and the credentials
+	//   are left intact.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Managed;Unmanaged;Removed
+	// +kubebuilder:default:=Managed
+	// +optional
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
+	// DeletionPolicy describes what happens to the DNS records
+	// when the ExternalDNS instance is deleted:
+	//
+	// * Retain: the records are left in the DNS provider.
+	//
+	// * Cleanup: the records owned by the instance are removed
+	//   from the DNS provider before the instance is deleted.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Retain;Cleanup
+	// +kubebuilder:default:=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ExternalDNSOperandConfig overrides the defaults
+	// from the ExternalDNSConfig named "cluster".
+	ExternalDNSOperandConfig `json:",inline"`
 }
 
+// ZonesMode describes how the zones are distributed among the ExternalDNS containers.
+type ZonesMode string
+
+const (
+	ZonesModePerZone ZonesMode = "PerZone"
+	ZonesModeShared  ZonesMode = "Shared"
+)
+
+// DeletionPolicy describes what happens to the DNS records when the ExternalDNS instance is deleted.
+type DeletionPolicy string
+
+const (
+	DeletionPolicyRetain  DeletionPolicy = "Retain"
+	DeletionPolicyCleanup DeletionPolicy = "Cleanup"
+)
+
+// ManagementState describes how the operator manages the ExternalDNS deployment.
+type ManagementState string
+
+const (
+	ManagementStateManaged   ManagementState = "Managed"
+	ManagementStateUnmanaged ManagementState = "Unmanaged"
+	ManagementStateRemoved   ManagementState = "Removed"
+)
+
 // ExternalDNSDomain describes how sets of included
 // or excluded domains are to be constructed.
 type ExternalDNSDomain struct {
@@ -241,6 +333,15 @@ type ExternalDNSProvider struct {
 	// +kubebuilder:validation:Optional
 	// +optional
 	Infoblox *ExternalDNSInfobloxProviderOptions `json:"infoblox,omitempty"`
+
+	// TrustedCA is a reference to a configmap in the operator namespace
+	// containing the PEM encoded CA bundle under the "ca-bundle.crt" key.
+	// The bundle is trusted only by this instance to reach the DNS provider,
+	// in addition to the trusted CA bundle of the operator.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	TrustedCA *ConfigMapReference `json:"trustedCA,omitempty"`
 }
 
 type ExternalDNSAWSProviderOptions struct {
@@ -255,6 +356,23 @@ type ExternalDNSAWSProviderOptions struct {
 	// +required
 	Credentials SecretReference `json:"credentials"`
 
+	// region is the AWS region ExternalDNS is configured with.
+	// The region determines the partition of the Route 53
+	// API endpoint, e.g. AWS GovCloud regions use
+	// a dedicated partition.
+	//
+	// The region is passed to ExternalDNS as AWS_REGION.
+	//
+	// On OpenShift, this field defaults to the region of the cluster
+	// when the resource is created. The current region of the cluster
+	// is used if this field is not set.
+	//
+	// e.g. "us-gov-west-1"
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Region string `json:"region,omitempty"`
+
 	// assumeRole is a reference to the IAM role that
 	// ExternalDNS will be assuming in order to perform
 	// any DNS updates.
@@ -262,14 +380,169 @@ type ExternalDNSAWSProviderOptions struct {
 	// +kubebuilder:validation:Optional
 	// +optional
 	AssumeRole *ExternalDNSAWSAssumeRoleOptions `json:"assumeRole,omitempty"`
+
+	// zoneAssumeRoles is a list of the IAM roles that
+	// ExternalDNS will be assuming in order to perform
+	// the DNS updates in the given zones.
+	// This allows to manage the hosted zones which
+	// belong to different AWS accounts.
+	//
+	// Each entry refers to a zone ID from the Zones field,
+	// the zones which don't have an entry use
+	// the assumeRole field if it's set.
+	//
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=zoneID
+	// +optional
+	ZoneAssumeRoles []ExternalDNSAWSZoneAssumeRoleOptions `json:"zoneAssumeRoles,omitempty"`
+
+	// zoneType filters the hosted zones ExternalDNS
+	// publishes records to by their visibility.
+	//
+	// This field accepts the following values:
+	//
+	//  "Public": Only public hosted zones are used.
+	//
+	//  "Private": Only private hosted zones are used.
+	//
+	// An empty value means that both public and
+	// private hosted zones are used.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ZoneType ExternalDNSAWSZoneType `json:"zoneType,omitempty"`
+
+	// zoneTags filters the hosted zones ExternalDNS
+	// publishes records to by their tags.
+	// Each entry is expected to be of the form "key=value",
+	// or "key" to match any value of the given tag key.
+	// A hosted zone has to match all the given tags to be used.
+	//
+	// Tag based filtering can be used together with Zones
+	// or instead of it, when the hosted zones are too many
+	// to be listed explicitly.
+	//
+	// e.g. "external-dns=managed"
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ZoneTags []string `json:"zoneTags,omitempty"`
+
+	// zonesCacheDuration is the time for which ExternalDNS
+	// caches the list of hosted zones before refreshing it
+	// from Route 53. Caching the zones reduces the number of
+	// API calls, which is useful when many hosted zones exist.
+	// The zones are not cached if this field is not set.
+	//
+	// e.g. "1h"
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ZonesCacheDuration *metav1.Duration `json:"zonesCacheDuration,omitempty"`
+
+	// evaluateTargetHealth is the default value of the
+	// "EvaluateTargetHealth" setting of the alias records
+	// created by ExternalDNS. It can be overridden for a given
+	// source resource with the
+	// "external-dns.alpha.kubernetes.io/aws-evaluate-target-health"
+	// annotation.
+	//
+	// ExternalDNS evaluates the target health
+	// if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	EvaluateTargetHealth *bool `json:"evaluateTargetHealth,omitempty"`
+
+	// preferCNAME instructs ExternalDNS to create
+	// CNAME records instead of alias records.
+	//
+	// If this field is not set, CNAME records are
+	// preferred only when the cluster runs in
+	// an AWS GovCloud region.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	PreferCNAME *bool `json:"preferCNAME,omitempty"`
+
+	// routingPolicies restricts the Route 53 routing policies
+	// which can be requested by the source resources
+	// using the ExternalDNS annotations.
+	// The source resources annotated with a routing policy
+	// which is not allowed are ignored by ExternalDNS.
+	// The ignored source resources are listed in the
+	// RoutingPoliciesAllowed status condition which is
+	// refreshed every 5 minutes.
+	//
+	// All the routing policies are allowed
+	// if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	RoutingPolicies *ExternalDNSAWSRoutingPolicyOptions `json:"routingPolicies,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Public;Private
+type ExternalDNSAWSZoneType string
+
+const (
+	AWSZoneTypePublic  ExternalDNSAWSZoneType = "Public"
+	AWSZoneTypePrivate ExternalDNSAWSZoneType = "Private"
+)
+
+// ExternalDNSAWSRoutingPolicyOptions describes which Route 53
+// routing policies are allowed for the source resources.
+type ExternalDNSAWSRoutingPolicyOptions struct {
+	// allowed is the list of the routing policies which
+	// can be requested by the source resources.
+	// The following values are accepted:
+	//
+	//  "Weighted": "external-dns.alpha.kubernetes.io/aws-weight" annotation.
+	//
+	//  "Latency": "external-dns.alpha.kubernetes.io/aws-region" annotation.
+	//
+	//  "Failover": "external-dns.alpha.kubernetes.io/aws-failover" annotation.
+	//
+	//  "Geolocation": "external-dns.alpha.kubernetes.io/aws-geolocation-*" annotations.
+	//
+	//  "SetIdentifier": "external-dns.alpha.kubernetes.io/set-identifier" annotation.
+	//
+	// Any routing policy other than "SetIdentifier"
+	// requires "SetIdentifier" to be allowed too
+	// as Route 53 identifies the records of the same name
+	// by their set identifier.
+	//
+	// An empty list means that no routing policy is allowed.
+	//
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	// +optional
+	Allowed []ExternalDNSAWSRoutingPolicy `json:"allowed,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Weighted;Latency;Failover;Geolocation;SetIdentifier
+type ExternalDNSAWSRoutingPolicy string
+
+const (
+	AWSRoutingPolicyWeighted      ExternalDNSAWSRoutingPolicy = "Weighted"
+	AWSRoutingPolicyLatency       ExternalDNSAWSRoutingPolicy = "Latency"
+	AWSRoutingPolicyFailover      ExternalDNSAWSRoutingPolicy = "Failover"
+	AWSRoutingPolicyGeolocation   ExternalDNSAWSRoutingPolicy = "Geolocation"
+	AWSRoutingPolicySetIdentifier ExternalDNSAWSRoutingPolicy = "SetIdentifier"
+)
+
 type ExternalDNSGCPProviderOptions struct {
 	// Project is the GCP project to use for
 	// creating DNS records. This field is not necessary
 	// when running on GCP as externalDNS auto-detects
 	// the GCP project to use when running on GCP.
 	//
+	// On OpenShift, the project of the cluster is used
+	// if this field is not set. It has to be set
+	// if the DNS zones are in another project,
+	// e.g. the host project of a shared VPC.
+	//
 	// +kubebuilder:validation:Optional
 	// +optional
 	Project *string `json:"project,omitempty"`
@@ -283,8 +556,66 @@ type ExternalDNSGCPProviderOptions struct {
 	// +kubebuilder:validation:Required
 	// +required
 	Credentials SecretReference `json:"credentials"`
+
+	// zoneVisibility filters the managed zones ExternalDNS
+	// publishes records to by their visibility.
+	//
+	// This field accepts the following values:
+	//
+	//  "Public": Only public managed zones are used.
+	//
+	//  "Private": Only private managed zones are used.
+	//
+	// An empty value means that both public and
+	// private managed zones are used.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ZoneVisibility ExternalDNSGCPZoneVisibility `json:"zoneVisibility,omitempty"`
+
+	// batchChangeSize is the maximum number of the DNS record
+	// changes sent to Cloud DNS in a single request.
+	// ExternalDNS uses 1000 if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchChangeSize *int32 `json:"batchChangeSize,omitempty"`
+
+	// batchChangeInterval is the time to wait
+	// between the change requests sent to Cloud DNS.
+	// ExternalDNS uses 1s if this field is not set.
+	//
+	// e.g. "5s"
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	BatchChangeInterval *metav1.Duration `json:"batchChangeInterval,omitempty"`
+
+	// impersonateServiceAccount is the email of the service account
+	// which ExternalDNS impersonates to manage the DNS records.
+	// The credentials from the Credentials secret (or the ones
+	// provided by the platform on OpenShift) are then only used
+	// to get the tokens of the impersonated service account,
+	// which requires the "roles/iam.serviceAccountTokenCreator"
+	// role on it.
+	//
+	// e.g. "dns-admin@my-project.iam.gserviceaccount.com"
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[^@\s]+@[^@\s]+\.gserviceaccount\.com$`
+	// +optional
+	ImpersonateServiceAccount string `json:"impersonateServiceAccount,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Public;Private
+type ExternalDNSGCPZoneVisibility string
+
+const (
+	GCPZoneVisibilityPublic  ExternalDNSGCPZoneVisibility = "Public"
+	GCPZoneVisibilityPrivate ExternalDNSGCPZoneVisibility = "Private"
+)
+
 type ExternalDNSAzureProviderOptions struct {
 	// ConfigFile is a reference to a secret containing
 	// the necessary information to use the Azure provider.
@@ -303,9 +634,63 @@ type ExternalDNSAzureProviderOptions struct {
 	// https://github.com/kubernetes-sigs/external-dns/blob/226dbb931f7a2019810b3703aec096c4ea4f40ea/docs/tutorials/azure.md#configuration-file
 	// for more information on the necessary configuration key/values and how to obtain them.
 	//
-	// +kubebuilder:validation:Required
-	// +required
-	ConfigFile SecretReference `json:"configFile"`
+	// ConfigFile cannot be used together with the other fields,
+	// the operator generates the configuration file from them
+	// if ConfigFile is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ConfigFile *SecretReference `json:"configFile,omitempty"`
+
+	// subscriptionID is the ID of the Azure subscription
+	// which contains the DNS zones.
+	//
+	// On OpenShift, the subscription of the cluster is used
+	// if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	SubscriptionID string `json:"subscriptionID,omitempty"`
+
+	// resourceGroup is the name of the Azure resource group
+	// which contains the DNS zones.
+	//
+	// On OpenShift, the resource group of the cluster is used
+	// if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+
+	// tenantID is the ID of the Azure Active Directory tenant.
+	//
+	// On OpenShift, the tenant of the cluster is used
+	// if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	// useManagedIdentityExtension instructs ExternalDNS to authenticate
+	// using the managed identity of the Azure virtual machine
+	// it runs on, instead of a service principal.
+	//
+	// The managed identity must be used when ConfigFile is not set
+	// and the operator doesn't run on OpenShift, as there is
+	// no other way to provide the credentials in this case.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	UseManagedIdentityExtension bool `json:"useManagedIdentityExtension,omitempty"`
+
+	// userAssignedIdentityClientID is the client ID of the
+	// user assigned managed identity to use.
+	// The system assigned managed identity is used if this field is not set.
+	// It can only be set when useManagedIdentityExtension is true.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	UserAssignedIdentityClientID string `json:"userAssignedIdentityClientID,omitempty"`
 }
 
 type ExternalDNSBlueCatProviderOptions struct {
@@ -328,9 +713,68 @@ type ExternalDNSBlueCatProviderOptions struct {
 	// https://github.com/kubernetes-sigs/external-dns/blob/226dbb931f7a2019810b3703aec096c4ea4f40ea/docs/tutorials/bluecat.md#using-json-configuration-file
 	// for more information on the necessary configuration values and how to obtain them.
 	//
-	// +kubebuilder:validation:Required
-	// +required
-	ConfigFile SecretReference `json:"configFile"`
+	// ConfigFile cannot be used together with the other fields,
+	// the operator generates the configuration file from them
+	// if ConfigFile is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ConfigFile *SecretReference `json:"configFile,omitempty"`
+
+	// credentials is a reference to a secret containing
+	// the following keys (with corresponding values):
+	//
+	// * username
+	// * password
+	//
+	// The credentials are used to authenticate
+	// to the BlueCat API gateway.
+	// It's required when ConfigFile is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Credentials *SecretReference `json:"credentials,omitempty"`
+
+	// gatewayHost is the URL of the BlueCat API gateway.
+	// It's required when ConfigFile is not set.
+	//
+	// e.g. "https://bluecatgw.example.com"
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	GatewayHost string `json:"gatewayHost,omitempty"`
+
+	// dnsConfiguration is the name of the BlueCat
+	// DNS configuration to use.
+	// It's required when ConfigFile is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	DNSConfiguration string `json:"dnsConfiguration,omitempty"`
+
+	// dnsView is the name of the BlueCat DNS view to use.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	DNSView string `json:"dnsView,omitempty"`
+
+	// rootZone is the zone under which
+	// ExternalDNS manages the records.
+	//
+	// e.g. "example.com"
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	RootZone string `json:"rootZone,omitempty"`
+
+	// skipTLSVerify disables the verification of the TLS certificate
+	// of the API gateway. It should only be used for testing:
+	// the certificate is verified against the system certificate authorities
+	// and the trusted CA bundle configured for the operator otherwise.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	SkipTLSVerify bool `json:"skipTLSVerify,omitempty"`
 }
 
 type ExternalDNSInfobloxProviderOptions struct {
@@ -357,10 +801,63 @@ type ExternalDNSInfobloxProviderOptions struct {
 	WAPIPort int `json:"wapiPort"`
 
 	// WAPIVersion is the version of the Infoblox WAPI.
+	// It has to be of the form "<major>.<minor>" or
+	// "<major>.<minor>.<patch>", e.g. "2.3.1".
 	//
 	// +kubebuilder:validation:Required
 	// +required
 	WAPIVersion string `json:"wapiVersion"`
+
+	// view is the DNS view in which ExternalDNS
+	// manages the records. The default view
+	// is used if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	View string `json:"view,omitempty"`
+
+	// createPTR instructs ExternalDNS to create
+	// the PTR records for the A records it manages.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	CreatePTR bool `json:"createPTR,omitempty"`
+
+	// sslVerify defines whether ExternalDNS verifies
+	// the TLS certificate of the grid host.
+	// The certificate is verified against the system
+	// certificate authorities and the trusted CA bundle
+	// configured for the operator.
+	//
+	// The certificate is verified if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	SSLVerify *bool `json:"sslVerify,omitempty"`
+
+	// maxResults is the maximum number of the objects
+	// returned by a single WAPI request. It's used to
+	// page through the large sets of the records.
+	// The WAPI default is used if this field is not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxResults *int32 `json:"maxResults,omitempty"`
+
+	// fqdnRegex is the regular expression used to filter
+	// the zones retrieved from the grid by their FQDN.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	FQDNRegex string `json:"fqdnRegex,omitempty"`
+
+	// nameRegex is the regular expression used to filter
+	// the records retrieved from the grid by their name.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	NameRegex string `json:"nameRegex,omitempty"`
 }
 
 // SecretReference contains the information to let you locate the desired secret.
@@ -373,6 +870,17 @@ type SecretReference struct {
 	Name string `json:"name"`
 }
 
+// ConfigMapReference contains the information to let you locate the desired configmap.
+// ConfigMap is required to be in the operator namespace.
+type ConfigMapReference struct {
+	// Name is the name of the configmap.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
 // +kubebuilder:validation:Enum=AWS;GCP;Azure;BlueCat;Infoblox
 type ExternalDNSProviderType string
 
@@ -427,6 +935,36 @@ type ExternalDNSSource struct {
 	// +kubebuilder:validation:Optional
 	// +optional
 	FQDNTemplate []string `json:"fqdnTemplate,omitempty"`
+
+	// Namespaces is the list of namespaces from which ExternalDNS
+	// publishes the source resources.
+	//
+	// The namespaces listed here and the namespaces matching NamespaceSelector
+	// are merged. If neither is set, the source resources are published
+	// from all the namespaces.
+	//
+	// ExternalDNS watches a single namespace: the operand gets a container
+	// for every selected namespace, with its own TXT record owner.
+	// The operand is allowed to read the source resources
+	// of the selected namespaces only.
+	//
+	// The namespaces which don't exist are ignored until they are created.
+	//
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector selects the namespaces from which ExternalDNS
+	// publishes the source resources by their labels.
+	//
+	// The selected namespaces are merged with Namespaces.
+	// The operand is updated when the namespaces gain or lose the labels.
+	// An empty selector selects all the namespaces.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 }
 
 // ExternalDNSSourceUnion describes optional fields for an ExternalDNS source that should
@@ -490,6 +1028,30 @@ type ExternalDNSAWSAssumeRoleOptions struct {
 	// +kubebuilder:validation:Required
 	// +required
 	ARN string `json:"arn,omitempty"`
+
+	// externalID is the external ID which is passed
+	// to AWS STS when assuming the IAM role.
+	// It's needed when the trust policy of the role
+	// requires an external ID.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+}
+
+// ExternalDNSAWSZoneAssumeRoleOptions describes
+// the IAM role to assume for a given zone.
+type ExternalDNSAWSZoneAssumeRoleOptions struct {
+	// zoneID is the ID of the hosted zone
+	// which is managed using the IAM role.
+	// It has to be one of the zones from the Zones field.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	ZoneID string `json:"zoneID"`
+
+	ExternalDNSAWSAssumeRoleOptions `json:",inline"`
 }
 
 // ExternalDNSServiceSourceOptions describes options
@@ -585,6 +1147,87 @@ type ExternalDNSStatus struct {
 
 	// Zones is the configured zones in use by ExternalDNS.
 	Zones []string `json:"zones,omitempty"`
+
+	// DryRunPlan summarizes the changes planned by ExternalDNS
+	// when it runs in the dry run mode.
+	//
+	// +optional
+	DryRunPlan *ExternalDNSDryRunPlan `json:"dryRunPlan,omitempty"`
+
+	// ManualSync describes the last sync requested
+	// with the sync-now annotation.
+	//
+	// +optional
+	ManualSync *ExternalDNSManualSync `json:"manualSync,omitempty"`
+
+	// ManagementState is the management state
+	// applied to the ExternalDNS deployment.
+	//
+	// +optional
+	ManagementState ManagementState `json:"managementState,omitempty"`
+}
+
+// ExternalDNSManualSyncResult is the result of a manual sync.
+type ExternalDNSManualSyncResult string
+
+const (
+	// ManualSyncInProgress means that the sync is running.
+	ManualSyncInProgress ExternalDNSManualSyncResult = "InProgress"
+	// ManualSyncSucceeded means that the records were synced.
+	ManualSyncSucceeded ExternalDNSManualSyncResult = "Succeeded"
+	// ManualSyncFailed means that the sync failed.
+	ManualSyncFailed ExternalDNSManualSyncResult = "Failed"
+)
+
+// ExternalDNSManualSync describes a sync requested with the sync-now annotation.
+type ExternalDNSManualSync struct {
+	// Request is the value of the sync-now annotation
+	// which requested the sync.
+	Request string `json:"request"`
+
+	// Result is the result of the sync:
+	// InProgress, Succeeded or Failed.
+	Result ExternalDNSManualSyncResult `json:"result"`
+
+	// StartTime is the time when the sync was started.
+	//
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the sync finished.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message is the human readable details about the result.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ExternalDNSDryRunPlan describes the changes to the DNS records
+// which ExternalDNS would make if it was not in the dry run mode.
+type ExternalDNSDryRunPlan struct {
+	// Create is the number of records to be created.
+	Create int32 `json:"create"`
+
+	// Update is the number of records to be updated.
+	Update int32 `json:"update"`
+
+	// Delete is the number of records to be deleted.
+	Delete int32 `json:"delete"`
+
+	// Changes is the truncated list of the planned changes
+	// in the "<action> <name> <type>" format.
+	//
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+
+	// ConfigMapName is the name of the config map
+	// in the operand namespace which lists all the planned changes.
+	//
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
 }
 
 var (
@@ -596,6 +1239,17 @@ var (
 	ExternalDNSProviderAuthFailedReasonType = "AuthenticationFailed"
 )
 
+const (
+	// CleanupFinalizer is the finalizer which blocks the deletion of the ExternalDNS instance
+	// until its DNS records are removed. It's set when the deletion policy is Cleanup.
+	CleanupFinalizer = "externaldns.olm.openshift.io/cleanup-records"
+
+	// SyncNowAnnotation requests an immediate sync of the DNS records.
+	// A new sync is started every time the value of the annotation changes,
+	// e.g. set it to the current timestamp.
+	SyncNowAnnotation = "externaldns.olm.openshift.io/sync-now"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 //
 // ExternalDNSList contains a list of ExternalDNSes.
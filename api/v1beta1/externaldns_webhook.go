@@ -17,31 +17,173 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"reflect"
 	"regexp"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	utilErrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// azureZoneIDRegex matches the resource IDs of the public and private Azure DNS zones.
+var azureZoneIDRegex = regexp.MustCompile(`(?i)^/subscriptions/([^/]+)/resourceGroups/([^/]+)/providers/Microsoft\.Network/(dnszones|privateDnsZones)/[^/]+$`)
+
+// infobloxWAPIVersionRegex matches the WAPI versions like "2.3.1" or "2.12".
+var infobloxWAPIVersionRegex = regexp.MustCompile(`^[0-9]+\.[0-9]+(\.[0-9]+)?$`)
+
+const (
+	// AWS limits for the length of the tag keys and values, see
+	// https://docs.aws.amazon.com/Route53/latest/APIReference/API_Tag.html
+	awsTagKeyMaxLength   = 128
+	awsTagValueMaxLength = 256
+
+	// maxPerZoneZones is the maximum number of zones in the PerZone zones mode.
+	maxPerZoneZones = 10
+)
+
 // webhookLog is for logging in this package.
-var webhookLog = logf.Log.WithName("validating-webhook")
+var webhookLog = logf.Log.WithName("webhook")
 
 var isOpenShift bool
 
-func (r *ExternalDNS) SetupWebhookWithManager(mgr ctrl.Manager, openshift bool) error {
+// PlatformDetails provides the current details of the OpenShift cluster.
+// +kubebuilder:object:generate=false
+type PlatformDetails interface {
+	// PlatformStatus returns the status of the platform from the infrastructure config.
+	PlatformStatus() *configv1.PlatformStatus
+	// ClusterDNS returns the DNS configuration of the cluster.
+	ClusterDNS() *configv1.DNSSpec
+}
+
+// platformDetails provides the details of the OpenShift cluster
+// used to default the platform derived values.
+// The details are read on every request as they can change while the operator runs.
+var platformDetails PlatformDetails
+
+// externalDNSReader is the cached reader used to find
+// the instances which conflict with the validated one.
+var externalDNSReader client.Reader
+
+// routeDummyFQDNTemplate is the FQDN template which satisfies ExternalDNS
+// when the hostname annotation is ignored for Route source.
+const routeDummyFQDNTemplate = `{{""}}`
+
+func (r *ExternalDNS) SetupWebhookWithManager(mgr ctrl.Manager, openshift bool, platform PlatformDetails) error {
 	isOpenShift = openshift
+	platformDetails = platform
+	externalDNSReader = mgr.GetCache()
 	webhookLog.Info("Setting up the webhook", "IsOpenShift", isOpenShift)
 	return ctrl.NewWebhookManagedBy(mgr).For(r).Complete()
 }
 
+//+kubebuilder:webhook:path=/mutate-externaldns-olm-openshift-io-v1beta1-externaldns,mutating=true,failurePolicy=fail,sideEffects=None,groups=externaldns.olm.openshift.io,resources=externaldnses,verbs=create;update,versions=v1beta1,name=mexternaldns.kb.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Defaulter = &ExternalDNS{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// The defaults make the stored resource show the values the operand runs with.
+func (r *ExternalDNS) Default() {
+	webhookLog.Info("default", "name", r.Name)
+
+	r.defaultSource()
+	r.defaultProvider()
+	r.defaultZones()
+}
+
+func (r *ExternalDNS) defaultSource() {
+	if r.Spec.Source.HostnameAnnotationPolicy == "" {
+		r.Spec.Source.HostnameAnnotationPolicy = HostnameAnnotationPolicyIgnore
+	}
+
+	// ExternalDNS needs FQDNTemplate if the hostname annotation is ignored even for Route source.
+	// The hostname is retrieved from the route's spec, the template is just to pass the validation.
+	if r.Spec.Source.Type == SourceTypeRoute && r.Spec.Source.HostnameAnnotationPolicy == HostnameAnnotationPolicyIgnore && len(r.Spec.Source.FQDNTemplate) == 0 {
+		r.Spec.Source.FQDNTemplate = []string{routeDummyFQDNTemplate}
+	}
+}
+
+// defaultProvider sets the AWS region and the GCP project of the cluster
+// if they are not set.
+// Only new resources are defaulted: the empty values of the existing resources
+// follow the platform details when they change.
+func (r *ExternalDNS) defaultProvider() {
+	if !r.CreationTimestamp.IsZero() {
+		return
+	}
+	if !isOpenShift || platformDetails == nil {
+		return
+	}
+	platformStatus := platformDetails.PlatformStatus()
+	if platformStatus == nil {
+		return
+	}
+
+	switch r.Spec.Provider.Type {
+	case ProviderTypeAWS:
+		if platformStatus.AWS == nil || platformStatus.AWS.Region == "" {
+			return
+		}
+		if r.Spec.Provider.AWS == nil {
+			r.Spec.Provider.AWS = &ExternalDNSAWSProviderOptions{}
+		}
+		if r.Spec.Provider.AWS.Region == "" {
+			r.Spec.Provider.AWS.Region = platformStatus.AWS.Region
+		}
+	case ProviderTypeGCP:
+		if platformStatus.GCP == nil || platformStatus.GCP.ProjectID == "" {
+			return
+		}
+		if r.Spec.Provider.GCP == nil {
+			r.Spec.Provider.GCP = &ExternalDNSGCPProviderOptions{}
+		}
+		if r.Spec.Provider.GCP.Project == nil || *r.Spec.Provider.GCP.Project == "" {
+			project := platformStatus.GCP.ProjectID
+			r.Spec.Provider.GCP.Project = &project
+		}
+	}
+}
+
+// defaultZones sets the public zone of the cluster
+// if the provider matches the cluster's platform.
+// Only new resources are defaulted: an empty list of zones
+// of the existing resources means all the zones.
+func (r *ExternalDNS) defaultZones() {
+	if !r.CreationTimestamp.IsZero() || len(r.Spec.Zones) > 0 {
+		return
+	}
+	if !isOpenShift || platformDetails == nil {
+		return
+	}
+	platformStatus, clusterDNS := platformDetails.PlatformStatus(), platformDetails.ClusterDNS()
+	if platformStatus == nil || clusterDNS == nil || clusterDNS.PublicZone == nil || clusterDNS.PublicZone.ID == "" {
+		return
+	}
+
+	platformProviders := map[configv1.PlatformType]ExternalDNSProviderType{
+		configv1.AWSPlatformType:   ProviderTypeAWS,
+		configv1.AzurePlatformType: ProviderTypeAzure,
+		configv1.GCPPlatformType:   ProviderTypeGCP,
+	}
+	if provider, ok := platformProviders[platformStatus.Type]; ok && provider == r.Spec.Provider.Type {
+		r.Spec.Zones = []string{clusterDNS.PublicZone.ID}
+	}
+}
+
 // The single validating webhook is kept for all the versions, its version matches the storage version.
 // This should not be a problem since the conversion happens before the validation.
 //+kubebuilder:webhook:path=/validate-externaldns-olm-openshift-io-v1beta1-externaldns,mutating=false,failurePolicy=fail,sideEffects=None,groups=externaldns.olm.openshift.io,resources=externaldnses,verbs=create;update,versions=v1beta1,name=vexternaldns.kb.io,admissionReviewVersions={v1,v1beta1}
@@ -51,13 +193,13 @@ var _ webhook.Validator = &ExternalDNS{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *ExternalDNS) ValidateCreate() (admission.Warnings, error) {
 	webhookLog.Info("validate create", "name", r.Name)
-	return nil, r.validate(nil)
+	return r.warnings(nil), r.validate(nil)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ExternalDNS) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	webhookLog.Info("validate update", "name", r.Name)
-	return nil, r.validate(old)
+	return r.warnings(old), r.validate(old)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -70,12 +212,112 @@ func (r *ExternalDNS) validate(old runtime.Object) error {
 	return utilErrors.NewAggregate([]error{
 		r.validateFilters(),
 		r.validateSources(old),
+		r.validateSourceNamespaces(),
+		r.validateZones(),
 		r.validateHostnameAnnotationPolicy(),
 		r.validateProviderCredentials(),
 		r.validateAWSRoleARN(),
+		r.validateAWSZoneFilters(),
+		r.validateAWSRoutingPolicies(),
+		r.validateAzureOptions(),
+		r.validateGCPOptions(),
+		r.validateInfobloxOptions(),
+		r.validateBlueCatOptions(),
+		r.validateConflicts(old),
 	})
 }
 
+// validateConflicts rejects the instance if its zones and domains
+// overlap with another instance's ones.
+func (r *ExternalDNS) validateConflicts(old runtime.Object) error {
+	if externalDNSReader == nil {
+		return nil
+	}
+	if oldR, ok := old.(*ExternalDNS); ok {
+		// allow the updates which don't change the managed DNS names
+		// to not block the instances which were created before the validation was introduced
+		if reflect.DeepEqual(oldR.Spec.Zones, r.Spec.Zones) && reflect.DeepEqual(oldR.Spec.Domains, r.Spec.Domains) && oldR.Spec.Provider.Type == r.Spec.Provider.Type && oldR.Spec.DryRun == r.Spec.DryRun &&
+			reflect.DeepEqual(oldR.Spec.Source.Namespaces, r.Spec.Source.Namespaces) && reflect.DeepEqual(oldR.Spec.Source.NamespaceSelector, r.Spec.Source.NamespaceSelector) {
+			return nil
+		}
+	}
+
+	list := &ExternalDNSList{}
+	if err := externalDNSReader.List(context.TODO(), list); err != nil {
+		return fmt.Errorf("failed to list ExternalDNS instances to check for conflicts: %w", err)
+	}
+	for i := range list.Items {
+		if overlap := r.Overlap(&list.Items[i]); overlap != "" {
+			return fmt.Errorf("conflicts with ExternalDNS %q: %s are managed by both instances", list.Items[i].Name, overlap)
+		}
+	}
+	return nil
+}
+
+// possibleConflictWarnings returns the warnings about the instances
+// which may manage the same DNS records as the validated one.
+func (r *ExternalDNS) possibleConflictWarnings() admission.Warnings {
+	if externalDNSReader == nil {
+		return nil
+	}
+
+	list := &ExternalDNSList{}
+	if err := externalDNSReader.List(context.TODO(), list); err != nil {
+		webhookLog.Error(err, "failed to list ExternalDNS instances to check for possible conflicts")
+		return nil
+	}
+
+	var warnings admission.Warnings
+	for i := range list.Items {
+		if overlap := r.PossibleOverlap(&list.Items[i]); overlap != "" {
+			warnings = append(warnings, fmt.Sprintf("may conflict with ExternalDNS %q: %s may be managed by both instances", list.Items[i].Name, overlap))
+		}
+	}
+	return warnings
+}
+
+// warnings returns the warnings about the configurations
+// which may result in the unexpected DNS records or the records left behind.
+func (r *ExternalDNS) warnings(old runtime.Object) admission.Warnings {
+	var warnings admission.Warnings
+
+	if r.Spec.Source.HostnameAnnotationPolicy == HostnameAnnotationPolicyAllow {
+		warnings = append(warnings, `"hostnameAnnotation" is "Allow": any user allowed to annotate the source resources can publish arbitrary DNS records in the managed zones`)
+	}
+
+	if len(r.Spec.Zones) == 0 {
+		warnings = append(warnings, `"zones" is empty: ExternalDNS manages DNS records in all the zones accessible with the given credentials`)
+	}
+
+	if oldR, ok := old.(*ExternalDNS); ok && !reflect.DeepEqual(oldR.Spec.Zones, r.Spec.Zones) {
+		warnings = append(warnings, `"zones" changed: DNS records published in the zones which are not managed anymore are left behind`)
+	}
+
+	warnings = append(warnings, r.possibleConflictWarnings()...)
+
+	for _, domain := range r.Spec.Domains {
+		if domain.FilterType == FilterTypeExclude {
+			warnings = append(warnings, `"domains" has exclude filters: DNS records published in the excluded domains are left behind`)
+			break
+		}
+	}
+
+	if r.Spec.Source.Type == SourceTypeService && r.Spec.Source.Service != nil {
+		for _, serviceType := range r.Spec.Source.Service.ServiceType {
+			if serviceType == corev1.ServiceTypeClusterIP {
+				warnings = append(warnings, `"serviceType" has "ClusterIP": the cluster internal IPs of the services are published`)
+				break
+			}
+		}
+	}
+
+	if r.Spec.Source.NamespaceSelector != nil && len(r.Spec.Source.NamespaceSelector.MatchLabels) == 0 && len(r.Spec.Source.NamespaceSelector.MatchExpressions) == 0 {
+		warnings = append(warnings, `"namespaceSelector" is empty: every namespace of the cluster gets its own ExternalDNS container`)
+	}
+
+	return warnings
+}
+
 func (r *ExternalDNS) validateSources(old runtime.Object) error {
 	if old != nil {
 		if oldR, ok := old.(*ExternalDNS); ok {
@@ -94,6 +336,30 @@ func (r *ExternalDNS) validateSources(old runtime.Object) error {
 	return nil
 }
 
+// validateZones limits the number of zones in the PerZone mode:
+// every zone runs its own container which watches the source resources.
+// The schema allows more zones for the Shared mode.
+func (r *ExternalDNS) validateZones() error {
+	if r.Spec.ZonesMode != ZonesModeShared && len(r.Spec.Zones) > maxPerZoneZones {
+		return fmt.Errorf("at most %d zones are allowed in the %q zones mode, got %d: use the %q zones mode to manage more zones", maxPerZoneZones, ZonesModePerZone, len(r.Spec.Zones), ZonesModeShared)
+	}
+	return nil
+}
+
+func (r *ExternalDNS) validateSourceNamespaces() error {
+	for _, ns := range r.Spec.Source.Namespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace %q in \"namespaces\": %s", ns, strings.Join(errs, ", "))
+		}
+	}
+	if r.Spec.Source.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(r.Spec.Source.NamespaceSelector); err != nil {
+			return fmt.Errorf(`invalid "namespaceSelector": %w`, err)
+		}
+	}
+	return nil
+}
+
 func (r *ExternalDNS) validateFilters() error {
 	for _, f := range r.Spec.Domains {
 		switch f.MatchType {
@@ -140,7 +406,7 @@ func (r *ExternalDNS) validateProviderCredentials() error {
 			return errors.New("credentials secret must be specified when provider type is AWS")
 		}
 	case ProviderTypeAzure:
-		if provider.Azure == nil || provider.Azure.ConfigFile.Name == "" {
+		if provider.Azure == nil || ((provider.Azure.ConfigFile == nil || provider.Azure.ConfigFile.Name == "") && !provider.Azure.UseManagedIdentityExtension) {
 			return errors.New("config file name must be specified when provider type is Azure")
 		}
 	case ProviderTypeGCP:
@@ -148,7 +414,7 @@ func (r *ExternalDNS) validateProviderCredentials() error {
 			return errors.New("credentials secret must be specified when provider type is GCP")
 		}
 	case ProviderTypeBlueCat:
-		if provider.BlueCat == nil || provider.BlueCat.ConfigFile.Name == "" {
+		if provider.BlueCat == nil || ((provider.BlueCat.ConfigFile == nil || provider.BlueCat.ConfigFile.Name == "") && (provider.BlueCat.Credentials == nil || provider.BlueCat.Credentials.Name == "")) {
 			return errors.New("config file name must be specified when provider type is BlueCat")
 		}
 	case ProviderTypeInfoblox:
@@ -162,9 +428,179 @@ func (r *ExternalDNS) validateProviderCredentials() error {
 func (r *ExternalDNS) validateAWSRoleARN() error {
 	// Ensure we have a valid arn if it is specified.
 	provider := r.Spec.Provider
-	if provider.AWS != nil && provider.AWS.AssumeRole != nil && !arn.IsARN(provider.AWS.AssumeRole.ARN) {
+	if provider.AWS == nil {
+		return nil
+	}
+	if provider.AWS.AssumeRole != nil && !arn.IsARN(provider.AWS.AssumeRole.ARN) {
 		return fmt.Errorf("arn %q is not a valid AWS ARN", provider.AWS.AssumeRole.ARN)
 	}
 
+	for _, zoneRole := range provider.AWS.ZoneAssumeRoles {
+		if !arn.IsARN(zoneRole.ARN) {
+			return fmt.Errorf("arn %q of zone %q is not a valid AWS ARN", zoneRole.ARN, zoneRole.ZoneID)
+		}
+		found := false
+		for _, zone := range r.Spec.Zones {
+			if zone == zoneRole.ZoneID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("zone %q of the assume role %q is not in the list of zones", zoneRole.ZoneID, zoneRole.ARN)
+		}
+	}
+
+	return nil
+}
+
+func (r *ExternalDNS) validateAWSZoneFilters() error {
+	provider := r.Spec.Provider
+	if provider.AWS == nil {
+		return nil
+	}
+
+	for _, tag := range provider.AWS.ZoneTags {
+		key, value, _ := strings.Cut(tag, "=")
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf(`zone tag %q must be of the form "key=value" or "key" with a non empty key`, tag)
+		}
+		if len(key) > awsTagKeyMaxLength {
+			return fmt.Errorf("zone tag %q has a key longer than %d characters", tag, awsTagKeyMaxLength)
+		}
+		if len(value) > awsTagValueMaxLength {
+			return fmt.Errorf("zone tag %q has a value longer than %d characters", tag, awsTagValueMaxLength)
+		}
+	}
+
+	if provider.AWS.ZonesCacheDuration != nil && provider.AWS.ZonesCacheDuration.Duration < 0 {
+		return fmt.Errorf("zones cache duration %q must not be negative", provider.AWS.ZonesCacheDuration.Duration)
+	}
+
+	return nil
+}
+
+func (r *ExternalDNS) validateAWSRoutingPolicies() error {
+	provider := r.Spec.Provider
+	if provider.AWS == nil || provider.AWS.RoutingPolicies == nil {
+		return nil
+	}
+
+	setIdentifierAllowed := false
+	for _, policy := range provider.AWS.RoutingPolicies.Allowed {
+		if policy == AWSRoutingPolicySetIdentifier {
+			setIdentifierAllowed = true
+		}
+	}
+
+	for _, policy := range provider.AWS.RoutingPolicies.Allowed {
+		if policy != AWSRoutingPolicySetIdentifier && !setIdentifierAllowed {
+			return fmt.Errorf("routing policy %q requires %q routing policy to be allowed", policy, AWSRoutingPolicySetIdentifier)
+		}
+	}
+
+	return nil
+}
+
+func (r *ExternalDNS) validateAzureOptions() error {
+	provider := r.Spec.Provider
+	if provider.Type != ProviderTypeAzure || provider.Azure == nil {
+		return nil
+	}
+	azure := provider.Azure
+
+	configFileSet := azure.ConfigFile != nil && azure.ConfigFile.Name != ""
+	structuredOptionsSet := azure.SubscriptionID != "" || azure.ResourceGroup != "" || azure.TenantID != "" ||
+		azure.UseManagedIdentityExtension || azure.UserAssignedIdentityClientID != ""
+	if configFileSet && structuredOptionsSet {
+		return errors.New("config file cannot be specified together with the other Azure options")
+	}
+
+	if azure.UserAssignedIdentityClientID != "" && !azure.UseManagedIdentityExtension {
+		return errors.New(`"userAssignedIdentityClientID" can only be specified when "useManagedIdentityExtension" is true`)
+	}
+
+	if !isOpenShift && !configFileSet && (azure.SubscriptionID == "" || azure.ResourceGroup == "") {
+		// no platform credentials to take the missing values from
+		return errors.New(`"subscriptionID" and "resourceGroup" must be specified when the config file is not specified`)
+	}
+
+	if azure.SubscriptionID == "" && azure.ResourceGroup == "" {
+		return nil
+	}
+	for _, zone := range r.Spec.Zones {
+		matches := azureZoneIDRegex.FindStringSubmatch(zone)
+		if matches == nil {
+			return fmt.Errorf("zone %q is not a valid Azure DNS zone resource ID", zone)
+		}
+		if azure.SubscriptionID != "" && !strings.EqualFold(matches[1], azure.SubscriptionID) {
+			return fmt.Errorf("zone %q doesn't belong to subscription %q", zone, azure.SubscriptionID)
+		}
+		if azure.ResourceGroup != "" && !strings.EqualFold(matches[2], azure.ResourceGroup) {
+			return fmt.Errorf("zone %q doesn't belong to resource group %q", zone, azure.ResourceGroup)
+		}
+	}
+
+	return nil
+}
+
+func (r *ExternalDNS) validateGCPOptions() error {
+	provider := r.Spec.Provider
+	if provider.GCP == nil {
+		return nil
+	}
+
+	if provider.GCP.BatchChangeInterval != nil && provider.GCP.BatchChangeInterval.Duration < 0 {
+		return fmt.Errorf("batch change interval %q must not be negative", provider.GCP.BatchChangeInterval.Duration)
+	}
+
+	return nil
+}
+
+func (r *ExternalDNS) validateInfobloxOptions() error {
+	provider := r.Spec.Provider
+	if provider.Infoblox == nil {
+		return nil
+	}
+	infoblox := provider.Infoblox
+
+	// empty version is reported by the provider credentials validation
+	if infoblox.WAPIVersion != "" && !infobloxWAPIVersionRegex.MatchString(infoblox.WAPIVersion) {
+		return fmt.Errorf("WAPI version %q must be of the form \"<major>.<minor>\" or \"<major>.<minor>.<patch>\"", infoblox.WAPIVersion)
+	}
+	if _, err := regexp.Compile(infoblox.FQDNRegex); err != nil {
+		return fmt.Errorf("invalid FQDN regex: %w", err)
+	}
+	if _, err := regexp.Compile(infoblox.NameRegex); err != nil {
+		return fmt.Errorf("invalid name regex: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExternalDNS) validateBlueCatOptions() error {
+	provider := r.Spec.Provider
+	if provider.BlueCat == nil {
+		return nil
+	}
+	bluecat := provider.BlueCat
+
+	structuredOptionsSet := bluecat.Credentials != nil || bluecat.GatewayHost != "" || bluecat.DNSConfiguration != "" ||
+		bluecat.DNSView != "" || bluecat.RootZone != "" || bluecat.SkipTLSVerify
+	if bluecat.ConfigFile != nil && bluecat.ConfigFile.Name != "" {
+		if structuredOptionsSet {
+			return errors.New("config file cannot be specified together with the other BlueCat options")
+		}
+		return nil
+	}
+
+	if bluecat.GatewayHost == "" || bluecat.DNSConfiguration == "" {
+		return errors.New(`"gatewayHost" and "dnsConfiguration" must be specified when the config file is not specified`)
+	}
+	gatewayURL, err := url.Parse(bluecat.GatewayHost)
+	if err != nil || (gatewayURL.Scheme != "http" && gatewayURL.Scheme != "https") || gatewayURL.Host == "" {
+		return fmt.Errorf("gateway host %q must be a valid http or https URL", bluecat.GatewayHost)
+	}
+
 	return nil
 }
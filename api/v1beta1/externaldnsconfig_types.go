@@ -0,0 +1,202 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExternalDNSConfigName is the name of the ExternalDNSConfig singleton
+// read by the operator, the resources with other names are ignored.
+const ExternalDNSConfigName = "cluster"
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:path=externaldnsconfigs,scope=Cluster,singular=externaldnsconfig
+// +kubebuilder:storageversion
+
+// ExternalDNSConfig holds the defaults for all the ExternalDNS instances of the cluster.
+// Only the resource named "cluster" is used by the operator.
+type ExternalDNSConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec is the specification of the defaults for the ExternalDNS instances.
+	Spec ExternalDNSConfigSpec `json:"spec"`
+}
+
+// ExternalDNSConfigSpec defines the defaults for the ExternalDNS instances.
+type ExternalDNSConfigSpec struct {
+	// ExternalDNSOperandConfig holds the defaults of the settings
+	// which can be overridden by every ExternalDNS instance.
+	ExternalDNSOperandConfig `json:",inline"`
+}
+
+// ExternalDNSOperandConfig holds the settings of the ExternalDNS deployment
+// which can be set for all the instances in the ExternalDNSConfig
+// and overridden by the ExternalDNS instances.
+// The unset fields fall back to the defaults of the ExternalDNSConfig,
+// then to the defaults of the operator.
+type ExternalDNSOperandConfig struct {
+	// Image is the ExternalDNS image.
+	// The image given to the operator is used if not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources are the compute resources of the ExternalDNS containers.
+	// No requests or limits are set if not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodePlacement describes the nodes the ExternalDNS pods are scheduled to.
+	// The pods are scheduled to the Linux nodes, including the control plane nodes, if not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	NodePlacement *ExternalDNSNodePlacement `json:"nodePlacement,omitempty"`
+
+	// LogLevel is the log level of ExternalDNS.
+	// Debug is used if not set. The dry run needs at least the Info level
+	// to report the planned changes.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Debug;Info;Warning;Error
+	// +optional
+	LogLevel ExternalDNSLogLevel `json:"logLevel,omitempty"`
+
+	// Policy describes which changes ExternalDNS makes to the DNS records:
+	//
+	// * Sync: the records are created, updated and deleted.
+	//
+	// * UpsertOnly: the records are created and updated, never deleted.
+	//
+	// * CreateOnly: the records are only created.
+	//
+	// Sync is used if not set.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Sync;UpsertOnly;CreateOnly
+	// +optional
+	Policy ExternalDNSPolicy `json:"policy,omitempty"`
+
+	// Registry describes the TXT records ExternalDNS uses
+	// to track the ownership of the DNS records.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Registry *ExternalDNSRegistry `json:"registry,omitempty"`
+
+	// Proxy is the HTTP proxy used by ExternalDNS to reach the DNS provider.
+	// On OpenShift, the cluster-wide proxy is used if not set,
+	// the proxy environment variables of the operator are used otherwise.
+	// An empty proxy makes ExternalDNS reach the DNS provider directly.
+	// The proxy is only used for the providers which support it.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Proxy *ExternalDNSProxy `json:"proxy,omitempty"`
+}
+
+// ExternalDNSNodePlacement describes the nodes the ExternalDNS pods are scheduled to.
+type ExternalDNSNodePlacement struct {
+	// NodeSelector is the node selector of the ExternalDNS pods.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are the tolerations of the ExternalDNS pods.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// ExternalDNSLogLevel is the log level of ExternalDNS.
+type ExternalDNSLogLevel string
+
+const (
+	ExternalDNSLogLevelDebug   ExternalDNSLogLevel = "Debug"
+	ExternalDNSLogLevelInfo    ExternalDNSLogLevel = "Info"
+	ExternalDNSLogLevelWarning ExternalDNSLogLevel = "Warning"
+	ExternalDNSLogLevelError   ExternalDNSLogLevel = "Error"
+)
+
+// ExternalDNSPolicy describes which changes ExternalDNS makes to the DNS records.
+type ExternalDNSPolicy string
+
+const (
+	ExternalDNSPolicySync       ExternalDNSPolicy = "Sync"
+	ExternalDNSPolicyUpsertOnly ExternalDNSPolicy = "UpsertOnly"
+	ExternalDNSPolicyCreateOnly ExternalDNSPolicy = "CreateOnly"
+)
+
+// ExternalDNSRegistry describes the TXT records ExternalDNS uses
+// to track the ownership of the DNS records.
+type ExternalDNSRegistry struct {
+	// TXTPrefix is the prefix of the names of the TXT records.
+	// "external-dns-" is used if not set. Changing the prefix of a running instance
+	// makes it lose the ownership of the records it created before.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	TXTPrefix string `json:"txtPrefix,omitempty"`
+}
+
+// ExternalDNSProxy is the HTTP proxy used by ExternalDNS.
+// The unset fields are not inherited from the cluster-wide proxy
+// or from the ExternalDNSConfig: the proxy is replaced as a whole.
+type ExternalDNSProxy struct {
+	// HTTPProxy is the URL of the proxy for the HTTP requests.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for the HTTPS requests.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is the comma-separated list of the hostnames, domains and CIDRs
+	// which are reached without the proxy.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// ExternalDNSConfigList contains a list of ExternalDNSConfigs.
+type ExternalDNSConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExternalDNSConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExternalDNSConfig{}, &ExternalDNSConfigList{})
+}
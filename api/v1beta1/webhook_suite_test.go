@@ -100,7 +100,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = (&ExternalDNS{}).SetupWebhookWithManager(mgr, false)
+	err = (&ExternalDNS{}).SetupWebhookWithManager(mgr, false, nil)
 	Expect(err).NotTo(HaveOccurred())
 
 	//+kubebuilder:scaffold:webhook
@@ -2,11 +2,18 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func makeExternalDNS(name string, domains []ExternalDNSDomain) *ExternalDNS {
@@ -31,6 +38,20 @@ func makeExternalDNS(name string, domains []ExternalDNSDomain) *ExternalDNS {
 	}
 }
 
+// testPlatformDetails provides the fixed platform details.
+type testPlatformDetails struct {
+	platformStatus *configv1.PlatformStatus
+	clusterDNS     *configv1.DNSSpec
+}
+
+func (d *testPlatformDetails) PlatformStatus() *configv1.PlatformStatus {
+	return d.platformStatus
+}
+
+func (d *testPlatformDetails) ClusterDNS() *configv1.DNSSpec {
+	return d.clusterDNS
+}
+
 var _ = Describe("ExternalDNS admission webhook when platform is OCP", func() {
 	BeforeEach(func() {
 		// Add any setup steps that needs to be executed before each test
@@ -107,6 +128,78 @@ var _ = Describe("ExternalDNS admission webhook when platform is OCP", func() {
 				Expect(err.Error()).Should(ContainSubstring("config file name must be specified when provider type is BlueCat"))
 			})
 		})
+
+		Context("defaulting of platform values", func() {
+			var details *testPlatformDetails
+			BeforeEach(func() {
+				details = &testPlatformDetails{
+					platformStatus: &configv1.PlatformStatus{
+						Type: configv1.AWSPlatformType,
+						AWS:  &configv1.AWSPlatformStatus{Region: "us-gov-west-1"},
+					},
+					clusterDNS: &configv1.DNSSpec{
+						PublicZone: &configv1.DNSZone{ID: "Z3URY6TWQ91KVV"},
+					},
+				}
+				platformDetails = details
+			})
+			AfterEach(func() {
+				platformDetails = nil
+			})
+			It("defaults AWS region and zones from the cluster", func() {
+				resource := makeExternalDNS("test-default-aws-platform", nil)
+				resource.Spec.Provider = ExternalDNSProvider{Type: ProviderTypeAWS}
+				err := k8sClient.Create(context.Background(), resource)
+				Expect(err).Should(Succeed())
+				Expect(resource.Spec.Provider.AWS).ShouldNot(BeNil())
+				Expect(resource.Spec.Provider.AWS.Region).Should(Equal("us-gov-west-1"))
+				Expect(resource.Spec.Zones).Should(Equal([]string{"Z3URY6TWQ91KVV"}))
+			})
+			It("keeps explicitly given AWS region and zones", func() {
+				resource := makeExternalDNS("test-explicit-aws-platform", nil)
+				resource.Spec.Provider = ExternalDNSProvider{
+					Type: ProviderTypeAWS,
+					AWS:  &ExternalDNSAWSProviderOptions{Region: "us-east-1"},
+				}
+				resource.Spec.Zones = []string{"Z1234"}
+				err := k8sClient.Create(context.Background(), resource)
+				Expect(err).Should(Succeed())
+				Expect(resource.Spec.Provider.AWS.Region).Should(Equal("us-east-1"))
+				Expect(resource.Spec.Zones).Should(Equal([]string{"Z1234"}))
+			})
+			It("doesn't default zones when provider doesn't match the platform", func() {
+				resource := makeExternalDNS("test-default-gcp-on-aws-platform", nil)
+				resource.Spec.Provider = ExternalDNSProvider{Type: ProviderTypeGCP}
+				err := k8sClient.Create(context.Background(), resource)
+				Expect(err).Should(Succeed())
+				Expect(resource.Spec.Zones).Should(BeEmpty())
+			})
+			It("defaults GCP project from the cluster", func() {
+				details.platformStatus = &configv1.PlatformStatus{
+					Type: configv1.GCPPlatformType,
+					GCP:  &configv1.GCPPlatformStatus{ProjectID: "test-project"},
+				}
+				resource := makeExternalDNS("test-default-gcp-platform", nil)
+				resource.Spec.Provider = ExternalDNSProvider{Type: ProviderTypeGCP}
+				err := k8sClient.Create(context.Background(), resource)
+				Expect(err).Should(Succeed())
+				Expect(resource.Spec.Provider.GCP).ShouldNot(BeNil())
+				Expect(resource.Spec.Provider.GCP.Project).Should(Equal(ptr.To("test-project")))
+			})
+			It("doesn't default AWS region on update", func() {
+				resource := makeExternalDNS("test-no-default-aws-update", nil)
+				resource.Spec.Provider = ExternalDNSProvider{Type: ProviderTypeAWS}
+				err := k8sClient.Create(context.Background(), resource)
+				Expect(err).Should(Succeed())
+				Expect(resource.Spec.Provider.AWS.Region).Should(Equal("us-gov-west-1"))
+
+				details.platformStatus.AWS.Region = "us-gov-east-1"
+				resource.Spec.Provider.AWS.Region = ""
+				err = k8sClient.Update(context.Background(), resource)
+				Expect(err).Should(Succeed())
+				Expect(resource.Spec.Provider.AWS.Region).Should(BeEmpty())
+			})
+		})
 	})
 
 })
@@ -194,6 +287,68 @@ var _ = Describe("ExternalDNS admission webhook", func() {
 		})
 	})
 
+	Context("resource with source namespaces", func() {
+		It("namespaces and namespace selector accepted", func() {
+			resource := makeExternalDNS("test-source-namespaces", nil)
+			resource.Spec.Source.Namespaces = []string{"tenant-a", "tenant-b"}
+			resource.Spec.Source.NamespaceSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"tenant": "c"},
+			}
+			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
+			Expect(k8sClient.Delete(context.Background(), resource)).Should(Succeed())
+		})
+		It("invalid namespace rejected", func() {
+			resource := makeExternalDNS("test-invalid-source-namespace", nil)
+			resource.Spec.Source.Namespaces = []string{"Tenant_A"}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`invalid namespace "Tenant_A" in "namespaces"`))
+		})
+		It("invalid namespace selector rejected", func() {
+			resource := makeExternalDNS("test-invalid-namespace-selector", nil)
+			resource.Spec.Source.NamespaceSelector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tenant", Operator: metav1.LabelSelectorOpIn},
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`invalid "namespaceSelector"`))
+		})
+	})
+
+	Context("resource with many zones", func() {
+		manyZones := func(count int) []string {
+			zones := []string{}
+			for i := 0; i < count; i++ {
+				zones = append(zones, fmt.Sprintf("zone-%d", i))
+			}
+			return zones
+		}
+		It("more than 10 zones in PerZone mode rejected", func() {
+			resource := makeExternalDNS("test-per-zone-many-zones", nil)
+			resource.Spec.Zones = manyZones(11)
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`at most 10 zones are allowed in the "PerZone" zones mode, got 11`))
+		})
+		It("more than 10 zones in Shared mode accepted", func() {
+			resource := makeExternalDNS("test-shared-many-zones", nil)
+			resource.Spec.ZonesMode = ZonesModeShared
+			resource.Spec.Zones = manyZones(50)
+			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
+			Expect(k8sClient.Delete(context.Background(), resource)).Should(Succeed())
+		})
+		It("more than 100 zones in Shared mode rejected", func() {
+			resource := makeExternalDNS("test-shared-too-many-zones", nil)
+			resource.Spec.ZonesMode = ZonesModeShared
+			resource.Spec.Zones = manyZones(101)
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("spec.zones"))
+		})
+	})
+
 	Context("resource with AWS provider", func() {
 		It("rejected when credential not specified", func() {
 			resource := makeExternalDNS("test-missing-aws-credentials", nil)
@@ -231,6 +386,117 @@ var _ = Describe("ExternalDNS admission webhook", func() {
 			Expect(err).ShouldNot(Succeed())
 			Expect(err.Error()).Should(ContainSubstring(`arn "arn:aws:iam:bad123456789012:role/foo" is not a valid AWS ARN`))
 		})
+		It("valid zone RoleARN", func() {
+			resource := makeExternalDNS("test-valid-zone-rolearn", nil)
+			resource.Spec.Zones = []string{"private-zone"}
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAWS,
+				AWS: &ExternalDNSAWSProviderOptions{
+					ZoneAssumeRoles: []ExternalDNSAWSZoneAssumeRoleOptions{
+						{
+							ZoneID: "private-zone",
+							ExternalDNSAWSAssumeRoleOptions: ExternalDNSAWSAssumeRoleOptions{
+								ARN:        "arn:aws:iam::123456789012:role/foo",
+								ExternalID: "external-id",
+							},
+						},
+					},
+					Credentials: SecretReference{Name: "credentials"},
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).Should(Succeed())
+		})
+		It("zone RoleARN for unknown zone rejected", func() {
+			resource := makeExternalDNS("test-unknown-zone-rolearn", nil)
+			resource.Spec.Zones = []string{"public-zone"}
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAWS,
+				AWS: &ExternalDNSAWSProviderOptions{
+					ZoneAssumeRoles: []ExternalDNSAWSZoneAssumeRoleOptions{
+						{
+							ZoneID: "private-zone",
+							ExternalDNSAWSAssumeRoleOptions: ExternalDNSAWSAssumeRoleOptions{
+								ARN: "arn:aws:iam::123456789012:role/foo",
+							},
+						},
+					},
+					Credentials: SecretReference{Name: "credentials"},
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`zone "private-zone" of the assume role "arn:aws:iam::123456789012:role/foo" is not in the list of zones`))
+		})
+		It("valid zone filters accepted", func() {
+			resource := makeExternalDNS("test-valid-zone-filters", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAWS,
+				AWS: &ExternalDNSAWSProviderOptions{
+					ZoneType:           AWSZoneTypePublic,
+					ZoneTags:           []string{"env=prod", "external-dns"},
+					ZonesCacheDuration: &metav1.Duration{Duration: time.Hour},
+					Credentials:        SecretReference{Name: "credentials"},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
+			Expect(k8sClient.Delete(context.Background(), resource)).Should(Succeed())
+		})
+		It("zone tag with empty key rejected", func() {
+			resource := makeExternalDNS("test-invalid-zone-tag", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAWS,
+				AWS: &ExternalDNSAWSProviderOptions{
+					ZoneTags:    []string{"=prod"},
+					Credentials: SecretReference{Name: "credentials"},
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`zone tag "=prod" must be of the form "key=value" or "key" with a non empty key`))
+		})
+		It("negative zones cache duration rejected", func() {
+			resource := makeExternalDNS("test-invalid-zones-cache-duration", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAWS,
+				AWS: &ExternalDNSAWSProviderOptions{
+					ZonesCacheDuration: &metav1.Duration{Duration: -time.Minute},
+					Credentials:        SecretReference{Name: "credentials"},
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`zones cache duration "-1m0s" must not be negative`))
+		})
+		It("routing policies with set identifier accepted", func() {
+			resource := makeExternalDNS("test-valid-routing-policies", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAWS,
+				AWS: &ExternalDNSAWSProviderOptions{
+					RoutingPolicies: &ExternalDNSAWSRoutingPolicyOptions{
+						Allowed: []ExternalDNSAWSRoutingPolicy{AWSRoutingPolicyFailover, AWSRoutingPolicySetIdentifier},
+					},
+					Credentials: SecretReference{Name: "credentials"},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
+			Expect(k8sClient.Delete(context.Background(), resource)).Should(Succeed())
+		})
+		It("routing policies without set identifier rejected", func() {
+			resource := makeExternalDNS("test-invalid-routing-policies", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAWS,
+				AWS: &ExternalDNSAWSProviderOptions{
+					RoutingPolicies: &ExternalDNSAWSRoutingPolicyOptions{
+						Allowed: []ExternalDNSAWSRoutingPolicy{AWSRoutingPolicyWeighted},
+					},
+					Credentials: SecretReference{Name: "credentials"},
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`routing policy "Weighted" requires "SetIdentifier" routing policy to be allowed`))
+		})
 	})
 
 	Context("resource with Azure provider", func() {
@@ -241,6 +507,47 @@ var _ = Describe("ExternalDNS admission webhook", func() {
 			Expect(err).ShouldNot(Succeed())
 			Expect(err.Error()).Should(ContainSubstring("config file name must be specified when provider type is Azure"))
 		})
+		It("managed identity with zones from the subscription and resource group accepted", func() {
+			resource := makeExternalDNS("test-azure-managed-identity", nil)
+			resource.Spec.Zones = []string{"/subscriptions/sub/resourceGroups/dns-rg/providers/Microsoft.Network/dnszones/example.com"}
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAzure,
+				Azure: &ExternalDNSAzureProviderOptions{
+					SubscriptionID:              "sub",
+					ResourceGroup:               "dns-rg",
+					UseManagedIdentityExtension: true,
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
+		})
+		It("managed identity with zone from another resource group rejected", func() {
+			resource := makeExternalDNS("test-azure-wrong-resource-group", nil)
+			resource.Spec.Zones = []string{"/subscriptions/sub/resourceGroups/other-rg/providers/Microsoft.Network/dnszones/example.com"}
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAzure,
+				Azure: &ExternalDNSAzureProviderOptions{
+					SubscriptionID:              "sub",
+					ResourceGroup:               "dns-rg",
+					UseManagedIdentityExtension: true,
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`zone "/subscriptions/sub/resourceGroups/other-rg/providers/Microsoft.Network/dnszones/example.com" doesn't belong to resource group "dns-rg"`))
+		})
+		It("config file together with the other options rejected", func() {
+			resource := makeExternalDNS("test-azure-config-file-and-options", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeAzure,
+				Azure: &ExternalDNSAzureProviderOptions{
+					ConfigFile:    &SecretReference{Name: "config"},
+					ResourceGroup: "dns-rg",
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("config file cannot be specified together with the other Azure options"))
+		})
 	})
 
 	Context("resource with GCP provider", func() {
@@ -251,6 +558,32 @@ var _ = Describe("ExternalDNS admission webhook", func() {
 			Expect(err).ShouldNot(Succeed())
 			Expect(err.Error()).Should(ContainSubstring("credentials secret must be specified when provider type is GCP"))
 		})
+		It("valid GCP options accepted", func() {
+			resource := makeExternalDNS("test-valid-gcp-options", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeGCP,
+				GCP: &ExternalDNSGCPProviderOptions{
+					Credentials:               SecretReference{Name: "credentials"},
+					ZoneVisibility:            GCPZoneVisibilityPrivate,
+					BatchChangeInterval:       &metav1.Duration{Duration: 5 * time.Second},
+					ImpersonateServiceAccount: "dns@project.iam.gserviceaccount.com",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
+		})
+		It("negative batch change interval rejected", func() {
+			resource := makeExternalDNS("test-negative-gcp-batch-interval", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeGCP,
+				GCP: &ExternalDNSGCPProviderOptions{
+					Credentials:         SecretReference{Name: "credentials"},
+					BatchChangeInterval: &metav1.Duration{Duration: -time.Second},
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`batch change interval "-1s" must not be negative`))
+		})
 	})
 
 	Context("resource with Bluecat provider", func() {
@@ -261,9 +594,89 @@ var _ = Describe("ExternalDNS admission webhook", func() {
 			Expect(err).ShouldNot(Succeed())
 			Expect(err.Error()).Should(ContainSubstring("config file name must be specified when provider type is BlueCat"))
 		})
+		It("structured options accepted", func() {
+			resource := makeExternalDNS("test-bluecat-options", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeBlueCat,
+				BlueCat: &ExternalDNSBlueCatProviderOptions{
+					Credentials:      &SecretReference{Name: "bluecat-credentials"},
+					GatewayHost:      "https://bluecatgw.example.com",
+					DNSConfiguration: "Example",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
+		})
+		It("rejected when gateway host is not a URL", func() {
+			resource := makeExternalDNS("test-bluecat-invalid-gateway", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeBlueCat,
+				BlueCat: &ExternalDNSBlueCatProviderOptions{
+					Credentials:      &SecretReference{Name: "bluecat-credentials"},
+					GatewayHost:      "bluecatgw.example.com",
+					DNSConfiguration: "Example",
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`gateway host "bluecatgw.example.com" must be a valid http or https URL`))
+		})
+		It("rejected when config file is specified together with the other options", func() {
+			resource := makeExternalDNS("test-bluecat-config-file-and-options", nil)
+			resource.Spec.Provider = ExternalDNSProvider{
+				Type: ProviderTypeBlueCat,
+				BlueCat: &ExternalDNSBlueCatProviderOptions{
+					ConfigFile: &SecretReference{Name: "bluecat-config"},
+					RootZone:   "example.com",
+				},
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("config file cannot be specified together with the other BlueCat options"))
+		})
 	})
 
 	Context("resource with Infobox provider", func() {
+		It("valid options accepted", func() {
+			resource := makeExternalDNS("test-valid-infoblox-options", nil)
+			resource.Spec.Provider = ExternalDNSProvider{Type: ProviderTypeInfoblox, Infoblox: &ExternalDNSInfobloxProviderOptions{
+				Credentials: SecretReference{Name: "infoblox-credentials"},
+				GridHost:    "127.0.0.1",
+				WAPIPort:    443,
+				WAPIVersion: "2.12.2",
+				View:        "internal",
+				CreatePTR:   true,
+				FQDNRegex:   `^.*\.example\.com$`,
+			}}
+			Expect(k8sClient.Create(context.Background(), resource)).Should(Succeed())
+		})
+
+		It("rejected when provider WAPIVersion is malformed", func() {
+			resource := makeExternalDNS("test-malformed-infoblox-wapi-version", nil)
+			resource.Spec.Provider = ExternalDNSProvider{Type: ProviderTypeInfoblox, Infoblox: &ExternalDNSInfobloxProviderOptions{
+				Credentials: SecretReference{Name: "infoblox-credentials"},
+				GridHost:    "127.0.0.1",
+				WAPIPort:    443,
+				WAPIVersion: "v2.12",
+			}}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring(`WAPI version "v2.12" must be of the form "<major>.<minor>" or "<major>.<minor>.<patch>"`))
+		})
+
+		It("rejected when name regex is invalid", func() {
+			resource := makeExternalDNS("test-invalid-infoblox-name-regex", nil)
+			resource.Spec.Provider = ExternalDNSProvider{Type: ProviderTypeInfoblox, Infoblox: &ExternalDNSInfobloxProviderOptions{
+				Credentials: SecretReference{Name: "infoblox-credentials"},
+				GridHost:    "127.0.0.1",
+				WAPIPort:    443,
+				WAPIVersion: "2.12.2",
+				NameRegex:   "app-(",
+			}}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("invalid name regex"))
+		})
+
 		It("rejected when provider WAPIVersion not specified", func() {
 			resource := makeExternalDNS("test-missing-bluecat-config", nil)
 			resource.Spec.Provider = ExternalDNSProvider{Type: ProviderTypeInfoblox, Infoblox: &ExternalDNSInfobloxProviderOptions{
@@ -334,6 +747,189 @@ var _ = Describe("ExternalDNS admission webhook", func() {
 		})
 	})
 
+	Context("resource with conflicting zones and domains", func() {
+		includeDomain := func(name string) []ExternalDNSDomain {
+			return []ExternalDNSDomain{
+				{
+					FilterType: FilterTypeInclude,
+					ExternalDNSDomainUnion: ExternalDNSDomainUnion{
+						MatchType: DomainMatchTypeExact,
+						Name:      ptr.To(name),
+					},
+				},
+			}
+		}
+		It("rejects overlapping include domains in the same zone", func() {
+			first := makeExternalDNS("test-conflict-first", includeDomain("example.com"))
+			first.Spec.Zones = []string{"Z-CONFLICT"}
+			Expect(k8sClient.Create(context.Background(), first)).Should(Succeed())
+
+			second := makeExternalDNS("test-conflict-second", includeDomain("apps.example.com"))
+			second.Spec.Zones = []string{"Z-CONFLICT"}
+			Eventually(func() error {
+				return k8sClient.Create(context.Background(), second)
+			}).Should(MatchError(ContainSubstring(`conflicts with ExternalDNS "test-conflict-first": domains "apps.example.com" in zones "Z-CONFLICT"`)))
+		})
+		It("accepts disjoint include domains in the same zone", func() {
+			first := makeExternalDNS("test-no-conflict-first", includeDomain("foo.example.com"))
+			first.Spec.Zones = []string{"Z-NO-CONFLICT"}
+			Expect(k8sClient.Create(context.Background(), first)).Should(Succeed())
+
+			second := makeExternalDNS("test-no-conflict-second", includeDomain("bar.example.com"))
+			second.Spec.Zones = []string{"Z-NO-CONFLICT"}
+			Expect(k8sClient.Create(context.Background(), second)).Should(Succeed())
+		})
+		It("accepts overlapping include domains in the dry run mode", func() {
+			first := makeExternalDNS("test-dry-run-first", includeDomain("example.com"))
+			first.Spec.Zones = []string{"Z-DRY-RUN"}
+			Expect(k8sClient.Create(context.Background(), first)).Should(Succeed())
+
+			second := makeExternalDNS("test-dry-run-second", includeDomain("example.com"))
+			second.Spec.Zones = []string{"Z-DRY-RUN"}
+			second.Spec.DryRun = true
+			Expect(k8sClient.Create(context.Background(), second)).Should(Succeed())
+		})
+		It("accepts overlapping include domains in disjoint source namespaces", func() {
+			first := makeExternalDNS("test-namespaces-first", includeDomain("example.com"))
+			first.Spec.Zones = []string{"Z-NAMESPACES"}
+			first.Spec.Source.Namespaces = []string{"team-a"}
+			Expect(k8sClient.Create(context.Background(), first)).Should(Succeed())
+
+			second := makeExternalDNS("test-namespaces-second", includeDomain("example.com"))
+			second.Spec.Zones = []string{"Z-NAMESPACES"}
+			second.Spec.Source.Namespaces = []string{"team-b"}
+			Expect(k8sClient.Create(context.Background(), second)).Should(Succeed())
+
+			third := makeExternalDNS("test-namespaces-third", includeDomain("example.com"))
+			third.Spec.Zones = []string{"Z-NAMESPACES"}
+			third.Spec.Source.Namespaces = []string{"team-b", "team-c"}
+			Eventually(func() error {
+				return k8sClient.Create(context.Background(), third)
+			}).Should(MatchError(ContainSubstring(`conflicts with ExternalDNS "test-namespaces-second"`)))
+		})
+	})
+
+	Context("admission warnings", func() {
+		var reader client.Reader
+		BeforeEach(func() {
+			// the instances created by the other tests may conflict
+			reader = externalDNSReader
+			externalDNSReader = nil
+		})
+		AfterEach(func() {
+			externalDNSReader = reader
+		})
+		It("warns about the allowed hostname annotation", func() {
+			resource := makeExternalDNS("test-warning-hostname-allow", nil)
+			resource.Spec.Zones = []string{"Z1234"}
+			resource.Spec.Source.HostnameAnnotationPolicy = HostnameAnnotationPolicyAllow
+			warnings, err := resource.ValidateCreate()
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(ConsistOf(ContainSubstring(`"hostnameAnnotation" is "Allow"`)))
+		})
+		It("warns about the empty zones", func() {
+			resource := makeExternalDNS("test-warning-empty-zones", nil)
+			warnings, err := resource.ValidateCreate()
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(ConsistOf(ContainSubstring(`"zones" is empty`)))
+		})
+		It("warns about the changed zones", func() {
+			old := makeExternalDNS("test-warning-changed-zones", nil)
+			old.Spec.Zones = []string{"Z1234"}
+			resource := old.DeepCopy()
+			resource.Spec.Zones = []string{"Z5678"}
+			warnings, err := resource.ValidateUpdate(old)
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(ConsistOf(ContainSubstring(`"zones" changed`)))
+		})
+		It("warns about the exclude domain filters", func() {
+			resource := makeExternalDNS("test-warning-exclude-domains", []ExternalDNSDomain{
+				{
+					FilterType: FilterTypeExclude,
+					ExternalDNSDomainUnion: ExternalDNSDomainUnion{
+						MatchType: DomainMatchTypeExact,
+						Name:      ptr.To("internal.example.com"),
+					},
+				},
+			})
+			resource.Spec.Zones = []string{"Z1234"}
+			warnings, err := resource.ValidateCreate()
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(ConsistOf(ContainSubstring(`"domains" has exclude filters`)))
+		})
+		It("warns about the published ClusterIP services", func() {
+			resource := makeExternalDNS("test-warning-cluster-ip", nil)
+			resource.Spec.Zones = []string{"Z1234"}
+			resource.Spec.Source.Service = &ExternalDNSServiceSourceOptions{
+				ServiceType: []corev1.ServiceType{corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeClusterIP},
+			}
+			warnings, err := resource.ValidateCreate()
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(ConsistOf(ContainSubstring(`"serviceType" has "ClusterIP"`)))
+		})
+		It("warns about the empty namespace selector", func() {
+			resource := makeExternalDNS("test-warning-empty-namespace-selector", nil)
+			resource.Spec.Zones = []string{"Z1234"}
+			resource.Spec.Source.NamespaceSelector = &metav1.LabelSelector{}
+			warnings, err := resource.ValidateCreate()
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(ConsistOf(ContainSubstring(`"namespaceSelector" is empty`)))
+		})
+		It("warns about the instances which may manage the same zones", func() {
+			scheme := runtime.NewScheme()
+			Expect(AddToScheme(scheme)).Should(Succeed())
+			other := makeExternalDNS("test-warning-possible-conflict-other", nil)
+			externalDNSReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(other).Build()
+
+			resource := makeExternalDNS("test-warning-possible-conflict", nil)
+			resource.Spec.Zones = []string{"Z1234"}
+			warnings, err := resource.ValidateCreate()
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(ConsistOf(ContainSubstring(`may conflict with ExternalDNS "test-warning-possible-conflict-other": all domains in all the zones accessible with the same credentials`)))
+
+			resource.Spec.Provider.AWS.Credentials.Name = "other-credentials"
+			warnings, err = resource.ValidateCreate()
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(BeEmpty())
+		})
+		It("warns about the instances which may select the same source namespaces", func() {
+			scheme := runtime.NewScheme()
+			Expect(AddToScheme(scheme)).Should(Succeed())
+			other := makeExternalDNS("test-warning-namespace-selector-other", nil)
+			other.Spec.Zones = []string{"Z1234"}
+			other.Spec.Source.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+			externalDNSReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(other).Build()
+
+			resource := makeExternalDNS("test-warning-namespace-selector", nil)
+			resource.Spec.Zones = []string{"Z1234"}
+			resource.Spec.Source.Namespaces = []string{"team-b"}
+			warnings, err := resource.ValidateCreate()
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(ConsistOf(ContainSubstring(`may conflict with ExternalDNS "test-warning-namespace-selector-other": all domains in zones "Z1234" of the source namespaces selected by both instances`)))
+		})
+		It("doesn't warn about the safe configuration", func() {
+			old := makeExternalDNS("test-no-warnings", nil)
+			old.Spec.Zones = []string{"Z1234"}
+			resource := old.DeepCopy()
+			warnings, err := resource.ValidateUpdate(old)
+			Expect(err).Should(Succeed())
+			Expect(warnings).Should(BeEmpty())
+		})
+	})
+
+	Context("resource with route source", func() {
+		It("defaults the dummy fqdnTemplate when hostname annotation is ignored", func() {
+			resource := makeExternalDNS("test-default-route-fqdn-template", nil)
+			resource.Spec.Source = ExternalDNSSource{
+				ExternalDNSSourceUnion:   ExternalDNSSourceUnion{Type: SourceTypeRoute},
+				HostnameAnnotationPolicy: HostnameAnnotationPolicyIgnore,
+			}
+			err := k8sClient.Create(context.Background(), resource)
+			Expect(err).Should(Succeed())
+			Expect(resource.Spec.Source.FQDNTemplate).Should(Equal([]string{`{{""}}`}))
+		})
+	})
+
 	Context("resource with crd source", func() {
 		It("should be rejected as not implemented", func() {
 			resource := makeExternalDNS("test-crd-source", nil)
@@ -27,6 +27,21 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNS) DeepCopyInto(out *ExternalDNS) {
 	*out = *in
@@ -78,6 +93,36 @@ func (in *ExternalDNSAWSProviderOptions) DeepCopyInto(out *ExternalDNSAWSProvide
 		*out = new(ExternalDNSAWSAssumeRoleOptions)
 		**out = **in
 	}
+	if in.ZoneAssumeRoles != nil {
+		in, out := &in.ZoneAssumeRoles, &out.ZoneAssumeRoles
+		*out = make([]ExternalDNSAWSZoneAssumeRoleOptions, len(*in))
+		copy(*out, *in)
+	}
+	if in.ZoneTags != nil {
+		in, out := &in.ZoneTags, &out.ZoneTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ZonesCacheDuration != nil {
+		in, out := &in.ZonesCacheDuration, &out.ZonesCacheDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.EvaluateTargetHealth != nil {
+		in, out := &in.EvaluateTargetHealth, &out.EvaluateTargetHealth
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreferCNAME != nil {
+		in, out := &in.PreferCNAME, &out.PreferCNAME
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RoutingPolicies != nil {
+		in, out := &in.RoutingPolicies, &out.RoutingPolicies
+		*out = new(ExternalDNSAWSRoutingPolicyOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSAWSProviderOptions.
@@ -90,10 +135,50 @@ func (in *ExternalDNSAWSProviderOptions) DeepCopy() *ExternalDNSAWSProviderOptio
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSAWSRoutingPolicyOptions) DeepCopyInto(out *ExternalDNSAWSRoutingPolicyOptions) {
+	*out = *in
+	if in.Allowed != nil {
+		in, out := &in.Allowed, &out.Allowed
+		*out = make([]ExternalDNSAWSRoutingPolicy, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSAWSRoutingPolicyOptions.
+func (in *ExternalDNSAWSRoutingPolicyOptions) DeepCopy() *ExternalDNSAWSRoutingPolicyOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSAWSRoutingPolicyOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSAWSZoneAssumeRoleOptions) DeepCopyInto(out *ExternalDNSAWSZoneAssumeRoleOptions) {
+	*out = *in
+	out.ExternalDNSAWSAssumeRoleOptions = in.ExternalDNSAWSAssumeRoleOptions
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSAWSZoneAssumeRoleOptions.
+func (in *ExternalDNSAWSZoneAssumeRoleOptions) DeepCopy() *ExternalDNSAWSZoneAssumeRoleOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSAWSZoneAssumeRoleOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSAzureProviderOptions) DeepCopyInto(out *ExternalDNSAzureProviderOptions) {
 	*out = *in
-	out.ConfigFile = in.ConfigFile
+	if in.ConfigFile != nil {
+		in, out := &in.ConfigFile, &out.ConfigFile
+		*out = new(SecretReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSAzureProviderOptions.
@@ -109,7 +194,16 @@ func (in *ExternalDNSAzureProviderOptions) DeepCopy() *ExternalDNSAzureProviderO
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSBlueCatProviderOptions) DeepCopyInto(out *ExternalDNSBlueCatProviderOptions) {
 	*out = *in
-	out.ConfigFile = in.ConfigFile
+	if in.ConfigFile != nil {
+		in, out := &in.ConfigFile, &out.ConfigFile
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(SecretReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSBlueCatProviderOptions.
@@ -142,6 +236,80 @@ func (in *ExternalDNSCRDSourceOptions) DeepCopy() *ExternalDNSCRDSourceOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSConfig) DeepCopyInto(out *ExternalDNSConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSConfig.
+func (in *ExternalDNSConfig) DeepCopy() *ExternalDNSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExternalDNSConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSConfigList) DeepCopyInto(out *ExternalDNSConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ExternalDNSConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSConfigList.
+func (in *ExternalDNSConfigList) DeepCopy() *ExternalDNSConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExternalDNSConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSConfigSpec) DeepCopyInto(out *ExternalDNSConfigSpec) {
+	*out = *in
+	in.ExternalDNSOperandConfig.DeepCopyInto(&out.ExternalDNSOperandConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSConfigSpec.
+func (in *ExternalDNSConfigSpec) DeepCopy() *ExternalDNSConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSDomain) DeepCopyInto(out *ExternalDNSDomain) {
 	*out = *in
@@ -183,6 +351,26 @@ func (in *ExternalDNSDomainUnion) DeepCopy() *ExternalDNSDomainUnion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSDryRunPlan) DeepCopyInto(out *ExternalDNSDryRunPlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSDryRunPlan.
+func (in *ExternalDNSDryRunPlan) DeepCopy() *ExternalDNSDryRunPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSDryRunPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSGCPProviderOptions) DeepCopyInto(out *ExternalDNSGCPProviderOptions) {
 	*out = *in
@@ -192,6 +380,16 @@ func (in *ExternalDNSGCPProviderOptions) DeepCopyInto(out *ExternalDNSGCPProvide
 		**out = **in
 	}
 	out.Credentials = in.Credentials
+	if in.BatchChangeSize != nil {
+		in, out := &in.BatchChangeSize, &out.BatchChangeSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BatchChangeInterval != nil {
+		in, out := &in.BatchChangeInterval, &out.BatchChangeInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSGCPProviderOptions.
@@ -208,6 +406,16 @@ func (in *ExternalDNSGCPProviderOptions) DeepCopy() *ExternalDNSGCPProviderOptio
 func (in *ExternalDNSInfobloxProviderOptions) DeepCopyInto(out *ExternalDNSInfobloxProviderOptions) {
 	*out = *in
 	out.Credentials = in.Credentials
+	if in.SSLVerify != nil {
+		in, out := &in.SSLVerify, &out.SSLVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxResults != nil {
+		in, out := &in.MaxResults, &out.MaxResults
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSInfobloxProviderOptions.
@@ -252,6 +460,58 @@ func (in *ExternalDNSList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSManualSync) DeepCopyInto(out *ExternalDNSManualSync) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSManualSync.
+func (in *ExternalDNSManualSync) DeepCopy() *ExternalDNSManualSync {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSManualSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSNodePlacement) DeepCopyInto(out *ExternalDNSNodePlacement) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSNodePlacement.
+func (in *ExternalDNSNodePlacement) DeepCopy() *ExternalDNSNodePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSNodePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSOpenShiftRouteOptions) DeepCopyInto(out *ExternalDNSOpenShiftRouteOptions) {
 	*out = *in
@@ -267,6 +527,41 @@ func (in *ExternalDNSOpenShiftRouteOptions) DeepCopy() *ExternalDNSOpenShiftRout
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSOperandConfig) DeepCopyInto(out *ExternalDNSOperandConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodePlacement != nil {
+		in, out := &in.NodePlacement, &out.NodePlacement
+		*out = new(ExternalDNSNodePlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(ExternalDNSRegistry)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ExternalDNSProxy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSOperandConfig.
+func (in *ExternalDNSOperandConfig) DeepCopy() *ExternalDNSOperandConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSOperandConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSProvider) DeepCopyInto(out *ExternalDNSProvider) {
 	*out = *in
@@ -283,16 +578,21 @@ func (in *ExternalDNSProvider) DeepCopyInto(out *ExternalDNSProvider) {
 	if in.Azure != nil {
 		in, out := &in.Azure, &out.Azure
 		*out = new(ExternalDNSAzureProviderOptions)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.BlueCat != nil {
 		in, out := &in.BlueCat, &out.BlueCat
 		*out = new(ExternalDNSBlueCatProviderOptions)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Infoblox != nil {
 		in, out := &in.Infoblox, &out.Infoblox
 		*out = new(ExternalDNSInfobloxProviderOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustedCA != nil {
+		in, out := &in.TrustedCA, &out.TrustedCA
+		*out = new(ConfigMapReference)
 		**out = **in
 	}
 }
@@ -307,6 +607,36 @@ func (in *ExternalDNSProvider) DeepCopy() *ExternalDNSProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSProxy) DeepCopyInto(out *ExternalDNSProxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSProxy.
+func (in *ExternalDNSProxy) DeepCopy() *ExternalDNSProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSRegistry) DeepCopyInto(out *ExternalDNSRegistry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSRegistry.
+func (in *ExternalDNSRegistry) DeepCopy() *ExternalDNSRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNSServiceSourceOptions) DeepCopyInto(out *ExternalDNSServiceSourceOptions) {
 	*out = *in
@@ -336,6 +666,16 @@ func (in *ExternalDNSSource) DeepCopyInto(out *ExternalDNSSource) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSSource.
@@ -395,6 +735,7 @@ func (in *ExternalDNSSpec) DeepCopyInto(out *ExternalDNSSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	in.ExternalDNSOperandConfig.DeepCopyInto(&out.ExternalDNSOperandConfig)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSSpec.
@@ -422,6 +763,16 @@ func (in *ExternalDNSStatus) DeepCopyInto(out *ExternalDNSStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DryRunPlan != nil {
+		in, out := &in.DryRunPlan, &out.DryRunPlan
+		*out = new(ExternalDNSDryRunPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManualSync != nil {
+		in, out := &in.ManualSync, &out.ManualSync
+		*out = new(ExternalDNSManualSync)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSStatus.
@@ -1,19 +1,23 @@
 #!/usr/bin/env bash
 
-# Meant to secure the communication between the API and the validating webhook endpoint
+# Meant to secure the communication between the API and the webhook endpoints
 # using OpenShift's service serving certificate
 
 set -e
 
 usage() {
   cat <<EOF
-Make the service serving certificates and add the CA bundle to the validating webhook's client config.
+Make the service serving certificates and add the CA bundle to the webhook's client config.
 usage: ${0} [OPTIONS]
 The following flags are required.
     --namespace        Namespace where webhook service resides.
     --service          Service name of webhook.
     --secret           Secret name for CA certificate and server certificate/key pair.
     --webhook          Webhook config name.
+
+The following flags are optional.
+    --webhook-kind     Webhook kind, either MutatingWebhookConfiguration or
+                       ValidatingWebhookConfiguration (defaults to ValidatingWebhookConfiguration)
 EOF
   exit 1
 }
@@ -28,6 +32,10 @@ while [ $# -gt 0 ]; do
           webhook="$2"
           shift
           ;;
+      --webhook-kind)
+          kind="$2"
+          shift
+          ;;
       --secret)
           secret="$2"
           shift
@@ -54,4 +62,4 @@ if [ ! -x "$(command -v oc)" ]; then
 fi
 
 oc -n "${namespace}" annotate service "${service}" "service.beta.openshift.io/serving-cert-secret-name=${secret}" --overwrite=true
-oc annotate validatingwebhookconfigurations "${webhook}" "service.beta.openshift.io/inject-cabundle=true" --overwrite=true
+oc annotate "${kind:-validatingwebhookconfiguration}" "${webhook}" "service.beta.openshift.io/inject-cabundle=true" --overwrite=true
@@ -50,6 +50,7 @@ func main() {
 	flag.IntVar(&opCfg.RequeuePeriodSeconds, "requeue-period", operatorconfig.DefaultRequeuePeriodSeconds, "Requeue period for a failed reconciliation (in seconds).")
 	flag.BoolVar(&opCfg.EnableLeaderElection, "leader-elect", operatorconfig.DefaultEnableLeaderElection, "Enable leader election for controller manager to ensure there is only one active controller manager.")
 	flag.BoolVar(&opCfg.WebhookDisableHTTP2, "webhook-disable-http2", false, "Disable HTTP/2 for the webhook server.")
+	flag.BoolVar(&opCfg.EnablePreflightCheck, "enable-preflight-check", operatorconfig.DefaultEnablePreflightCheck, "Check the provider connectivity with a preflight job before rolling out ExternalDNS. Defaults to true.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -18,18 +18,15 @@ package config
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
-	configv1 "github.com/openshift/api/config/v1"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -44,13 +41,17 @@ const (
 	DefaultHealthProbeAddr         = ":9440"
 	DefaultRequeuePeriodSeconds    = 5
 	DefaultEnableLeaderElection    = false
+	DefaultEnablePreflightCheck    = true
 
-	openshiftKind              = "OpenShiftAPIServer"
-	openshiftResourceGroup     = "operator.openshift.io"
-	openshiftResourceVersion   = "v1"
-	openshiftClusterConfigName = "cluster"
+	openshiftKind            = "OpenShiftAPIServer"
+	openshiftResourceGroup   = "operator.openshift.io"
+	openshiftResourceVersion = "v1"
 )
 
+// OpenShiftClusterConfigName is the name of the OpenShift cluster configs
+// which describe the platform (infrastructure, dns).
+const OpenShiftClusterConfigName = "cluster"
+
 var DefaultCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
 
 // Config is configuration of the operator.
@@ -81,8 +82,8 @@ type Config struct {
 	// IsOpenShift is the flag indicating that the operator runs in OpenShift cluster.
 	IsOpenShift bool
 
-	// PlatformStatus is the details about the underlying platform.
-	PlatformStatus *configv1.PlatformStatus
+	// Platform holds the details about the underlying platform.
+	Platform *PlatformDetails
 
 	// TrustedCAConfigMapName is the name of the configmap containing CA bundle to be trusted by ExternalDNS containers.
 	TrustedCAConfigMapName string
@@ -99,6 +100,10 @@ type Config struct {
 
 	// WebhookDisableHTTP2 disables HTTP2 for the webhook server.
 	WebhookDisableHTTP2 bool
+
+	// EnablePreflightCheck enables the preflight job which checks
+	// the provider connectivity before rolling out ExternalDNS.
+	EnablePreflightCheck bool
 }
 
 // DetectPlatform detects the underlying platform and fills corresponding config fields
@@ -117,14 +122,14 @@ func (c *Config) DetectPlatform(kubeConfig *rest.Config) error {
 	return nil
 }
 
-// FillPlatformDetails fills the config with the platform details
-func (c *Config) FillPlatformDetails(ctx context.Context, ctrlClient ctrlclient.Client) error {
+// FillPlatformDetails fills the config with the platform details.
+// The details are kept up to date by the platform controller afterwards.
+func (c *Config) FillPlatformDetails(ctx context.Context, ctrlClient ctrlclient.Reader) error {
+	c.Platform = &PlatformDetails{}
 	if c.IsOpenShift {
-		infraConfig := &configv1.Infrastructure{}
-		if err := ctrlClient.Get(ctx, types.NamespacedName{Name: openshiftClusterConfigName}, infraConfig); err != nil {
-			return fmt.Errorf("failed to get infrastructure config: %w", err)
+		if _, err := c.Platform.Refresh(ctx, ctrlClient); err != nil {
+			return err
 		}
-		c.PlatformStatus = infraConfig.Status.PlatformStatus
 	}
 	return nil
 }
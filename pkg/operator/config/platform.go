@@ -0,0 +1,122 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	configv1 "github.com/openshift/api/config/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PlatformDetails holds the details about the underlying platform
+// which can change while the operator runs.
+// It's safe for concurrent use, a nil PlatformDetails holds no details.
+type PlatformDetails struct {
+	lock           sync.RWMutex
+	platformStatus *configv1.PlatformStatus
+	clusterDNS     *configv1.DNSSpec
+	clusterProxy   *configv1.Proxy
+}
+
+// NewPlatformDetails returns the platform details initialized with the given values.
+func NewPlatformDetails(platformStatus *configv1.PlatformStatus, clusterDNS *configv1.DNSSpec, clusterProxy *configv1.Proxy) *PlatformDetails {
+	p := &PlatformDetails{}
+	p.Update(platformStatus, clusterDNS, clusterProxy)
+	return p
+}
+
+// PlatformStatus returns a copy of the status of the platform from the infrastructure config.
+func (p *PlatformDetails) PlatformStatus() *configv1.PlatformStatus {
+	if p == nil {
+		return nil
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.platformStatus.DeepCopy()
+}
+
+// ClusterDNS returns a copy of the DNS configuration of the platform.
+func (p *PlatformDetails) ClusterDNS() *configv1.DNSSpec {
+	if p == nil {
+		return nil
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.clusterDNS.DeepCopy()
+}
+
+// ClusterProxy returns a copy of the spec and the status of the cluster-wide proxy,
+// nil if the cluster has no proxy config.
+func (p *PlatformDetails) ClusterProxy() *configv1.Proxy {
+	if p == nil {
+		return nil
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.clusterProxy.DeepCopy()
+}
+
+// Update replaces the platform details with the given values.
+// Only the spec and the status of the cluster proxy are kept.
+// Returns true if the details changed.
+func (p *PlatformDetails) Update(platformStatus *configv1.PlatformStatus, clusterDNS *configv1.DNSSpec, clusterProxy *configv1.Proxy) bool {
+	if clusterProxy != nil {
+		clusterProxy = &configv1.Proxy{Spec: clusterProxy.Spec, Status: clusterProxy.Status}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if reflect.DeepEqual(p.platformStatus, platformStatus) && reflect.DeepEqual(p.clusterDNS, clusterDNS) && reflect.DeepEqual(p.clusterProxy, clusterProxy) {
+		return false
+	}
+	p.platformStatus = platformStatus.DeepCopy()
+	p.clusterDNS = clusterDNS.DeepCopy()
+	p.clusterProxy = clusterProxy.DeepCopy()
+	return true
+}
+
+// Refresh reads the OpenShift infrastructure, dns and proxy configs and updates the platform details.
+// Returns true if the details changed.
+func (p *PlatformDetails) Refresh(ctx context.Context, ctrlClient ctrlclient.Reader) (bool, error) {
+	infraConfig := &configv1.Infrastructure{}
+	if err := ctrlClient.Get(ctx, types.NamespacedName{Name: OpenShiftClusterConfigName}, infraConfig); err != nil {
+		return false, fmt.Errorf("failed to get infrastructure config: %w", err)
+	}
+
+	dnsConfig := &configv1.DNS{}
+	if err := ctrlClient.Get(ctx, types.NamespacedName{Name: OpenShiftClusterConfigName}, dnsConfig); err != nil {
+		return false, fmt.Errorf("failed to get dns config: %w", err)
+	}
+
+	// the proxy config is optional
+	proxyConfig := &configv1.Proxy{}
+	if err := ctrlClient.Get(ctx, types.NamespacedName{Name: OpenShiftClusterConfigName}, proxyConfig); err != nil {
+		if !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get proxy config: %w", err)
+		}
+		proxyConfig = nil
+	}
+
+	return p.Update(infraConfig.Status.PlatformStatus, &dnsConfig.Spec, proxyConfig), nil
+}
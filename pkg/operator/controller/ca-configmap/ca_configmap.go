@@ -18,7 +18,7 @@ package ca_configmap
 
 import (
 	"context"
-	"reflect"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
 )
 
 // ensureTrustedCAConfigMap ensures that the source configmap has been copied to the operand namespace.
@@ -50,22 +51,14 @@ func (r *reconciler) ensureTrustedCAConfigMap(ctx context.Context) (bool, *corev
 	// desired is created from the source
 	desired := desiredTrustedCAConfigMap(source, targetName)
 
-	if !targetExists {
-		// target configmap doesn't exist, create it
-		if err := r.createTrustedCAConfigMap(ctx, desired); err != nil {
-			return false, nil, err
-		}
-		return r.currentTrustedCAConfigMap(ctx, targetName)
+	changed, err := utils.Apply(ctx, r.client, target, desired)
+	if err != nil {
+		return targetExists, target, fmt.Errorf("failed to apply trusted CA configmap %s: %w", targetName, err)
 	}
-
-	// target configmap exists, try to update it with the source data
-	if updated, err := r.updateTrustedCAConfigMap(ctx, target, desired); err != nil {
-		return true, target, err
-	} else if updated {
-		return r.currentTrustedCAConfigMap(ctx, targetName)
+	if changed {
+		r.log.Info("applied trusted CA configmap", "namespace", desired.Namespace, "name", desired.Name)
 	}
-
-	return true, target, nil
+	return true, desired, nil
 }
 
 // currentTrustedCAConfigMap returns the definition of the configmap object with the given name.
@@ -80,15 +73,6 @@ func (r *reconciler) currentTrustedCAConfigMap(ctx context.Context, name types.N
 	return true, cm, nil
 }
 
-// createTrustedCAConfigMap creates the given configmap.
-func (r *reconciler) createTrustedCAConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
-	if err := r.client.Create(ctx, cm); err != nil {
-		return err
-	}
-	r.log.Info("created trusted CA configmap", "namespace", cm.Namespace, "name", cm.Name)
-	return nil
-}
-
 // desiredTrustedCAConfigMap returns the desired target configmap.
 func desiredTrustedCAConfigMap(source *corev1.ConfigMap, targetName types.NamespacedName) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
@@ -99,25 +83,3 @@ func desiredTrustedCAConfigMap(source *corev1.ConfigMap, targetName types.Namesp
 		Data: source.Data,
 	}
 }
-
-// updateTrustedCAConfigMap updates the target configmap with the desired content if update is needed.
-// Returns a boolean indicating whether the configmap was updated, and an error value if any occurred.
-func (r *reconciler) updateTrustedCAConfigMap(ctx context.Context, current, desired *corev1.ConfigMap) (bool, error) {
-	if configMapsEqual(current, desired) {
-		return false, nil
-	}
-	updated := current.DeepCopy()
-	updated.Data = desired.Data
-	if err := r.client.Update(ctx, updated); err != nil {
-		return false, err
-	}
-	r.log.Info("updated trusted CA configmap", "namespace", updated.Namespace, "name", updated.Name)
-	return true, nil
-}
-
-// configMapsEqual compares two configMaps. Returns true if
-// the configMaps should be considered equal for the purpose of determining
-// whether an update is necessary, false otherwise.
-func configMapsEqual(a, b *corev1.ConfigMap) bool {
-	return reflect.DeepEqual(a.Data, b.Data)
-}
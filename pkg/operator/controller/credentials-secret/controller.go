@@ -19,6 +19,7 @@ package credentials_secret
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -101,7 +102,9 @@ func New(mgr manager.Manager, config Config) (controller.Controller, error) {
 					newED := e.ObjectNew.(*operatorv1beta1.ExternalDNS)
 					oldName := getExternalDNSCredentialsSecretName(oldED, config.IsOpenShift)
 					newName := getExternalDNSCredentialsSecretName(newED, config.IsOpenShift)
-					return oldName != newName || oldED.DeletionTimestamp != newED.DeletionTimestamp
+					// the generated provider config depends on the provider options
+					providerChanged := !reflect.DeepEqual(oldED.Spec.Provider, newED.Spec.Provider)
+					return oldName != newName || oldED.DeletionTimestamp != newED.DeletionTimestamp || providerChanged
 				},
 				GenericFunc: func(e event.GenericEvent) bool {
 					return hasSecret(e.Object, config.IsOpenShift)
@@ -243,10 +246,10 @@ func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	return reconcile.Result{}, nil
 }
 
-// hasSecret returns true if ExternalDNS references a secret
+// hasSecret returns true if ExternalDNS references a secret or needs one to be generated
 func hasSecret(o client.Object, isOpenShift bool) bool {
 	ed := o.(*operatorv1beta1.ExternalDNS)
-	return len(getExternalDNSCredentialsSecretName(ed, isOpenShift)) != 0
+	return len(getExternalDNSCredentialsSecretName(ed, isOpenShift)) != 0 || hasGeneratedConfig(ed)
 }
 
 // getExternalDNSCredentialsSecretName returns the name of the credentials secret which should be used as source
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -161,6 +162,23 @@ func TestReconcile(t *testing.T) {
 			expectedResult:  reconcile.Result{},
 			errExpected:     true,
 		},
+		{
+			name:            "Bootstrap Azure provider with managed identity",
+			existingObjects: []runtime.Object{testAzureExtDNSInstanceManagedIdentity()},
+			inputConfig:     testConfig(),
+			inputRequest:    testRequest(),
+			expectedResult:  reconcile.Result{},
+			expectedEvents: []test.Event{
+				{
+					EventType: watch.Added,
+					ObjType:   "secret",
+					NamespacedName: types.NamespacedName{
+						Namespace: testOperandNamespace,
+						Name:      testTargetSecretName,
+					},
+				},
+			},
+		},
 		{
 			name:            "Target secret has expected keys for GCP provider",
 			existingObjects: []runtime.Object{testGCPExtDNSInstance(), testGCPSrcSecret(), testGCPTargetSecret()},
@@ -191,6 +209,31 @@ func TestReconcile(t *testing.T) {
 			expectedResult:  reconcile.Result{},
 			errExpected:     true,
 		},
+		{
+			name:            "Bootstrap Bluecat provider with generated config",
+			existingObjects: []runtime.Object{testBlueCatExtDNSInstanceWithOptions(), testBlueCatCredentialsSrcSecret()},
+			inputConfig:     testConfig(),
+			inputRequest:    testRequest(),
+			expectedResult:  reconcile.Result{},
+			expectedEvents: []test.Event{
+				{
+					EventType: watch.Added,
+					ObjType:   "secret",
+					NamespacedName: types.NamespacedName{
+						Namespace: testOperandNamespace,
+						Name:      testTargetSecretName,
+					},
+				},
+			},
+		},
+		{
+			name:            "Source secret doesn't have expected credentials for Bluecat provider with generated config",
+			existingObjects: []runtime.Object{testBlueCatExtDNSInstanceWithOptions(), testBlueCatSrcSecret()},
+			inputConfig:     testConfig(),
+			inputRequest:    testRequest(),
+			expectedResult:  reconcile.Result{},
+			errExpected:     true,
+		},
 		{
 			name: "Bootstrap when platform is OCP and it provided the credentials secret",
 			// externaldns without credentials specified + secret provided by OCP
@@ -239,7 +282,7 @@ func TestReconcile(t *testing.T) {
 	for _, tc := range testCases {
 
 		t.Run(tc.name, func(t *testing.T) {
-			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build()
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).WithInterceptorFuncs(test.ServerSideApply).Build()
 
 			r := &reconciler{
 				client: cl,
@@ -311,6 +354,11 @@ func TestGetExternalDNSCredentialsSecretName(t *testing.T) {
 			inputExtDNS: testBlueCatExtDNSInstance(),
 			expected:    testSrcSecretName,
 		},
+		{
+			name:        "BlueCat with generated config",
+			inputExtDNS: testBlueCatExtDNSInstanceWithOptions(),
+			expected:    testSrcSecretName,
+		},
 		{
 			name:        "Infoblox",
 			inputExtDNS: testInfobloxExtDNSInstance(),
@@ -398,6 +446,11 @@ func TestHasSecret(t *testing.T) {
 			inputIsOpenShift: true,
 			expected:         true,
 		},
+		{
+			name:        "Generated Azure config",
+			inputObject: testAzureExtDNSInstanceManagedIdentity(),
+			expected:    true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -409,6 +462,145 @@ func TestHasSecret(t *testing.T) {
 	}
 }
 
+func TestDesiredAzureConfig(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputBase      map[string]interface{}
+		inputOptions   *operatorv1beta1.ExternalDNSAzureProviderOptions
+		expectedConfig string
+	}{
+		{
+			name: "Platform credentials",
+			inputBase: map[string]interface{}{
+				"aadClientId":     "client",
+				"aadClientSecret": "secret",
+				"resourceGroup":   "cluster-rg",
+				"subscriptionId":  "sub",
+				"tenantId":        "tenant",
+			},
+			expectedConfig: `{"aadClientId":"client","aadClientSecret":"secret","resourceGroup":"cluster-rg","subscriptionId":"sub","tenantId":"tenant"}`,
+		},
+		{
+			name: "Platform credentials with resource group override",
+			inputBase: map[string]interface{}{
+				"aadClientId":     "client",
+				"aadClientSecret": "secret",
+				"resourceGroup":   "cluster-rg",
+				"subscriptionId":  "sub",
+				"tenantId":        "tenant",
+			},
+			inputOptions: &operatorv1beta1.ExternalDNSAzureProviderOptions{
+				ResourceGroup: "dns-rg",
+			},
+			expectedConfig: `{"aadClientId":"client","aadClientSecret":"secret","resourceGroup":"dns-rg","subscriptionId":"sub","tenantId":"tenant"}`,
+		},
+		{
+			name: "Platform credentials replaced with managed identity",
+			inputBase: map[string]interface{}{
+				"aadClientId":     "client",
+				"aadClientSecret": "secret",
+				"resourceGroup":   "cluster-rg",
+				"subscriptionId":  "sub",
+				"tenantId":        "tenant",
+			},
+			inputOptions: &operatorv1beta1.ExternalDNSAzureProviderOptions{
+				UseManagedIdentityExtension: true,
+			},
+			expectedConfig: `{"resourceGroup":"cluster-rg","subscriptionId":"sub","tenantId":"tenant","useManagedIdentityExtension":true}`,
+		},
+		{
+			name:      "User assigned managed identity",
+			inputBase: map[string]interface{}{},
+			inputOptions: &operatorv1beta1.ExternalDNSAzureProviderOptions{
+				SubscriptionID:               "sub",
+				ResourceGroup:                "dns-rg",
+				TenantID:                     "tenant",
+				UseManagedIdentityExtension:  true,
+				UserAssignedIdentityClientID: "identity",
+			},
+			expectedConfig: `{"resourceGroup":"dns-rg","subscriptionId":"sub","tenantId":"tenant","useManagedIdentityExtension":true,"userAssignedIdentityID":"identity"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := desiredAzureConfig(tc.inputBase, tc.inputOptions)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if string(got) != tc.expectedConfig {
+				t.Errorf("expected config %s, got %s", tc.expectedConfig, got)
+			}
+		})
+	}
+}
+
+func TestDesiredGCPCredentials(t *testing.T) {
+	testCases := []struct {
+		name                string
+		inputCredentials    string
+		inputOptions        *operatorv1beta1.ExternalDNSGCPProviderOptions
+		expectedCredentials string
+		errExpected         bool
+	}{
+		{
+			name:                "No options",
+			inputCredentials:    `{"type":"service_account"}`,
+			expectedCredentials: `{"type":"service_account"}`,
+		},
+		{
+			name:                "No impersonation",
+			inputCredentials:    `{"type":"service_account"}`,
+			inputOptions:        &operatorv1beta1.ExternalDNSGCPProviderOptions{},
+			expectedCredentials: `{"type":"service_account"}`,
+		},
+		{
+			name:             "Impersonation",
+			inputCredentials: `{"type": "service_account"}`,
+			inputOptions: &operatorv1beta1.ExternalDNSGCPProviderOptions{
+				ImpersonateServiceAccount: "dns@project.iam.gserviceaccount.com",
+			},
+			expectedCredentials: `{"delegates":[],"service_account_impersonation_url":"https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/dns@project.iam.gserviceaccount.com:generateAccessToken","source_credentials":{"type":"service_account"},"type":"impersonated_service_account"}`,
+		},
+		{
+			name:             "Impersonation with invalid credentials",
+			inputCredentials: `not json`,
+			inputOptions: &operatorv1beta1.ExternalDNSGCPProviderOptions{
+				ImpersonateServiceAccount: "dns@project.iam.gserviceaccount.com",
+			},
+			errExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := desiredGCPCredentials([]byte(tc.inputCredentials), tc.inputOptions)
+			if err != nil {
+				if !tc.errExpected {
+					t.Fatalf("got unexpected error: %v", err)
+				}
+				return
+			} else if tc.errExpected {
+				t.Fatalf("error expected but not received")
+			}
+			if string(got) != tc.expectedCredentials {
+				t.Errorf("expected credentials %s, got %s", tc.expectedCredentials, got)
+			}
+		})
+	}
+}
+
+func TestDesiredBlueCatConfig(t *testing.T) {
+	got, err := desiredBlueCatConfig(testBlueCatCredentialsSrcSecret(), testBlueCatExtDNSInstanceWithOptions().Spec.Provider.BlueCat)
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+	expected := `{"gatewayHost":"https://bluecatgw.example.com","gatewayUsername":"user","gatewayPassword":"pass","dnsConfiguration":"Example","dnsView":"Internal","rootZone":"example.com","skipTLSVerify":false}`
+	if string(got) != expected {
+		t.Errorf("expected config %s, got %s", expected, got)
+	}
+}
+
 func testConfig() Config {
 	return Config{
 		SourceNamespace: testOperatorNamespace,
@@ -518,7 +710,7 @@ func testAzureExtDNSInstance() *operatorv1beta1.ExternalDNS {
 	extDNS.Spec.Provider = operatorv1beta1.ExternalDNSProvider{
 		Type: operatorv1beta1.ProviderTypeAzure,
 		Azure: &operatorv1beta1.ExternalDNSAzureProviderOptions{
-			ConfigFile: operatorv1beta1.SecretReference{
+			ConfigFile: &operatorv1beta1.SecretReference{
 				Name: testSrcSecretName,
 			},
 		},
@@ -534,6 +726,19 @@ func testAzureExtDNSInstanceNoSecret() *operatorv1beta1.ExternalDNS {
 	return extDNS
 }
 
+func testAzureExtDNSInstanceManagedIdentity() *operatorv1beta1.ExternalDNS {
+	extDNS := testExtDNSInstance()
+	extDNS.Spec.Provider = operatorv1beta1.ExternalDNSProvider{
+		Type: operatorv1beta1.ProviderTypeAzure,
+		Azure: &operatorv1beta1.ExternalDNSAzureProviderOptions{
+			SubscriptionID:              "sub",
+			ResourceGroup:               "dns-rg",
+			UseManagedIdentityExtension: true,
+		},
+	}
+	return extDNS
+}
+
 func testAzureSrcSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -561,8 +766,9 @@ func testAzureWrongSrcSecret() *corev1.Secret {
 func testAzureTargetSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      testTargetSecretName,
-			Namespace: testOperandNamespace,
+			Name:            testTargetSecretName,
+			Namespace:       testOperandNamespace,
+			OwnerReferences: testTargetOwnerReferences(),
 		},
 		Data: map[string][]byte{
 			"azure.json": []byte("val1"),
@@ -576,14 +782,44 @@ func testBlueCatExtDNSInstance() *operatorv1beta1.ExternalDNS {
 	extDNS.Spec.Provider = operatorv1beta1.ExternalDNSProvider{
 		Type: operatorv1beta1.ProviderTypeBlueCat,
 		BlueCat: &operatorv1beta1.ExternalDNSBlueCatProviderOptions{
-			ConfigFile: operatorv1beta1.SecretReference{
+			ConfigFile: &operatorv1beta1.SecretReference{
+				Name: testSrcSecretName,
+			},
+		},
+	}
+	return extDNS
+}
+
+func testBlueCatExtDNSInstanceWithOptions() *operatorv1beta1.ExternalDNS {
+	extDNS := testExtDNSInstance()
+	extDNS.Spec.Provider = operatorv1beta1.ExternalDNSProvider{
+		Type: operatorv1beta1.ProviderTypeBlueCat,
+		BlueCat: &operatorv1beta1.ExternalDNSBlueCatProviderOptions{
+			Credentials: &operatorv1beta1.SecretReference{
 				Name: testSrcSecretName,
 			},
+			GatewayHost:      "https://bluecatgw.example.com",
+			DNSConfiguration: "Example",
+			DNSView:          "Internal",
+			RootZone:         "example.com",
 		},
 	}
 	return extDNS
 }
 
+func testBlueCatCredentialsSrcSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testSrcSecretName,
+			Namespace: testOperatorNamespace,
+		},
+		Data: map[string][]byte{
+			"username": []byte("user"),
+			"password": []byte("pass"),
+		},
+	}
+}
+
 func testBlueCatSrcSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -611,8 +847,9 @@ func testBlueCatWrongSecret() *corev1.Secret {
 func testBlueCatTargetSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      testTargetSecretName,
-			Namespace: testOperandNamespace,
+			Name:            testTargetSecretName,
+			Namespace:       testOperandNamespace,
+			OwnerReferences: testTargetOwnerReferences(),
 		},
 		Data: map[string][]byte{
 			"bluecat.json": []byte("val1"),
@@ -663,8 +900,9 @@ func testInfobloxWrongSrcSecret() *corev1.Secret {
 func testInfoBloxTargetSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      testTargetSecretName,
-			Namespace: testOperandNamespace,
+			Name:            testTargetSecretName,
+			Namespace:       testOperandNamespace,
+			OwnerReferences: testTargetOwnerReferences(),
 		},
 		Data: map[string][]byte{
 			"EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME": []byte("val1"),
@@ -723,8 +961,9 @@ func testGCPWrongSecret() *corev1.Secret {
 func testGCPTargetSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      testTargetSecretName,
-			Namespace: testOperandNamespace,
+			Name:            testTargetSecretName,
+			Namespace:       testOperandNamespace,
+			OwnerReferences: testTargetOwnerReferences(),
 		},
 		Data: map[string][]byte{
 			"gcp-credentials.json": []byte("val1"),
@@ -770,11 +1009,26 @@ func testSrcSecretWhenPlatformOCP() *corev1.Secret {
 	}
 }
 
+// testTargetOwnerReferences returns the owner references
+// of the target secret created for the test ExternalDNS instance.
+func testTargetOwnerReferences() []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion:         operatorv1beta1.GroupVersion.String(),
+			Kind:               "ExternalDNS",
+			Name:               testExtDNSName,
+			Controller:         ptr.To(true),
+			BlockOwnerDeletion: ptr.To(true),
+		},
+	}
+}
+
 func testTargetSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      testTargetSecretName,
-			Namespace: testOperandNamespace,
+			Name:            testTargetSecretName,
+			Namespace:       testOperandNamespace,
+			OwnerReferences: testTargetOwnerReferences(),
 		},
 		Data: map[string][]byte{
 			"aws_access_key_id":     []byte("val1"),
@@ -787,8 +1041,9 @@ func testTargetSecret() *corev1.Secret {
 func testDriftedTargetSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      testTargetSecretName,
-			Namespace: testOperandNamespace,
+			Name:            testTargetSecretName,
+			Namespace:       testOperandNamespace,
+			OwnerReferences: testTargetOwnerReferences(),
 		},
 		Data: map[string][]byte{
 			"aws_access_key_id":     []byte("otherval1"),
@@ -801,8 +1056,9 @@ func testDriftedTargetSecret() *corev1.Secret {
 func testTargetSecretWithCredentialsKey() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      testTargetSecretName,
-			Namespace: testOperandNamespace,
+			Name:            testTargetSecretName,
+			Namespace:       testOperandNamespace,
+			OwnerReferences: testTargetOwnerReferences(),
 		},
 		Data: map[string][]byte{
 			"credentials": []byte("[default]\naws_access_key_id = val1\naws_secret_access_key = val2"),
@@ -813,8 +1069,9 @@ func testTargetSecretWithCredentialsKey() *corev1.Secret {
 func testTargetSecretWithoutCredentialsKey() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      testTargetSecretName,
-			Namespace: testOperandNamespace,
+			Name:            testTargetSecretName,
+			Namespace:       testOperandNamespace,
+			OwnerReferences: testTargetOwnerReferences(),
 		},
 		Data: map[string][]byte{
 			"aws_access_key_id":     []byte("val1"),
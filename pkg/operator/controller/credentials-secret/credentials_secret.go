@@ -22,7 +22,6 @@ import (
 
 	"encoding/json"
 	"fmt"
-	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -32,16 +31,27 @@ import (
 
 	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
 	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
+)
+
+const (
+	gcpImpersonationURLFormat = "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken"
 )
 
 // ensureCredentialsSecret ensures that the source secret has been copied to the operand namespace.
 // Returns the destination secret, a boolean if the destination secret exists, and an error when relevant.
 func (r *reconciler) ensureCredentialsSecret(ctx context.Context, sourceName types.NamespacedName, extDNS *operatorv1beta1.ExternalDNS, fromCR bool) (bool, *corev1.Secret, error) {
-	// get the source secret
-	sourceExists, source, err := r.currentCredentialsSecret(ctx, sourceName)
-	if err != nil {
-		return false, nil, err
-	} else if !sourceExists {
+	var source *corev1.Secret
+	if len(sourceName.Name) > 0 {
+		// get the source secret
+		sourceExists, sourceSecret, err := r.currentCredentialsSecret(ctx, sourceName)
+		if err != nil {
+			return false, nil, err
+		} else if !sourceExists {
+			return false, nil, nil
+		}
+		source = sourceSecret
+	} else if !hasGeneratedConfig(extDNS) {
 		return false, nil, nil
 	}
 
@@ -61,22 +71,15 @@ func (r *reconciler) ensureCredentialsSecret(ctx context.Context, sourceName typ
 	if err != nil {
 		return false, nil, err
 	}
-	if !destExists {
-		// destination secret doesn't exist, create it
-		if err := r.createCredentialsSecret(ctx, desired); err != nil {
-			return false, nil, err
-		}
-		return r.currentCredentialsSecret(ctx, destName)
-	}
 
-	// destination secret exists, try to update it with source data
-	if updated, err := r.updateCredentialsSecret(ctx, dest, desired); err != nil {
-		return true, dest, err
-	} else if updated {
-		return r.currentCredentialsSecret(ctx, destName)
+	changed, err := utils.Apply(ctx, r.client, dest, desired)
+	if err != nil {
+		return destExists, dest, fmt.Errorf("failed to apply secret %s: %w", destName, err)
 	}
-
-	return true, dest, nil
+	if changed {
+		r.log.Info("applied secret", "namespace", desired.Namespace, "name", desired.Name)
+	}
+	return true, desired, nil
 }
 
 // currentCredentialsSecret returns the definition of the secret object with the given name.
@@ -91,15 +94,6 @@ func (r *reconciler) currentCredentialsSecret(ctx context.Context, name types.Na
 	return true, secret, nil
 }
 
-// createCredentialsSecret creates the given secret using the reconciler's client.
-func (r *reconciler) createCredentialsSecret(ctx context.Context, secret *corev1.Secret) error {
-	if err := r.client.Create(ctx, secret); err != nil {
-		return err
-	}
-	r.log.Info("created secret", "namespace", secret.Namespace, "name", secret.Name)
-	return nil
-}
-
 // desiredCredentialsSecret returns the desired destination secret.
 func desiredCredentialsSecret(sourceSecret *corev1.Secret, destName types.NamespacedName, extDNS *operatorv1beta1.ExternalDNS, isOpenShift, fromCR bool) (*corev1.Secret, error) {
 	secret := &corev1.Secret{
@@ -110,21 +104,38 @@ func desiredCredentialsSecret(sourceSecret *corev1.Secret, destName types.Namesp
 		Data: map[string][]byte{},
 	}
 
+	if sourceSecret == nil {
+		// no source secret: the config is generated from the provider options
+		azureConfig, err := desiredAzureConfig(map[string]interface{}{}, extDNS.Spec.Provider.Azure)
+		if err != nil {
+			return nil, err
+		}
+		secret.Data["azure.json"] = azureConfig
+		return secret, nil
+	}
+
 	if isOpenShift && !fromCR {
 		// secret came from CCO: use CCO fields
 		switch extDNS.Spec.Provider.Type {
 		case operatorv1beta1.ProviderTypeGCP:
-			secret.Data["gcp-credentials.json"] = sourceSecret.Data["service_account.json"]
+			gcpCredentials, err := desiredGCPCredentials(sourceSecret.Data["service_account.json"], extDNS.Spec.Provider.GCP)
+			if err != nil {
+				return nil, err
+			}
+			secret.Data["gcp-credentials.json"] = gcpCredentials
 		case operatorv1beta1.ProviderTypeAzure:
-			azure_map := map[string]string{
+			azure_map := map[string]interface{}{
 				"aadClientId":     string(sourceSecret.Data["azure_client_id"]),
 				"aadClientSecret": string(sourceSecret.Data["azure_client_secret"]),
 				"resourceGroup":   string(sourceSecret.Data["azure_resourcegroup"]),
 				"subscriptionId":  string(sourceSecret.Data["azure_subscription_id"]),
 				"tenantId":        string(sourceSecret.Data["azure_tenant_id"]),
 			}
-			azureMarshalledJson, _ := json.Marshal(azure_map)
-			secret.Data["azure.json"] = azureMarshalledJson
+			azureConfig, err := desiredAzureConfig(azure_map, extDNS.Spec.Provider.Azure)
+			if err != nil {
+				return nil, err
+			}
+			secret.Data["azure.json"] = azureConfig
 		case operatorv1beta1.ProviderTypeAWS:
 			secret.Data = sourceSecret.Data
 		}
@@ -163,8 +174,29 @@ func desiredCredentialsSecret(sourceSecret *corev1.Secret, destName types.Namesp
 		if creds, exists := sourceSecret.Data["gcp-credentials.json"]; !exists || len(creds) == 0 {
 			return nil, fmt.Errorf("invalid credentials for GCP")
 		}
+		if extDNS.Spec.Provider.GCP != nil && len(extDNS.Spec.Provider.GCP.ImpersonateServiceAccount) > 0 {
+			// don't modify the source secret's data
+			secret.Data = make(map[string][]byte, len(sourceSecret.Data))
+			for k, v := range sourceSecret.Data {
+				secret.Data[k] = v
+			}
+			gcpCredentials, err := desiredGCPCredentials(sourceSecret.Data["gcp-credentials.json"], extDNS.Spec.Provider.GCP)
+			if err != nil {
+				return nil, err
+			}
+			secret.Data["gcp-credentials.json"] = gcpCredentials
+		}
 	case operatorv1beta1.ProviderTypeBlueCat:
-		if config, exists := sourceSecret.Data["bluecat.json"]; !exists || len(config) == 0 {
+		if options := extDNS.Spec.Provider.BlueCat; options != nil && (options.ConfigFile == nil || len(options.ConfigFile.Name) == 0) {
+			// source secret contains the credentials only
+			config, err := desiredBlueCatConfig(sourceSecret, options)
+			if err != nil {
+				return nil, err
+			}
+			secret.Data = map[string][]byte{
+				"bluecat.json": config,
+			}
+		} else if config, exists := sourceSecret.Data["bluecat.json"]; !exists || len(config) == 0 {
 			return nil, fmt.Errorf("invalid config for bluecat")
 		}
 	}
@@ -172,27 +204,105 @@ func desiredCredentialsSecret(sourceSecret *corev1.Secret, destName types.Namesp
 	return secret, nil
 }
 
-// updateCredentialsSecret updates the destination secret with the desired content if update is needed.
-// Returns a Boolean indicating whether the secret was updated, and an error value.
-func (r *reconciler) updateCredentialsSecret(ctx context.Context, current, desired *corev1.Secret) (bool, error) {
-	if secretsEqual(current, desired) {
-		return false, nil
+// desiredAzureConfig returns the content of azure.json built from the given base values
+// overridden by the given Azure provider options.
+func desiredAzureConfig(base map[string]interface{}, options *operatorv1beta1.ExternalDNSAzureProviderOptions) ([]byte, error) {
+	if options != nil {
+		if len(options.SubscriptionID) > 0 {
+			base["subscriptionId"] = options.SubscriptionID
+		}
+		if len(options.ResourceGroup) > 0 {
+			base["resourceGroup"] = options.ResourceGroup
+		}
+		if len(options.TenantID) > 0 {
+			base["tenantId"] = options.TenantID
+		}
+		if options.UseManagedIdentityExtension {
+			// the managed identity replaces the service principal
+			delete(base, "aadClientId")
+			delete(base, "aadClientSecret")
+			base["useManagedIdentityExtension"] = true
+			if len(options.UserAssignedIdentityClientID) > 0 {
+				base["userAssignedIdentityID"] = options.UserAssignedIdentityClientID
+			}
+		}
 	}
-	updated := current.DeepCopy()
-	updated.Data = desired.Data
-	if err := r.client.Update(ctx, updated); err != nil {
-		return false, err
+
+	config, err := json.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal azure config: %w", err)
 	}
-	r.log.Info("updated secret", "namespace", updated.Namespace, "name", updated.Name)
-	return true, nil
+	return config, nil
 }
 
-// secretsEqual compares two secrets. Returns true if
-// the secrets should be considered equal for the purpose of determining
-// whether an update is necessary, false otherwise.
-func secretsEqual(a, b *corev1.Secret) bool {
-	return reflect.DeepEqual(a.Data, b.Data)
+// desiredGCPCredentials returns the content of gcp-credentials.json.
+// The given credentials are wrapped into the impersonated service account credentials
+// if the impersonation is requested in the GCP provider options.
+func desiredGCPCredentials(credentials []byte, options *operatorv1beta1.ExternalDNSGCPProviderOptions) ([]byte, error) {
+	if options == nil || len(options.ImpersonateServiceAccount) == 0 {
+		return credentials, nil
+	}
+
+	if !json.Valid(credentials) {
+		return nil, fmt.Errorf("invalid credentials for GCP: source credentials are not valid JSON")
+	}
+	// https://google.aip.dev/auth/4111
+	impersonated := map[string]interface{}{
+		"type":                              "impersonated_service_account",
+		"service_account_impersonation_url": fmt.Sprintf(gcpImpersonationURLFormat, options.ImpersonateServiceAccount),
+		"source_credentials":                json.RawMessage(credentials),
+		"delegates":                         []string{},
+	}
+	impersonatedCredentials, err := json.Marshal(impersonated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal impersonated GCP credentials: %w", err)
+	}
+	return impersonatedCredentials, nil
+}
+
+// blueCatConfig is the content of bluecat.json expected by ExternalDNS.
+type blueCatConfig struct {
+	GatewayHost      string `json:"gatewayHost"`
+	GatewayUsername  string `json:"gatewayUsername"`
+	GatewayPassword  string `json:"gatewayPassword"`
+	DNSConfiguration string `json:"dnsConfiguration"`
+	DNSView          string `json:"dnsView,omitempty"`
+	RootZone         string `json:"rootZone,omitempty"`
+	SkipTLSVerify    bool   `json:"skipTLSVerify"`
+}
+
+// desiredBlueCatConfig returns the content of bluecat.json built
+// from the BlueCat provider options and the credentials from the given secret.
+func desiredBlueCatConfig(credentialsSecret *corev1.Secret, options *operatorv1beta1.ExternalDNSBlueCatProviderOptions) ([]byte, error) {
+	username, password := credentialsSecret.Data["username"], credentialsSecret.Data["password"]
+	if len(username) == 0 || len(password) == 0 {
+		return nil, fmt.Errorf("invalid credentials for bluecat: username or password not found")
+	}
+
+	config, err := json.Marshal(blueCatConfig{
+		GatewayHost:      options.GatewayHost,
+		GatewayUsername:  string(username),
+		GatewayPassword:  string(password),
+		DNSConfiguration: options.DNSConfiguration,
+		DNSView:          options.DNSView,
+		RootZone:         options.RootZone,
+		SkipTLSVerify:    options.SkipTLSVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bluecat config: %w", err)
+	}
+	return config, nil
 }
+
+// hasGeneratedConfig returns true if the provider config of the given ExternalDNS
+// is generated by the operator from the provider options without any source secret.
+func hasGeneratedConfig(extDNS *operatorv1beta1.ExternalDNS) bool {
+	return extDNS.Spec.Provider.Type == operatorv1beta1.ProviderTypeAzure &&
+		extDNS.Spec.Provider.Azure != nil &&
+		(extDNS.Spec.Provider.Azure.ConfigFile == nil || len(extDNS.Spec.Provider.Azure.ConfigFile.Name) == 0) &&
+		extDNS.Spec.Provider.Azure.UseManagedIdentityExtension
+}
+
 func newConfigForStaticCreds(accessKey string, accessSecret string) []byte {
 	buf := &bytes.Buffer{}
 	fmt.Fprint(buf, "[default]\n")
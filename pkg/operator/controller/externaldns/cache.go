@@ -0,0 +1,73 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+
+	controlleroperator "github.com/openshift/external-dns-operator/pkg/operator/controller"
+)
+
+// CacheByObject returns the cache configuration for the objects read by the controller
+// which are not limited to the operator's namespaces or which are numerous:
+// only the pods, the jobs and the role bindings created by the operator are cached,
+// the role bindings are cached from all the namespaces as they are created in the source namespaces,
+// the credentials requests are cached from the cloud credentials operator namespace.
+func CacheByObject(isOpenShift bool) map[client.Object]cache.ByObject {
+	byObject := map[client.Object]cache.ByObject{
+		&corev1.Pod{}:                {Label: operandSelector()},
+		&batchv1.Job{}:               {Label: operandSelector()},
+		&rbacv1.ClusterRoleBinding{}: {Label: operandSelector()},
+		&rbacv1.RoleBinding{}: {
+			Namespaces: map[string]cache.Config{
+				cache.AllNamespaces: {},
+			},
+			Label: operandSelector(),
+		},
+	}
+	if isOpenShift {
+		byObject[&cco.CredentialsRequest{}] = cache.ByObject{
+			Namespaces: map[string]cache.Config{
+				controlleroperator.CredentialsRequestNamespace: {},
+			},
+		}
+	}
+	return byObject
+}
+
+// operandSelector returns the label selector matching the pods and the jobs
+// of the deployments and the one-shot jobs created for the ExternalDNS instances,
+// as well as the role bindings of their service accounts.
+func operandSelector() labels.Selector {
+	// the values are valid label values, the requirements cannot fail
+	appName, _ := labels.NewRequirement(appNameLabel, selection.In, []string{
+		controlleroperator.ExternalDNSBaseName,
+		preflightAppName,
+		syncAppName,
+		cleanupAppName,
+	})
+	appInstance, _ := labels.NewRequirement(appInstanceLabel, selection.Exists, nil)
+	return labels.NewSelector().Add(*appName, *appInstance)
+}
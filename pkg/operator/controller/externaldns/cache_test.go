@@ -0,0 +1,86 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+)
+
+func TestOperandSelector(t *testing.T) {
+	testCases := []struct {
+		name          string
+		labels        labels.Set
+		expectedMatch bool
+	}{
+		{
+			name:          "Operand pod",
+			labels:        labels.Set{appNameLabel: "external-dns", appInstanceLabel: "test", "pod-template-hash": "abcd"},
+			expectedMatch: true,
+		},
+		{
+			name:          "Preflight job",
+			labels:        labels.Set{appNameLabel: preflightAppName, appInstanceLabel: "test"},
+			expectedMatch: true,
+		},
+		{
+			name:          "Sync job pod",
+			labels:        labels.Set{appNameLabel: syncAppName, appInstanceLabel: "test", jobNameLabel: "external-dns-sync-abcd"},
+			expectedMatch: true,
+		},
+		{
+			name:          "Cleanup job",
+			labels:        labels.Set{appNameLabel: cleanupAppName, appInstanceLabel: "test"},
+			expectedMatch: true,
+		},
+		{
+			name:   "No instance",
+			labels: labels.Set{appNameLabel: "external-dns"},
+		},
+		{
+			name:   "Other app",
+			labels: labels.Set{appNameLabel: "other", appInstanceLabel: "test"},
+		},
+		{
+			name: "No labels",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if match := operandSelector().Matches(tc.labels); match != tc.expectedMatch {
+				t.Errorf("expected match %t, got %t", tc.expectedMatch, match)
+			}
+		})
+	}
+}
+
+func TestCacheByObject(t *testing.T) {
+	for _, isOpenShift := range []bool{false, true} {
+		hasCredentialsRequest := false
+		for obj := range CacheByObject(isOpenShift) {
+			if _, ok := obj.(*cco.CredentialsRequest); ok {
+				hasCredentialsRequest = true
+			}
+		}
+		if hasCredentialsRequest != isOpenShift {
+			t.Errorf("expected the credentials requests to be cached %t on OpenShift %t, got %t", isOpenShift, isOpenShift, hasCredentialsRequest)
+		}
+	}
+}
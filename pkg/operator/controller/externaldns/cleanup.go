@@ -0,0 +1,312 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+)
+
+const (
+	cleanupAppName = "external-dns-cleanup"
+	// ownerCleanupAppName is the app of the jobs which remove the records of the released TXT owners.
+	ownerCleanupAppName = "external-dns-owner-cleanup"
+	// cleanupAnnotationFilter matches no source resource:
+	// ExternalDNS plans the deletion of all the records owned by the instance.
+	cleanupAnnotationFilter = "externaldns.olm.openshift.io/cleanup-records in (true)"
+	annotationFilterArg     = "--annotation-filter="
+	policyArgPrefix         = "--policy="
+)
+
+// ensureCleanupFinalizer adds the cleanup finalizer to the given ExternalDNS if its deletion policy is Cleanup,
+// removes it otherwise. The given ExternalDNS is updated with the result.
+func (r *reconciler) ensureCleanupFinalizer(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS) error {
+	want := externalDNS.Spec.DeletionPolicy == operatorv1beta1.DeletionPolicyCleanup
+	if want == controllerutil.ContainsFinalizer(externalDNS, operatorv1beta1.CleanupFinalizer) {
+		return nil
+	}
+
+	updated := externalDNS.DeepCopy()
+	if want {
+		controllerutil.AddFinalizer(updated, operatorv1beta1.CleanupFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(updated, operatorv1beta1.CleanupFinalizer)
+	}
+	if err := r.client.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update finalizers of externalDNS %s: %w", externalDNS.Name, err)
+	}
+	r.log.Info("updated finalizers of externalDNS", "name", externalDNS.Name, "finalizers", updated.Finalizers)
+	updated.DeepCopyInto(externalDNS)
+	return nil
+}
+
+// reconcileDeletion removes the DNS records of the given ExternalDNS being deleted
+// if its deletion policy is Cleanup, the finalizer is removed once the records are removed.
+func (r *reconciler) reconcileDeletion(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(externalDNS, operatorv1beta1.CleanupFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	if externalDNS.Spec.DeletionPolicy == operatorv1beta1.DeletionPolicyCleanup {
+		done, err := r.cleanupRecords(ctx, externalDNS)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !done {
+			// the cleanup job triggers the reconciliation once finished
+			return reconcile.Result{}, nil
+		}
+	}
+
+	// the deletion policy may have been changed to Retain to skip the cleanup
+	updated := externalDNS.DeepCopy()
+	controllerutil.RemoveFinalizer(updated, operatorv1beta1.CleanupFinalizer)
+	if err := r.client.Update(ctx, updated); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to remove the cleanup finalizer from externalDNS %s: %w", externalDNS.Name, err)
+	}
+	r.log.Info("removed the cleanup finalizer from externalDNS", "name", externalDNS.Name)
+	return reconcile.Result{}, nil
+}
+
+// cleanupRecords removes the DNS records owned by the given ExternalDNS.
+// The operand deployment is scaled down, then a job runs its containers once with an annotation filter
+// which doesn't match any source resource. Returns true once the records are removed.
+func (r *reconciler) cleanupRecords(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS) (bool, error) {
+	// the operand would recreate the removed records
+	exist, deployment, err := r.ensureExternalDNSDeploymentScaledDown(ctx, r.config.Namespace, externalDNS)
+	if err != nil {
+		return false, err
+	}
+	if !exist {
+		r.log.Info("no externalDNS deployment to clean up the records with", "name", externalDNS.Name)
+		r.recorder.Event(externalDNS, corev1.EventTypeWarning, "CleanupSkipped", "The deployment was not found, the DNS records were left intact")
+		return true, nil
+	}
+
+	desired := desiredCleanupJob(externalDNS, deployment)
+	if err := controllerutil.SetControllerReference(externalDNS, desired, r.scheme); err != nil {
+		return false, fmt.Errorf("failed to set the controller reference for cleanup job: %w", err)
+	}
+
+	current := &batchv1.Job{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, current); err != nil {
+		if !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get cleanup job %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+		if err := r.client.Create(ctx, desired); err != nil {
+			if errors.IsAlreadyExists(err) {
+				// the cache doesn't have the job created by the previous reconciliation yet
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to create cleanup job %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+		r.log.Info("created cleanup job", "namespace", desired.Namespace, "name", desired.Name)
+		r.recorder.Event(externalDNS, corev1.EventTypeNormal, "CleanupStarted", "Removing the DNS records owned by the instance")
+		return false, r.updateCleanupCondition(ctx, externalDNS, metav1.Condition{
+			Type:    ExternalDNSRecordsCleanedUpConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "CleanupInProgress",
+			Message: "The DNS records owned by the instance are being removed",
+		})
+	}
+
+	for _, cond := range current.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			r.recorder.Event(externalDNS, corev1.EventTypeNormal, "CleanupSucceeded", "The DNS records owned by the instance were removed")
+			return true, r.updateCleanupCondition(ctx, externalDNS, metav1.Condition{
+				Type:    ExternalDNSRecordsCleanedUpConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "CleanupSucceeded",
+				Message: "The DNS records owned by the instance were removed",
+			})
+		case batchv1.JobFailed:
+			message := fmt.Sprintf("The cleanup job failed (reason: %s).", cond.Reason) + r.jobFailureMessage(ctx, current)
+			r.recorder.Event(externalDNS, corev1.EventTypeWarning, "CleanupFailed", message)
+			if err := r.updateCleanupCondition(ctx, externalDNS, metav1.Condition{
+				Type:    ExternalDNSRecordsCleanedUpConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "CleanupFailed",
+				Message: message + " The cleanup is retried, set the deletion policy to Retain to skip it.",
+			}); err != nil {
+				return false, err
+			}
+			// the next reconciliation recreates the job
+			if err := r.client.Delete(ctx, current, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+				return false, fmt.Errorf("failed to delete cleanup job %s/%s: %w", current.Namespace, current.Name, err)
+			}
+			return false, fmt.Errorf("cleanup job %s/%s failed", current.Namespace, current.Name)
+		}
+	}
+
+	return false, nil
+}
+
+// desiredCleanupJob returns the job which runs the containers of the given deployment once
+// with the annotation filter which doesn't match any source resource.
+// The dry run mode is turned off: the instance may have published the records before it was turned on.
+// The sync policy is forced: the upsert-only and create-only policies don't delete any record.
+func desiredCleanupJob(externalDNS *operatorv1beta1.ExternalDNS, deployment *appsv1.Deployment) *batchv1.Job {
+	job := desiredOnceJob(externalDNS, deployment, controller.ExternalDNSCleanupJobName(externalDNS), cleanupAppName)
+	for i := range job.Spec.Template.Spec.Containers {
+		args := []string{}
+		for _, arg := range job.Spec.Template.Spec.Containers[i].Args {
+			switch {
+			case strings.HasPrefix(arg, policyArgPrefix):
+				args = append(args, policyArgPrefix+policyArgs[operatorv1beta1.ExternalDNSPolicySync])
+			// the annotation filter flag cannot be repeated
+			case !strings.HasPrefix(arg, annotationFilterArg) && arg != dryRunArg:
+				args = append(args, arg)
+			}
+		}
+		job.Spec.Template.Spec.Containers[i].Args = append(args, annotationFilterArg+cleanupAnnotationFilter)
+	}
+	return job
+}
+
+// ensureReleasedOwnersCleanup removes the DNS records of the TXT owners which are used by the current deployment
+// but not by the desired one: the owners of the namespaces which are not selected anymore,
+// the owner of the instance when it switches between all the namespaces and the selected ones.
+// No container would manage the records of the released owners anymore.
+// A job runs the containers of the released owners from the current deployment once
+// with the annotation filter which doesn't match any source resource.
+// The operand's policy is kept: the upsert-only and create-only policies don't delete the records.
+func (r *reconciler) ensureReleasedOwnersCleanup(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, current, desired *appsv1.Deployment) error {
+	released := txtOwners(current).Difference(txtOwners(desired))
+	if released.Len() == 0 {
+		return nil
+	}
+
+	job := desiredReleasedOwnersCleanupJob(externalDNS, current, released)
+	if err := controllerutil.SetControllerReference(externalDNS, job, r.scheme); err != nil {
+		return fmt.Errorf("failed to set the controller reference for released owners cleanup job: %w", err)
+	}
+
+	if err := r.deleteFinishedJobs(ctx, externalDNS, ownerCleanupAppName, job.Namespace); err != nil {
+		return err
+	}
+	if err := r.client.Create(ctx, job); err != nil {
+		if errors.IsAlreadyExists(err) {
+			// the previous reconciliation created the job but failed to roll out the deployment
+			return nil
+		}
+		return fmt.Errorf("failed to create released owners cleanup job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+	r.log.Info("created released owners cleanup job", "namespace", job.Namespace, "name", job.Name, "owners", sets.List(released))
+	return nil
+}
+
+// desiredReleasedOwnersCleanupJob returns the job which runs the containers of the given deployment
+// with the given TXT owners once with the annotation filter which doesn't match any source resource.
+// The name of the job is unique for every generation of the deployment.
+func desiredReleasedOwnersCleanupJob(externalDNS *operatorv1beta1.ExternalDNS, deployment *appsv1.Deployment, owners sets.Set[string]) *batchv1.Job {
+	name := controller.ExternalDNSReleasedOwnersCleanupJobName(externalDNS, sets.List(owners), deployment.Generation)
+	job := desiredOnceJob(externalDNS, deployment, name, ownerCleanupAppName)
+	containers := []corev1.Container{}
+	for _, container := range job.Spec.Template.Spec.Containers {
+		if !owners.Has(txtOwner(container)) {
+			continue
+		}
+		args := []string{}
+		for _, arg := range container.Args {
+			// the annotation filter flag cannot be repeated
+			if !strings.HasPrefix(arg, annotationFilterArg) {
+				args = append(args, arg)
+			}
+		}
+		container.Args = append(args, annotationFilterArg+cleanupAnnotationFilter)
+		containers = append(containers, container)
+	}
+	job.Spec.Template.Spec.Containers = containers
+	return job
+}
+
+// txtOwners returns the TXT owners of the containers of the given deployment.
+func txtOwners(deployment *appsv1.Deployment) sets.Set[string] {
+	owners := sets.New[string]()
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if owner := txtOwner(container); owner != "" {
+			owners.Insert(owner)
+		}
+	}
+	return owners
+}
+
+// txtOwner returns the TXT owner of the given container, empty if the owner flag is not set.
+func txtOwner(container corev1.Container) string {
+	for _, arg := range container.Args {
+		if strings.HasPrefix(arg, txtOwnerIDArg) {
+			return strings.TrimPrefix(arg, txtOwnerIDArg)
+		}
+	}
+	return ""
+}
+
+// deleteFinishedJobs deletes the completed and failed jobs of the given app and ExternalDNS.
+func (r *reconciler) deleteFinishedJobs(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, appName, namespace string) error {
+	jobs := &batchv1.JobList{}
+	if err := r.client.List(ctx, jobs, client.InNamespace(namespace), client.MatchingLabels{appNameLabel: appName, appInstanceLabel: externalDNS.Name}); err != nil {
+		return fmt.Errorf("failed to list %s jobs: %w", appName, err)
+	}
+	for i := range jobs.Items {
+		finished := false
+		for _, cond := range jobs.Items[i].Status.Conditions {
+			if cond.Status == corev1.ConditionTrue && (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) {
+				finished = true
+			}
+		}
+		if !finished {
+			continue
+		}
+		if err := r.client.Delete(ctx, &jobs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s job %s/%s: %w", appName, namespace, jobs.Items[i].Name, err)
+		}
+		r.log.Info("deleted job", "app", appName, "namespace", namespace, "name", jobs.Items[i].Name)
+	}
+	return nil
+}
+
+// updateCleanupCondition updates the status of the given ExternalDNS with the given cleanup condition.
+func (r *reconciler) updateCleanupCondition(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, cond metav1.Condition) error {
+	extDNSWithStatus := externalDNS.DeepCopy()
+	extDNSWithStatus.Status.Conditions = mergeConditions(extDNSWithStatus.Status.Conditions, cond)
+	if conditionsEqual(extDNSWithStatus.Status.Conditions, externalDNS.Status.Conditions) {
+		return nil
+	}
+	if err := r.client.Status().Update(ctx, extDNSWithStatus); err != nil {
+		return fmt.Errorf("failed to update externalDNS custom resource %s: %w", externalDNS.Name, err)
+	}
+	extDNSWithStatus.DeepCopyInto(externalDNS)
+	return nil
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestEnsureCleanupFinalizer(t *testing.T) {
+	testCases := []struct {
+		name              string
+		deletionPolicy    operatorv1beta1.DeletionPolicy
+		finalizers        []string
+		expectedFinalizer bool
+	}{
+		{
+			name:              "Retain by default",
+			expectedFinalizer: false,
+		},
+		{
+			name:              "Cleanup adds finalizer",
+			deletionPolicy:    operatorv1beta1.DeletionPolicyCleanup,
+			expectedFinalizer: true,
+		},
+		{
+			name:              "Retain removes finalizer",
+			deletionPolicy:    operatorv1beta1.DeletionPolicyRetain,
+			finalizers:        []string{operatorv1beta1.CleanupFinalizer},
+			expectedFinalizer: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extDNS := testExtDNSInstance()
+			extDNS.Spec.DeletionPolicy = tc.deletionPolicy
+			extDNS.Finalizers = tc.finalizers
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(extDNS).Build()
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				config: testConfig(),
+				log:    zap.New(zap.UseDevMode(true)),
+			}
+			if err := r.ensureCleanupFinalizer(context.TODO(), extDNS); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			current := &operatorv1beta1.ExternalDNS{}
+			if err := cl.Get(context.TODO(), types.NamespacedName{Name: extDNS.Name}, current); err != nil {
+				t.Fatalf("failed to get externalDNS: %v", err)
+			}
+			if got := controllerutil.ContainsFinalizer(current, operatorv1beta1.CleanupFinalizer); got != tc.expectedFinalizer {
+				t.Errorf("expected finalizer %t, got %t", tc.expectedFinalizer, got)
+			}
+			if got := controllerutil.ContainsFinalizer(extDNS, operatorv1beta1.CleanupFinalizer); got != tc.expectedFinalizer {
+				t.Errorf("expected the given instance to be updated with finalizer %t, got %t", tc.expectedFinalizer, got)
+			}
+		})
+	}
+}
+
+func TestReconcileDeletion(t *testing.T) {
+	deletingExtDNS := func(policy operatorv1beta1.DeletionPolicy) *operatorv1beta1.ExternalDNS {
+		extDNS := testExtDNSInstance()
+		extDNS.Spec.DeletionPolicy = policy
+		extDNS.Finalizers = []string{operatorv1beta1.CleanupFinalizer}
+		extDNS.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		return extDNS
+	}
+	cleanupJob := func(conds ...batchv1.JobCondition) *batchv1.Job {
+		job := desiredCleanupJob(testExtDNSInstance(), testCleanupDeployment(1))
+		job.Status.Conditions = conds
+		return job
+	}
+
+	testCases := []struct {
+		name              string
+		externalDNS       *operatorv1beta1.ExternalDNS
+		existingObjects   []runtime.Object
+		errExpected       bool
+		expectedDeleted   bool
+		expectedJob       bool
+		expectedReplicas  int32
+		expectedCondition *metav1.Condition
+	}{
+		{
+			name:            "Retain",
+			externalDNS:     deletingExtDNS(operatorv1beta1.DeletionPolicyRetain),
+			existingObjects: []runtime.Object{testCleanupDeployment(1)},
+			expectedDeleted: true,
+			// the deployment is garbage collected
+			expectedReplicas: 1,
+		},
+		{
+			name:            "Cleanup without deployment",
+			externalDNS:     deletingExtDNS(operatorv1beta1.DeletionPolicyCleanup),
+			expectedDeleted: true,
+		},
+		{
+			name:             "Cleanup started",
+			externalDNS:      deletingExtDNS(operatorv1beta1.DeletionPolicyCleanup),
+			existingObjects:  []runtime.Object{testCleanupDeployment(1)},
+			expectedJob:      true,
+			expectedReplicas: 0,
+			expectedCondition: &metav1.Condition{
+				Type:    ExternalDNSRecordsCleanedUpConditionType,
+				Status:  metav1.ConditionUnknown,
+				Reason:  "CleanupInProgress",
+				Message: "The DNS records owned by the instance are being removed",
+			},
+		},
+		{
+			name:             "Cleanup in progress",
+			externalDNS:      deletingExtDNS(operatorv1beta1.DeletionPolicyCleanup),
+			existingObjects:  []runtime.Object{testCleanupDeployment(0), cleanupJob()},
+			expectedJob:      true,
+			expectedReplicas: 0,
+		},
+		{
+			name:             "Cleanup succeeded",
+			externalDNS:      deletingExtDNS(operatorv1beta1.DeletionPolicyCleanup),
+			existingObjects:  []runtime.Object{testCleanupDeployment(0), cleanupJob(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})},
+			expectedDeleted:  true,
+			expectedJob:      true,
+			expectedReplicas: 0,
+		},
+		{
+			name:             "Cleanup failed",
+			externalDNS:      deletingExtDNS(operatorv1beta1.DeletionPolicyCleanup),
+			existingObjects:  []runtime.Object{testCleanupDeployment(0), cleanupJob(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonBackoffLimitExceeded})},
+			errExpected:      true,
+			expectedReplicas: 0,
+			expectedCondition: &metav1.Condition{
+				Type:    ExternalDNSRecordsCleanedUpConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "CleanupFailed",
+				Message: "The cleanup job failed (reason: BackoffLimitExceeded). The cleanup is retried, set the deletion policy to Retain to skip it.",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := append([]runtime.Object{tc.externalDNS}, tc.existingObjects...)
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithStatusSubresource(&operatorv1beta1.ExternalDNS{}).WithRuntimeObjects(objs...).Build()
+			r := &reconciler{
+				client:   cl,
+				scheme:   test.Scheme,
+				config:   testConfig(),
+				log:      zap.New(zap.UseDevMode(true)),
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			extDNS := &operatorv1beta1.ExternalDNS{}
+			if err := cl.Get(context.TODO(), types.NamespacedName{Name: tc.externalDNS.Name}, extDNS); err != nil {
+				t.Fatalf("failed to get externalDNS: %v", err)
+			}
+			_, err := r.reconcileDeletion(context.TODO(), extDNS)
+			if err != nil && !tc.errExpected {
+				t.Fatalf("unexpected error: %v", err)
+			} else if err == nil && tc.errExpected {
+				t.Fatalf("error expected but not received")
+			}
+
+			current := &operatorv1beta1.ExternalDNS{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Name: tc.externalDNS.Name}, current)
+			if tc.expectedDeleted {
+				if !errors.IsNotFound(err) {
+					t.Errorf("expected externalDNS to be deleted, got error: %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("failed to get externalDNS: %v", err)
+				}
+				if tc.expectedCondition != nil {
+					var cond *metav1.Condition
+					for i := range current.Status.Conditions {
+						if current.Status.Conditions[i].Type == ExternalDNSRecordsCleanedUpConditionType {
+							cond = &current.Status.Conditions[i]
+						}
+					}
+					if cond == nil {
+						t.Fatalf("expected %s condition, got none", ExternalDNSRecordsCleanedUpConditionType)
+					}
+					cond.LastTransitionTime = metav1.Time{}
+					if diff := cmp.Diff(*tc.expectedCondition, *cond); diff != "" {
+						t.Errorf("unexpected condition (-want +got):\n%s", diff)
+					}
+				}
+			}
+
+			jobs := &batchv1.JobList{}
+			if err := cl.List(context.TODO(), jobs, client.InNamespace(test.OperandNamespace)); err != nil {
+				t.Fatalf("failed to list jobs: %v", err)
+			}
+			if gotJob := len(jobs.Items) == 1; gotJob != tc.expectedJob {
+				t.Errorf("expected cleanup job %t, got %d jobs", tc.expectedJob, len(jobs.Items))
+			}
+
+			depl := &appsv1.Deployment{}
+			if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: test.OperandNamespace, Name: test.OperandName}, depl); err == nil {
+				if *depl.Spec.Replicas != tc.expectedReplicas {
+					t.Errorf("expected %d replicas, got %d", tc.expectedReplicas, *depl.Spec.Replicas)
+				}
+			}
+		})
+	}
+}
+
+func TestDesiredCleanupJob(t *testing.T) {
+	deployment := testCleanupDeployment(1)
+	deployment.Spec.Template.Spec.Containers[0].Args = append(deployment.Spec.Template.Spec.Containers[0].Args, "--annotation-filter=external-dns.alpha.kubernetes.io/aws-failover", "--dry-run")
+
+	job := desiredCleanupJob(testExtDNSInstance(), deployment)
+	expectedArgs := []string{
+		"--provider=aws",
+		"--policy=sync",
+		"--once",
+		"--annotation-filter=externaldns.olm.openshift.io/cleanup-records in (true)",
+	}
+	if diff := cmp.Diff(expectedArgs, job.Spec.Template.Spec.Containers[0].Args); diff != "" {
+		t.Errorf("unexpected container args (-want +got):\n%s", diff)
+	}
+}
+
+func TestDesiredCleanupJobPolicy(t *testing.T) {
+	for _, policy := range []string{"sync", "upsert-only", "create-only"} {
+		t.Run(policy, func(t *testing.T) {
+			deployment := testCleanupDeployment(1)
+			deployment.Spec.Template.Spec.Containers[0].Args = []string{"--provider=aws", "--policy=" + policy}
+
+			job := desiredCleanupJob(testExtDNSInstance(), deployment)
+			expectedArgs := []string{
+				"--provider=aws",
+				"--policy=sync",
+				"--once",
+				"--annotation-filter=externaldns.olm.openshift.io/cleanup-records in (true)",
+			}
+			if diff := cmp.Diff(expectedArgs, job.Spec.Template.Spec.Containers[0].Args); diff != "" {
+				t.Errorf("unexpected container args (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]string{"--provider=aws", "--policy=" + policy}, deployment.Spec.Template.Spec.Containers[0].Args); diff != "" {
+				t.Errorf("expected the deployment not to be modified (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEnsureReleasedOwnersCleanup(t *testing.T) {
+	container := func(name, owner string) corev1.Container {
+		return corev1.Container{
+			Name: name,
+			Args: []string{"--txt-owner-id=" + owner, "--provider=aws", "--policy=sync", "--namespace=" + name},
+		}
+	}
+	deployment := func(containers ...corev1.Container) *appsv1.Deployment {
+		depl := testOperandDeployment(1)
+		depl.Generation = 2
+		depl.Spec.Template.Spec.Containers = containers
+		return depl
+	}
+	current := deployment(container("tenant-a", "external-dns-test-tenant-a"), container("tenant-b", "external-dns-test-tenant-b"))
+
+	testCases := []struct {
+		name               string
+		desired            *appsv1.Deployment
+		existingObjects    []runtime.Object
+		expectedContainers []string
+	}{
+		{
+			name:    "No owner released",
+			desired: deployment(container("tenant-a", "external-dns-test-tenant-a"), container("tenant-b", "external-dns-test-tenant-b")),
+		},
+		{
+			name:               "Namespace deselected",
+			desired:            deployment(container("tenant-a", "external-dns-test-tenant-a")),
+			expectedContainers: []string{"tenant-b"},
+		},
+		{
+			name:               "Switched to all namespaces",
+			desired:            deployment(container("all", "external-dns-test")),
+			expectedContainers: []string{"tenant-a", "tenant-b"},
+		},
+		{
+			name:    "Finished job deleted",
+			desired: deployment(container("tenant-a", "external-dns-test-tenant-a")),
+			existingObjects: []runtime.Object{
+				testPreflightJob(desiredReleasedOwnersCleanupJob(testExtDNSInstance(), deployment(container("tenant-c", "external-dns-test-tenant-c")), txtOwners(deployment(container("tenant-c", "external-dns-test-tenant-c")))),
+					batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}),
+			},
+			expectedContainers: []string{"tenant-b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build()
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				config: testConfig(),
+				log:    zap.New(zap.UseDevMode(true)),
+			}
+			if err := r.ensureReleasedOwnersCleanup(context.TODO(), testExtDNSInstance(), current, tc.desired); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			jobs := &batchv1.JobList{}
+			if err := cl.List(context.TODO(), jobs, client.InNamespace(test.OperandNamespace)); err != nil {
+				t.Fatalf("failed to list jobs: %v", err)
+			}
+			if tc.expectedContainers == nil {
+				if len(jobs.Items) != 0 {
+					t.Errorf("expected no job, got %d", len(jobs.Items))
+				}
+				return
+			}
+			if len(jobs.Items) != 1 {
+				t.Fatalf("expected 1 job, got %d", len(jobs.Items))
+			}
+			gotContainers := []string{}
+			for _, c := range jobs.Items[0].Spec.Template.Spec.Containers {
+				gotContainers = append(gotContainers, c.Name)
+				expectedArgs := append(container(c.Name, txtOwner(c)).Args, "--once", "--annotation-filter=externaldns.olm.openshift.io/cleanup-records in (true)")
+				if diff := cmp.Diff(expectedArgs, c.Args); diff != "" {
+					t.Errorf("unexpected container args (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.expectedContainers, gotContainers); diff != "" {
+				t.Errorf("unexpected job containers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func testCleanupDeployment(replicas int32) *appsv1.Deployment {
+	depl := testOperandDeployment(replicas)
+	depl.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name: ExternalDNSContainerName,
+			Args: []string{"--provider=aws", "--policy=sync"},
+		},
+	}
+	return depl
+}
@@ -19,26 +19,35 @@ package externaldnscontroller
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	configv1 "github.com/openshift/api/config/v1"
+	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 
 	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	operatorconfig "github.com/openshift/external-dns-operator/pkg/operator/config"
 	controlleroperator "github.com/openshift/external-dns-operator/pkg/operator/controller"
 	ctrlutils "github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
 	operatorutils "github.com/openshift/external-dns-operator/pkg/utils"
@@ -52,22 +61,32 @@ type Config struct {
 	Image string
 	// OperatorNamespace is the namespace in which this operator is deployed.
 	OperatorNamespace string
+	// CredentialsSourceNamespace is the namespace of the credentials secrets referenced in ExternalDNS resources.
+	CredentialsSourceNamespace string
 	// IsOpenShift is the flag which instructs the operator that it runs in OpenShift.
 	IsOpenShift bool
-	// PlatformStatus is the details about the underlying platform.
-	PlatformStatus *configv1.PlatformStatus
+	// Platform holds the details about the underlying platform.
+	Platform *operatorconfig.PlatformDetails
+	// PlatformChanges receives an event every time the platform details change.
+	PlatformChanges <-chan event.GenericEvent
 	// InjectTrustedCA is the flag which instructs the operator to inject the trusted CA into ExternalDNS containers.
 	InjectTrustedCA bool
 	// RequeuePeriod is the period to wait after a failed reconciliation.
 	RequeuePeriod time.Duration
+	// EnablePreflight is the flag which instructs the operator to check the provider connectivity
+	// with a preflight job before rolling out the ExternalDNS deployment.
+	EnablePreflight bool
 }
 
 // reconciler reconciles an ExternalDNS object.
 type reconciler struct {
-	config Config
-	client client.Client
-	scheme *runtime.Scheme
-	log    logr.Logger
+	config    Config
+	client    client.Client
+	apiReader client.Reader
+	scheme    *runtime.Scheme
+	log       logr.Logger
+	podLogs   podLogReader
+	recorder  record.EventRecorder
 }
 
 // New creates the externaldns controller from mgr and cfg. The controller will be pre-configured
@@ -78,11 +97,20 @@ func New(mgr manager.Manager, cfg Config) (controller.Controller, error) {
 	operatorScheme := mgr.GetScheme()
 	operatorRESTMapper := mgr.GetRESTMapper()
 
+	// the controller-runtime client doesn't support the pods/log subresource
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
 	r := &reconciler{
-		config: cfg,
-		client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
-		log:    log,
+		config:    cfg,
+		client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+		scheme:    mgr.GetScheme(),
+		log:       log,
+		podLogs:   &clientsetPodLogReader{clientset: clientset},
+		recorder:  mgr.GetEventRecorderFor(controlleroperator.ControllerName),
 	}
 
 	c, err := controller.New(controlleroperator.ControllerName, mgr, controller.Options{Reconciler: r})
@@ -109,18 +137,31 @@ func New(mgr manager.Manager, cfg Config) (controller.Controller, error) {
 		return nil, err
 	}
 
-	// enqueue all ExternalDNS instances if the trusted CA config map changed
+	if err := c.Watch(source.Kind[client.Object](operatorCache, &batchv1.Job{}, handler.EnqueueRequestForOwner(operatorScheme, operatorRESTMapper, &operatorv1beta1.ExternalDNS{}, handler.OnlyControllerOwner()))); err != nil {
+		return nil, err
+	}
+
+	if err := c.Watch(source.Kind[client.Object](operatorCache, &rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestForOwner(operatorScheme, operatorRESTMapper, &operatorv1beta1.ExternalDNS{}, handler.OnlyControllerOwner()))); err != nil {
+		return nil, err
+	}
+
+	if err := c.Watch(source.Kind[client.Object](operatorCache, &rbacv1.RoleBinding{}, handler.EnqueueRequestForOwner(operatorScheme, operatorRESTMapper, &operatorv1beta1.ExternalDNS{}, handler.OnlyControllerOwner()))); err != nil {
+		return nil, err
+	}
+
+	// enqueue all ExternalDNS instances if the trusted CA config map
+	// or the zones and domains of any instance changed.
 	// EnqueueRequestForOwner won't work here
-	// because the trusted CA configmap doesn't belong to any particular ExternalDNS instance
+	// because these objects don't belong to any particular ExternalDNS instance
 	allExtDNSInstances := func(ctx context.Context, o client.Object) []reconcile.Request {
 		externalDNSList := &operatorv1beta1.ExternalDNSList{}
 		requests := []reconcile.Request{}
 		if err := mgr.GetCache().List(ctx, externalDNSList); err != nil {
-			log.Error(err, "failed to list externalDNS for trusted CA configmap")
+			log.Error(err, "failed to list externalDNS", "trigger", o.GetName())
 			return requests
 		}
 		for _, ed := range externalDNSList.Items {
-			log.Info("queueing externalDNS for trusted CA configmap", "name", ed.Name)
+			log.Info("queueing externalDNS", "name", ed.Name, "trigger", o.GetName())
 			request := reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name: ed.Name,
@@ -130,12 +171,170 @@ func New(mgr manager.Manager, cfg Config) (controller.Controller, error) {
 		}
 		return requests
 	}
+	// the conflict condition depends on the other instances
+	if err := c.Watch(
+		source.Kind[client.Object](operatorCache, &operatorv1beta1.ExternalDNS{},
+			handler.EnqueueRequestsFromMapFunc(allExtDNSInstances),
+			predicate.GenerationChangedPredicate{},
+		)); err != nil {
+		return nil, err
+	}
+
+	// the defaults from the config apply to all instances
+	if err := c.Watch(
+		source.Kind[client.Object](operatorCache, &operatorv1beta1.ExternalDNSConfig{},
+			handler.EnqueueRequestsFromMapFunc(allExtDNSInstances),
+			predicate.NewPredicateFuncs(ctrlutils.HasName(operatorv1beta1.ExternalDNSConfigName)),
+		)); err != nil {
+		return nil, err
+	}
+
+	// the platform details are used in the operand deployment and the credentials request
+	if cfg.PlatformChanges != nil {
+		if err := c.Watch(source.Channel(cfg.PlatformChanges, handler.EnqueueRequestsFromMapFunc(allExtDNSInstances))); err != nil {
+			return nil, err
+		}
+	}
+
+	// the namespaced instances fan out their containers to the selected namespaces,
+	// the namespaces may be created, deleted, or gain and lose the labels
+	extDNSInstancesForNamespace := func(ctx context.Context, o client.Object) []reconcile.Request {
+		externalDNSList := &operatorv1beta1.ExternalDNSList{}
+		requests := []reconcile.Request{}
+		if err := mgr.GetCache().List(ctx, externalDNSList); err != nil {
+			log.Error(err, "failed to list externalDNS for namespace")
+			return requests
+		}
+		for i := range externalDNSList.Items {
+			if namespaceScoped(&externalDNSList.Items[i]) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: externalDNSList.Items[i].Name}})
+			}
+		}
+		return requests
+	}
+	if err := c.Watch(
+		source.Kind[client.Object](operatorCache, &corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(extDNSInstancesForNamespace),
+			predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+						e.ObjectOld.GetDeletionTimestamp().IsZero() != e.ObjectNew.GetDeletionTimestamp().IsZero()
+				},
+			},
+		)); err != nil {
+		return nil, err
+	}
+
+	// the pods scheduling is reported in the status of the ExternalDNS instance they run for,
+	// the pods are owned by the replica sets, not by the ExternalDNS instances
+	extDNSInstanceForPod := func(ctx context.Context, o client.Object) []reconcile.Request {
+		if o.GetLabels()[appNameLabel] != controlleroperator.ExternalDNSBaseName {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: o.GetLabels()[appInstanceLabel]}}}
+	}
+	if err := c.Watch(
+		source.Kind[client.Object](operatorCache, &corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(extDNSInstanceForPod),
+			predicate.NewPredicateFuncs(ctrlutils.InNamespace(cfg.Namespace)),
+		)); err != nil {
+		return nil, err
+	}
+
+	if cfg.IsOpenShift {
+		// the credentials request is shared among the instances of the same provider
+		extDNSInstancesForCredentialsRequest := func(ctx context.Context, o client.Object) []reconcile.Request {
+			externalDNSList := &operatorv1beta1.ExternalDNSList{}
+			requests := []reconcile.Request{}
+			if err := mgr.GetCache().List(ctx, externalDNSList); err != nil {
+				log.Error(err, "failed to list externalDNS for credentials request")
+				return requests
+			}
+			for i := range externalDNSList.Items {
+				if controlleroperator.ExternalDNSCredentialsRequestName(&externalDNSList.Items[i]).Name == o.GetName() {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: externalDNSList.Items[i].Name}})
+				}
+			}
+			return requests
+		}
+		if err := c.Watch(
+			source.Kind[client.Object](operatorCache, &cco.CredentialsRequest{},
+				handler.EnqueueRequestsFromMapFunc(extDNSInstancesForCredentialsRequest),
+			)); err != nil {
+			return nil, err
+		}
+	}
+
+	// the source credentials secret is validated
+	// to report the invalid content in the ExternalDNS status
+	extDNSInstancesForSourceSecret := func(ctx context.Context, o client.Object) []reconcile.Request {
+		externalDNSList := &operatorv1beta1.ExternalDNSList{}
+		requests := []reconcile.Request{}
+		if err := mgr.GetCache().List(ctx, externalDNSList); err != nil {
+			log.Error(err, "failed to list externalDNS for source credentials secret")
+			return requests
+		}
+		for _, ed := range externalDNSList.Items {
+			if controlleroperator.ExternalDNSCredentialsSecretNameFromProvider(&ed) == o.GetName() {
+				log.Info("queueing externalDNS for source credentials secret", "name", ed.Name)
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ed.Name}})
+			}
+		}
+		return requests
+	}
+	if err := c.Watch(
+		source.Kind[client.Object](operatorCache, &corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(extDNSInstancesForSourceSecret),
+			predicate.NewPredicateFuncs(ctrlutils.InNamespace(cfg.CredentialsSourceNamespace)),
+		)); err != nil {
+		return nil, err
+	}
+
 	if err := c.Watch(
 		source.Kind[client.Object](operatorCache, &corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(allExtDNSInstances),
-			// only the target trusted CA configmap
+			// only the target trusted CA configmap and the one of the cluster-wide proxy
 			predicate.NewPredicateFuncs(ctrlutils.InNamespace(cfg.Namespace)),
-			predicate.NewPredicateFuncs(ctrlutils.HasName(controlleroperator.ExternalDNSDestTrustedCAConfigMapName(cfg.Namespace).Name)),
+			predicate.Or(
+				predicate.NewPredicateFuncs(ctrlutils.HasName(controlleroperator.ExternalDNSDestTrustedCAConfigMapName(cfg.Namespace).Name)),
+				predicate.NewPredicateFuncs(ctrlutils.HasName(controlleroperator.ExternalDNSProxyTrustedCAConfigMapName(cfg.Namespace).Name)),
+			),
+		)); err != nil {
+		return nil, err
+	}
+
+	// the trusted CA configmap of the provider is copied from the operator namespace
+	extDNSInstancesForProviderTrustedCA := func(ctx context.Context, o client.Object) []reconcile.Request {
+		externalDNSList := &operatorv1beta1.ExternalDNSList{}
+		requests := []reconcile.Request{}
+		if err := mgr.GetCache().List(ctx, externalDNSList); err != nil {
+			log.Error(err, "failed to list externalDNS for provider trusted CA configmap")
+			return requests
+		}
+		for _, ed := range externalDNSList.Items {
+			if ed.Spec.Provider.TrustedCA != nil && ed.Spec.Provider.TrustedCA.Name == o.GetName() {
+				log.Info("queueing externalDNS for provider trusted CA configmap", "name", ed.Name)
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ed.Name}})
+			}
+		}
+		return requests
+	}
+	if err := c.Watch(
+		source.Kind[client.Object](operatorCache, &corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(extDNSInstancesForProviderTrustedCA),
+			predicate.NewPredicateFuncs(ctrlutils.InNamespace(cfg.OperatorNamespace)),
+		)); err != nil {
+		return nil, err
+	}
+
+	// the copied trusted CA configmap of the provider is reverted if changed
+	if err := c.Watch(
+		source.Kind[client.Object](operatorCache, &corev1.ConfigMap{},
+			handler.EnqueueRequestForOwner(operatorScheme, operatorRESTMapper, &operatorv1beta1.ExternalDNS{}, handler.OnlyControllerOwner()),
+			predicate.NewPredicateFuncs(ctrlutils.InNamespace(cfg.Namespace)),
+			predicate.NewPredicateFuncs(func(o client.Object) bool {
+				return strings.HasPrefix(o.GetName(), controlleroperator.ExternalDNSBaseName+"-trusted-ca-")
+			}),
 		)); err != nil {
 		return nil, err
 	}
@@ -153,11 +352,26 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err := r.client.Get(ctx, req.NamespacedName, externalDNS); err != nil {
 		if errors.IsNotFound(err) {
 			reqLogger.Info("externalDNS not found; reconciliation will be skipped")
+			deleteMetrics(req.Name)
+			if r.config.IsOpenShift {
+				// the deleted instance may have been the only one assuming some roles
+				if err := r.pruneAWSCredentialsRequest(ctx); err != nil {
+					return reconcile.Result{}, fmt.Errorf("failed to prune the credentials request: %w", err)
+				}
+			}
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, fmt.Errorf("failed to get externalDNS %s: %w", req, err)
 	}
 
+	if !externalDNS.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, externalDNS)
+	}
+
+	if err := r.ensureCleanupFinalizer(ctx, externalDNS); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// request credentials from CCO only if all of the following is true:
 	//  - underlying platform is OpenShift
 	//  - DNS provider is supported by CCO
@@ -177,6 +391,16 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return reconcile.Result{}, fmt.Errorf("failed to get externalDNS service account: %w", err)
 	}
 
+	sourceNamespaces, err := r.currentSourceNamespaces(ctx, externalDNS)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get the source namespaces of externalDNS: %w", err)
+	}
+	if err := r.ensureExternalDNSRoleBindings(ctx, externalDNS, sa, sourceNamespaces); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to ensure externalDNS role bindings: %w", err)
+	}
+
+	credSecretValidCond := r.computeCredentialsSecretValidCondition(ctx, externalDNS)
+
 	credSecretNsName := controlleroperator.ExternalDNSDestCredentialsSecretName(r.config.Namespace, externalDNS.Name)
 	credSecretExists, credSecret, err := r.currentExternalDNSSecret(ctx, credSecretNsName)
 	if err != nil {
@@ -184,13 +408,14 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	if !credSecretExists {
 		// show that the secret is not there yet
-		if err := r.updateExternalDNSStatus(ctx, externalDNS, nil, false); err != nil {
+		if err := r.updateExternalDNSStatus(ctx, externalDNS, nil, false, nil, credSecretValidCond); err != nil {
 			reqLogger.Error(err, "failed to update externalDNS custom resource")
 		}
 		// credentials secret was not synced yet or doesn't exist at all,
 		// either way: no need to requeue immediately polluting the logs.
 		return reconcile.Result{RequeueAfter: r.config.RequeuePeriod}, fmt.Errorf("target credentials secret %s not found", credSecretNsName)
 	}
+	reportCredentialsSecretMetrics(externalDNS, credSecret)
 
 	var trustCAConfigMap *corev1.ConfigMap
 	if r.config.InjectTrustedCA {
@@ -205,16 +430,118 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return reconcile.Result{RequeueAfter: r.config.RequeuePeriod}, fmt.Errorf("target CA configmap %s not found", configMapNsName)
 		}
 		trustCAConfigMap = configMap
+	} else if r.config.IsOpenShift && hasProxyTrustedCA(r.config.Platform.ClusterProxy()) {
+		injected, configMap, err := r.ensureProxyTrustedCAConfigMap(ctx)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to ensure the proxy trusted CA configmap: %w", err)
+		}
+		if !injected {
+			// the bundle is injected asynchronously by the cluster network operator
+			return reconcile.Result{RequeueAfter: r.config.RequeuePeriod}, fmt.Errorf("proxy trusted CA bundle is not injected into configmap %s/%s yet", configMap.Namespace, configMap.Name)
+		}
+		trustCAConfigMap = configMap
+	}
+
+	providerCAConfigMapExists, providerTrustCAConfigMap, err := r.ensureProviderTrustedCAConfigMap(ctx, externalDNS, trustCAConfigMap)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to ensure the provider trusted CA configmap: %w", err)
+	}
+	if !providerCAConfigMapExists {
+		// the referenced configmap may be created later,
+		// either way: no need to requeue immediately polluting the logs.
+		return reconcile.Result{RequeueAfter: r.config.RequeuePeriod}, fmt.Errorf("provider trusted CA configmap %s/%s not found", r.config.OperatorNamespace, externalDNS.Spec.Provider.TrustedCA.Name)
+	}
+
+	var currentDeployment *appsv1.Deployment
+	var preflight *preflightResult
+	// the manual sync is deferred until the deployment is scaled down
+	syncDeferred := false
+	switch managementState(externalDNS) {
+	case operatorv1beta1.ManagementStateUnmanaged:
+		// the deployment may be edited manually
+		_, currentDeployment, err = r.currentExternalDNSDeployment(ctx, types.NamespacedName{Namespace: r.config.Namespace, Name: controlleroperator.ExternalDNSResourceName(externalDNS)})
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to get externalDNS deployment: %w", err)
+		}
+	case operatorv1beta1.ManagementStateRemoved:
+		_, currentDeployment, err = r.ensureExternalDNSDeploymentScaledDown(ctx, r.config.Namespace, externalDNS)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to scale down externalDNS deployment: %w", err)
+		}
+	default:
+		if sourceNamespaces != nil && len(sourceNamespaces) == 0 {
+			// no container to run until a namespace is selected
+			_, currentDeployment, err = r.ensureExternalDNSDeploymentScaledDown(ctx, r.config.Namespace, externalDNS)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to scale down externalDNS deployment: %w", err)
+			}
+			break
+		}
+		if manualSyncPending(externalDNS) {
+			// the operand would race with the sync job, it's scaled down until the job finishes
+			var exist bool
+			exist, currentDeployment, err = r.ensureExternalDNSDeploymentScaledDown(ctx, r.config.Namespace, externalDNS)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to scale down externalDNS deployment: %w", err)
+			}
+			if exist {
+				break
+			}
+			// the next reconciliation, triggered by the created deployment, scales it down
+			syncDeferred = true
+		}
+		_, currentDeployment, preflight, err = r.ensureExternalDNSDeployment(ctx, r.config.Namespace, r.config.Image, sa, credSecret, trustCAConfigMap, providerTrustCAConfigMap, sourceNamespaces, externalDNS)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to ensure externalDNS deployment: %w", err)
+		}
 	}
 
-	_, currentDeployment, err := r.ensureExternalDNSDeployment(ctx, r.config.Namespace, r.config.Image, sa, credSecret, trustCAConfigMap, externalDNS)
+	reportDeploymentMetrics(externalDNS, currentDeployment)
+
+	dryRunPlan, err := r.ensureDryRunPlan(ctx, externalDNS, currentDeployment)
 	if err != nil {
-		return reconcile.Result{}, fmt.Errorf("failed to ensure externalDNS deployment: %w", err)
+		return reconcile.Result{}, fmt.Errorf("failed to ensure externalDNS dry run plan: %w", err)
 	}
 
-	if err := r.updateExternalDNSStatus(ctx, externalDNS, currentDeployment, true); err != nil {
+	manualSync := externalDNS.Status.ManualSync
+	if managementState(externalDNS) != operatorv1beta1.ManagementStateRemoved {
+		syncDeployment := currentDeployment
+		if syncDeferred {
+			syncDeployment = nil
+		}
+		manualSync, err = r.ensureManualSync(ctx, externalDNS, syncDeployment)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to ensure externalDNS manual sync: %w", err)
+		}
+	}
+
+	conditions := []metav1.Condition{credSecretValidCond, computeSourceNamespacesCondition(sourceNamespaces)}
+	if preflight != nil {
+		conditions = append(conditions, preflight.condition)
+	}
+	// the skipped source resources are reported, the status is updated even if they cannot be listed
+	if routingPoliciesCond, err := r.computeRoutingPoliciesCondition(ctx, externalDNS, sourceNamespaces); err != nil {
+		reqLogger.Error(err, "failed to check the source resources against the allowed routing policies")
+	} else if routingPoliciesCond != nil {
+		conditions = append(conditions, *routingPoliciesCond)
+	}
+	if err := r.updateExternalDNSStatus(ctx, externalDNS, currentDeployment, true, &operandStatus{dryRunPlan: dryRunPlan, manualSync: manualSync}, conditions...); err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to update externalDNS custom resource %s: %w", externalDNS.Name, err)
 	}
 
-	return reconcile.Result{}, nil
+	result := reconcile.Result{}
+	if externalDNS.Spec.DryRun {
+		// the plan is logged by the operand on every sync
+		result.RequeueAfter = dryRunPlanRefreshPeriod
+	}
+	if preflight != nil && preflight.retryAfter > 0 && (result.RequeueAfter == 0 || preflight.retryAfter < result.RequeueAfter) {
+		// the failed preflight check is retried after a backoff
+		result.RequeueAfter = preflight.retryAfter
+	}
+	if routingPoliciesRestricted(externalDNS) && (result.RequeueAfter == 0 || routingPoliciesRefreshPeriod < result.RequeueAfter) {
+		// the source resources are not watched
+		result.RequeueAfter = routingPoliciesRefreshPeriod
+	}
+
+	return result, nil
 }
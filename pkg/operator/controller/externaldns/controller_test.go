@@ -173,6 +173,86 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:            "Unmanaged",
+			existingObjects: []runtime.Object{testExtDNSInstanceWithManagementState(operatorv1beta1.ManagementStateUnmanaged), testSecret(), testOperandDeployment(1)},
+			inputConfig:     testConfig(),
+			inputRequest:    testRequest(),
+			expectedResult:  reconcile.Result{},
+			expectedEvents: []test.Event{
+				{
+					EventType: watch.Added,
+					ObjType:   serviceAccountResource,
+					NamespacedName: types.NamespacedName{
+						Namespace: test.OperandNamespace,
+						Name:      test.OperandName,
+					},
+				},
+				{
+					EventType: watch.Modified,
+					ObjType:   externalDNSResource,
+					NamespacedName: types.NamespacedName{
+						Name: test.Name,
+					},
+				},
+			},
+		},
+		{
+			name:            "Removed",
+			existingObjects: []runtime.Object{testExtDNSInstanceWithManagementState(operatorv1beta1.ManagementStateRemoved), testSecret(), testOperandDeployment(1)},
+			inputConfig:     testConfig(),
+			inputRequest:    testRequest(),
+			expectedResult:  reconcile.Result{},
+			expectedEvents: []test.Event{
+				{
+					EventType: watch.Modified,
+					ObjType:   deploymentResource,
+					NamespacedName: types.NamespacedName{
+						Namespace: test.OperandNamespace,
+						Name:      test.OperandName,
+					},
+				},
+				{
+					EventType: watch.Added,
+					ObjType:   serviceAccountResource,
+					NamespacedName: types.NamespacedName{
+						Namespace: test.OperandNamespace,
+						Name:      test.OperandName,
+					},
+				},
+				{
+					EventType: watch.Modified,
+					ObjType:   externalDNSResource,
+					NamespacedName: types.NamespacedName{
+						Name: test.Name,
+					},
+				},
+			},
+		},
+		{
+			name:            "Removed and scaled down",
+			existingObjects: []runtime.Object{testExtDNSInstanceWithManagementState(operatorv1beta1.ManagementStateRemoved), testSecret(), testOperandDeployment(0)},
+			inputConfig:     testConfig(),
+			inputRequest:    testRequest(),
+			expectedResult:  reconcile.Result{},
+			expectedEvents: []test.Event{
+				{
+					EventType: watch.Added,
+					ObjType:   serviceAccountResource,
+					NamespacedName: types.NamespacedName{
+						Namespace: test.OperandNamespace,
+						Name:      test.OperandName,
+					},
+				},
+				{
+					EventType: watch.Modified,
+					ObjType:   externalDNSResource,
+					NamespacedName: types.NamespacedName{
+						Name: test.Name,
+					},
+				},
+			},
+		},
 		{
 			name:            "Deleted ExternalDNS",
 			existingObjects: []runtime.Object{},
@@ -184,13 +264,14 @@ func TestReconcile(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithStatusSubresource(&operatorv1beta1.ExternalDNS{}).WithRuntimeObjects(tc.existingObjects...).Build()
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithStatusSubresource(&operatorv1beta1.ExternalDNS{}).WithRuntimeObjects(tc.existingObjects...).WithInterceptorFuncs(test.ServerSideApply).Build()
 
 			r := &reconciler{
-				client: cl,
-				scheme: test.Scheme,
-				config: tc.inputConfig,
-				log:    zap.New(zap.UseDevMode(true)),
+				client:    cl,
+				apiReader: cl,
+				scheme:    test.Scheme,
+				config:    tc.inputConfig,
+				log:       zap.New(zap.UseDevMode(true)),
 			}
 
 			c := test.NewEventCollector(t, cl, managedTypesList, len(tc.expectedEvents))
@@ -298,3 +379,21 @@ func testSecret() *corev1.Secret {
 		},
 	}
 }
+
+func testExtDNSInstanceWithManagementState(state operatorv1beta1.ManagementState) *operatorv1beta1.ExternalDNS {
+	extDNS := testExtDNSInstance()
+	extDNS.Spec.ManagementState = state
+	return extDNS
+}
+
+func testOperandDeployment(replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      test.OperandName,
+			Namespace: test.OperandNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+	}
+}
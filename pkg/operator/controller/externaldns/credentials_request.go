@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 
 	configv1 "github.com/openshift/api/config/v1"
 	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
@@ -50,7 +51,12 @@ func (r *reconciler) ensureExternalCredentialsRequest(ctx context.Context, exter
 		Name:      controller.SecretFromCloudCredentialsOperator,
 		Namespace: r.config.OperatorNamespace,
 	}
-	desired, err := desiredCredentialsRequest(name, secretName, externalDNS, r.config.PlatformStatus)
+	assumeRoleARNs, err := r.externalDNSAssumeRoleARNs(ctx, externalDNS)
+	if err != nil {
+		return false, nil, err
+	}
+
+	desired, err := desiredCredentialsRequest(name, secretName, externalDNS, r.config.Platform.PlatformStatus(), assumeRoleARNs)
 	if err != nil {
 		return false, nil, err
 	}
@@ -59,18 +65,115 @@ func (r *reconciler) ensureExternalCredentialsRequest(ctx context.Context, exter
 		if err := r.createExternalDNSCredentialsRequest(ctx, desired); err != nil {
 			return false, nil, err
 		}
-		return r.currentExternalDNSCredentialsRequest(ctx, name)
+		// the cache may not have the created object yet
+		return true, desired, nil
 	}
 
 	if updated, err := r.updateExternalDNSCredentialsRequest(ctx, current, desired, externalDNS); err != nil {
 		return true, current, err
-	} else if updated {
-		return r.currentExternalDNSCredentialsRequest(ctx, name)
+	} else if updated != nil {
+		return true, updated, nil
 	}
 
 	return true, current, nil
 }
 
+// pruneAWSCredentialsRequest recomputes the AWS credentials request shared among the instances
+// after an ExternalDNS instance was deleted: the roles assumed only by the deleted instance are not allowed anymore.
+// The credentials request is not created if it doesn't exist.
+// The metrics of the credentials request are deleted when no AWS instance remains.
+func (r *reconciler) pruneAWSCredentialsRequest(ctx context.Context) error {
+	// the deleted instance is gone, the remaining instances are listed
+	remaining := &operatorv1beta1.ExternalDNS{
+		Spec: operatorv1beta1.ExternalDNSSpec{
+			Provider: operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAWS},
+		},
+	}
+	name := controller.ExternalDNSCredentialsRequestName(remaining)
+
+	exists, current, err := r.currentExternalDNSCredentialsRequest(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	secretName := types.NamespacedName{
+		Name:      controller.SecretFromCloudCredentialsOperator,
+		Namespace: r.config.OperatorNamespace,
+	}
+	assumeRoleARNs, err := r.externalDNSAssumeRoleARNs(ctx, remaining)
+	if err != nil {
+		return err
+	}
+
+	desired, err := desiredCredentialsRequest(name, secretName, remaining, r.config.Platform.PlatformStatus(), assumeRoleARNs)
+	if err != nil {
+		return err
+	}
+
+	if _, err = r.updateExternalDNSCredentialsRequest(ctx, current, desired, remaining); err != nil {
+		return err
+	}
+
+	externalDNSList := &operatorv1beta1.ExternalDNSList{}
+	if err := r.client.List(ctx, externalDNSList); err != nil {
+		return fmt.Errorf("failed to list externalDNS instances: %w", err)
+	}
+	for i := range externalDNSList.Items {
+		if externalDNSList.Items[i].Spec.Provider.Type == operatorv1beta1.ProviderTypeAWS {
+			return nil
+		}
+	}
+	deleteCredentialsRequestMetrics(name.Name)
+	return nil
+}
+
+// externalDNSAssumeRoleARNs returns the sorted list of the IAM roles
+// assumed by the ExternalDNS instances of the same provider as the given one.
+// The credentials request is shared among the instances of the same provider,
+// so it has to allow the roles of all of them.
+func (r *reconciler) externalDNSAssumeRoleARNs(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS) ([]string, error) {
+	if externalDNS.Spec.Provider.Type != operatorv1beta1.ProviderTypeAWS {
+		return nil, nil
+	}
+
+	externalDNSList := &operatorv1beta1.ExternalDNSList{}
+	if err := r.client.List(ctx, externalDNSList); err != nil {
+		return nil, fmt.Errorf("failed to list externalDNS instances: %w", err)
+	}
+
+	// the given instance may be more recent than the listed one
+	instances := []*operatorv1beta1.ExternalDNS{externalDNS}
+	for i := range externalDNSList.Items {
+		if externalDNSList.Items[i].Name != externalDNS.Name {
+			instances = append(instances, &externalDNSList.Items[i])
+		}
+	}
+
+	arnSet := map[string]struct{}{}
+	for _, ed := range instances {
+		awsOptions := ed.Spec.Provider.AWS
+		if ed.Spec.Provider.Type != operatorv1beta1.ProviderTypeAWS || awsOptions == nil {
+			continue
+		}
+		if awsOptions.AssumeRole != nil {
+			arnSet[awsOptions.AssumeRole.ARN] = struct{}{}
+		}
+		for _, zoneRole := range awsOptions.ZoneAssumeRoles {
+			arnSet[zoneRole.ARN] = struct{}{}
+		}
+	}
+
+	arns := make([]string, 0, len(arnSet))
+	for arn := range arnSet {
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+	return arns, nil
+}
+
 // currentExternalDNSCredentialsRequest returns true if credentials request exists.
 func (r *reconciler) currentExternalDNSCredentialsRequest(ctx context.Context, name types.NamespacedName) (bool, *cco.CredentialsRequest, error) {
 	cr := &cco.CredentialsRequest{}
@@ -92,26 +195,28 @@ func (r *reconciler) createExternalDNSCredentialsRequest(ctx context.Context, de
 	return nil
 }
 
-// updateExternalDNSClusterRole updates the cluster role with the desired state if the rules differ
-func (r *reconciler) updateExternalDNSCredentialsRequest(ctx context.Context, current, desired *cco.CredentialsRequest, externalDNS *operatorv1beta1.ExternalDNS) (bool, error) {
+// updateExternalDNSCredentialsRequest updates the credentials request with the desired state if they differ.
+// Returns the updated credentials request, nil if no update was needed.
+func (r *reconciler) updateExternalDNSCredentialsRequest(ctx context.Context, current, desired *cco.CredentialsRequest, externalDNS *operatorv1beta1.ExternalDNS) (*cco.CredentialsRequest, error) {
 	updated := current.DeepCopy()
 	changed, err := externalDNSCredentialsRequestChanged(current, desired, updated, externalDNS)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	if !changed {
-		return false, nil
+		return nil, nil
 	}
 
 	if err := r.client.Update(ctx, updated); err != nil {
-		return false, err
+		return nil, err
 	}
 	r.log.Info("updated externalDNS credentials request", "name", updated.Name, "namespace", updated.Namespace)
-	return true, nil
+	credentialsRequestUpdatesMetric.WithLabelValues(updated.Name).Inc()
+	return updated, nil
 }
 
 // desiredCredentialsRequestName returns the desired credentials request definition for externalDNS
-func desiredCredentialsRequest(name, secretName types.NamespacedName, externalDNS *operatorv1beta1.ExternalDNS, platformStatus *configv1.PlatformStatus) (*cco.CredentialsRequest, error) {
+func desiredCredentialsRequest(name, secretName types.NamespacedName, externalDNS *operatorv1beta1.ExternalDNS, platformStatus *configv1.PlatformStatus, assumeRoleARNs []string) (*cco.CredentialsRequest, error) {
 	credentialsRequest := &cco.CredentialsRequest{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "CredentialsRequest",
@@ -135,7 +240,7 @@ func desiredCredentialsRequest(name, secretName types.NamespacedName, externalDN
 		return nil, err
 	}
 
-	providerSpec, err := createProviderConfig(externalDNS, platformStatus, codec)
+	providerSpec, err := createProviderConfig(externalDNS, platformStatus, assumeRoleARNs, codec)
 
 	if err != nil {
 		return nil, err
@@ -222,37 +327,47 @@ func externalDNSCredentialsRequestChanged(current, desired, updated *cco.Credent
 	return changed, nil
 }
 
-func createProviderConfig(externalDNS *operatorv1beta1.ExternalDNS, platformStatus *configv1.PlatformStatus, codec *cco.ProviderCodec) (*runtime.RawExtension, error) {
+func createProviderConfig(externalDNS *operatorv1beta1.ExternalDNS, platformStatus *configv1.PlatformStatus, assumeRoleARNs []string, codec *cco.ProviderCodec) (*runtime.RawExtension, error) {
 	switch externalDNS.Spec.Provider.Type {
 	case operatorv1beta1.ProviderTypeAWS:
 		region := ""
 		if platformStatus != nil && platformStatus.Type == configv1.AWSPlatformType && platformStatus.AWS != nil {
 			region = platformStatus.AWS.Region
 		}
+		statements := []cco.StatementEntry{
+			{
+				Effect: "Allow",
+				Action: []string{
+					"route53:ChangeResourceRecordSets",
+				},
+				Resource: arnPrefix(region) + ":route53:::hostedzone/*",
+			},
+			{
+				Effect: "Allow",
+				Action: []string{
+					"route53:ListHostedZones",
+					"route53:ListResourceRecordSets",
+					"tag:GetResources",
+				},
+				Resource: "*",
+			},
+		}
+		// allow to assume only the roles requested by ExternalDNS instances
+		for _, roleARN := range assumeRoleARNs {
+			statements = append(statements, cco.StatementEntry{
+				Effect: "Allow",
+				Action: []string{
+					"sts:AssumeRole",
+				},
+				Resource: roleARN,
+			})
+		}
 		return codec.EncodeProviderSpec(
 			&cco.AWSProviderSpec{
 				TypeMeta: metav1.TypeMeta{
 					Kind: "AWSProviderSpec",
 				},
-				StatementEntries: []cco.StatementEntry{
-					{
-						Effect: "Allow",
-						Action: []string{
-							"route53:ChangeResourceRecordSets",
-						},
-						Resource: arnPrefix(region) + ":route53:::hostedzone/*",
-					},
-					{
-						Effect: "Allow",
-						Action: []string{
-							"route53:ListHostedZones",
-							"route53:ListResourceRecordSets",
-							"tag:GetResources",
-							"sts:AssumeRole",
-						},
-						Resource: "*",
-					},
-				},
+				StatementEntries: statements,
 			})
 	case operatorv1beta1.ProviderTypeGCP:
 		return codec.EncodeProviderSpec(
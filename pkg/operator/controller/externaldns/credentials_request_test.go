@@ -10,12 +10,15 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	operatorconfig "github.com/openshift/external-dns-operator/pkg/operator/config"
 	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
 )
 
@@ -70,6 +73,12 @@ func TestEnsureCredentialsRequest(t *testing.T) {
 			},
 			expectedCredentialRequest: newCredentialsRequest("externaldns-credentials-request-aws").withSAs("external-dns-operator").withSecret("externaldns-cloud-credentials", "external-dns-operator").withProviderSpec(desiredAWSProviderSpecGovARN).build(),
 		},
+		{
+			name:                      "Create credentials request in AWS with assume roles",
+			existingObjects:           []runtime.Object{test.NewExternalDNS("other").WithAWS().WithRouteSource().WithAWSAssumeRole("arn:aws:iam::123456789012:role/other").Build()},
+			inputExtDNS:               test.NewExternalDNS(test.Name).WithAWS().WithRouteSource().WithZones("public-zone", "private-zone").WithAWSZoneAssumeRole("private-zone", "arn:aws:iam::210987654321:role/network", "external-id").WithAWSAssumeRole("arn:aws:iam::123456789012:role/other").Build(),
+			expectedCredentialRequest: newCredentialsRequest("externaldns-credentials-request-aws").withSAs("external-dns-operator").withSecret("externaldns-cloud-credentials", "external-dns-operator").withProviderSpec(desiredAWSProviderSpecAssumeRoles).build(),
+		},
 		{
 			name:                      "Create credentials request from scratch in Azure",
 			existingObjects:           []runtime.Object{},
@@ -105,7 +114,7 @@ func TestEnsureCredentialsRequest(t *testing.T) {
 				Namespace:         test.OperandNamespace,
 				Image:             test.OperandImage,
 				OperatorNamespace: test.OperatorNamespace,
-				PlatformStatus:    tc.inputPlatformStatus,
+				Platform:          operatorconfig.NewPlatformDetails(tc.inputPlatformStatus, nil, nil),
 			},
 			log: zap.New(zap.UseDevMode(true)),
 		}
@@ -163,6 +172,85 @@ func TestEnsureCredentialsRequest(t *testing.T) {
 	}
 }
 
+func TestPruneAWSCredentialsRequest(t *testing.T) {
+	testCases := []struct {
+		name                      string
+		existingObjects           []runtime.Object
+		expectedCredentialRequest *cco.CredentialsRequest
+		expectedMetricsDeleted    bool
+	}{
+		{
+			name: "Roles of deleted instance removed",
+			existingObjects: []runtime.Object{
+				newCredentialsRequest("externaldns-credentials-request-aws").withSAs("external-dns-operator").withSecret("externaldns-cloud-credentials", "external-dns-operator").withProviderSpec(desiredAWSProviderSpecAssumeRoles).build(),
+				test.NewExternalDNS("other").WithAWS().WithRouteSource().WithAWSAssumeRole("arn:aws:iam::123456789012:role/other").Build(),
+			},
+			expectedCredentialRequest: newCredentialsRequest("externaldns-credentials-request-aws").withSAs("external-dns-operator").withSecret("externaldns-cloud-credentials", "external-dns-operator").withProviderSpec(desiredAWSProviderSpecOtherRole).build(),
+		},
+		{
+			name: "No remaining instance",
+			existingObjects: []runtime.Object{
+				newCredentialsRequest("externaldns-credentials-request-aws").withSAs("external-dns-operator").withSecret("externaldns-cloud-credentials", "external-dns-operator").withProviderSpec(desiredAWSProviderSpecAssumeRoles).build(),
+			},
+			expectedCredentialRequest: newCredentialsRequest("externaldns-credentials-request-aws").withSAs("external-dns-operator").withSecret("externaldns-cloud-credentials", "external-dns-operator").withProviderSpec(desiredAWSProviderSpec).build(),
+			expectedMetricsDeleted:    true,
+		},
+		{
+			name: "No credentials request",
+			existingObjects: []runtime.Object{
+				test.NewExternalDNS("other").WithAWS().WithRouteSource().WithAWSAssumeRole("arn:aws:iam::123456789012:role/other").Build(),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build()
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				config: Config{
+					Namespace:         test.OperandNamespace,
+					OperatorNamespace: test.OperatorNamespace,
+					Platform:          operatorconfig.NewPlatformDetails(nil, nil, nil),
+				},
+				log: zap.New(zap.UseDevMode(true)),
+			}
+
+			credentialsRequestUpdatesMetric.WithLabelValues("externaldns-credentials-request-aws").Inc()
+			defer deleteCredentialsRequestMetrics("externaldns-credentials-request-aws")
+
+			if err := r.pruneAWSCredentialsRequest(context.TODO()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// the label set is not found if the pruning deleted it
+			if deleted := !credentialsRequestUpdatesMetric.DeleteLabelValues("externaldns-credentials-request-aws"); deleted != tc.expectedMetricsDeleted {
+				t.Errorf("expected the credentials request metrics deleted: %t, got: %t", tc.expectedMetricsDeleted, deleted)
+			}
+
+			got := &cco.CredentialsRequest{}
+			err := cl.Get(context.TODO(), types.NamespacedName{Namespace: "openshift-cloud-credential-operator", Name: "externaldns-credentials-request-aws"}, got)
+			if tc.expectedCredentialRequest == nil {
+				if !errors.IsNotFound(err) {
+					t.Fatalf("expected the credentials request not to be created, got error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get the credentials request: %v", err)
+			}
+			gotDecodedAWSSpec, expectedAWSSpec, err := decodeAWSProviderSpec(*got, *tc.expectedCredentialRequest)
+			if err != nil {
+				t.Fatalf("Not able to decode AWS Provider Spec because of %v", err)
+			}
+			if diff := cmp.Diff(expectedAWSSpec, gotDecodedAWSSpec); diff != "" {
+				t.Errorf("Got unexpected provider spec (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 //
 // Helper functions
 //
@@ -285,7 +373,6 @@ func desiredAWSProviderSpec() runtime.Object {
 					"route53:ListHostedZones",
 					"route53:ListResourceRecordSets",
 					"tag:GetResources",
-					"sts:AssumeRole",
 				},
 				Resource: "*",
 			},
@@ -293,6 +380,52 @@ func desiredAWSProviderSpec() runtime.Object {
 	}
 }
 
+func desiredAWSProviderSpecAssumeRoles() runtime.Object {
+	return &cco.AWSProviderSpec{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "AWSProviderSpec",
+		},
+		StatementEntries: []cco.StatementEntry{
+			{
+				Effect: "Allow",
+				Action: []string{
+					"route53:ChangeResourceRecordSets",
+				},
+				Resource: "arn:aws:route53:::hostedzone/*",
+			},
+			{
+				Effect: "Allow",
+				Action: []string{
+					"route53:ListHostedZones",
+					"route53:ListResourceRecordSets",
+					"tag:GetResources",
+				},
+				Resource: "*",
+			},
+			{
+				Effect: "Allow",
+				Action: []string{
+					"sts:AssumeRole",
+				},
+				Resource: "arn:aws:iam::123456789012:role/other",
+			},
+			{
+				Effect: "Allow",
+				Action: []string{
+					"sts:AssumeRole",
+				},
+				Resource: "arn:aws:iam::210987654321:role/network",
+			},
+		},
+	}
+}
+
+func desiredAWSProviderSpecOtherRole() runtime.Object {
+	spec := desiredAWSProviderSpecAssumeRoles().(*cco.AWSProviderSpec)
+	spec.StatementEntries = spec.StatementEntries[:3]
+	return spec
+}
+
 func desiredAWSProviderSpecGovARN() runtime.Object {
 	return &cco.AWSProviderSpec{
 		TypeMeta: metav1.TypeMeta{
@@ -312,7 +445,6 @@ func desiredAWSProviderSpecGovARN() runtime.Object {
 					"route53:ListHostedZones",
 					"route53:ListResourceRecordSets",
 					"tag:GetResources",
-					"sts:AssumeRole",
 				},
 				Resource: "*",
 			},
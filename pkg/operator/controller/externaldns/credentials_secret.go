@@ -0,0 +1,192 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controlleroperator "github.com/openshift/external-dns-operator/pkg/operator/controller"
+)
+
+const (
+	// AWS credentials secret keys
+	awsAccessKeyIDKey     = "aws_access_key_id"
+	awsSecretAccessKeyKey = "aws_secret_access_key"
+	// BlueCat credentials secret keys
+	blueCatUsernameKey = "username"
+	blueCatPasswordKey = "password"
+
+	credentialsSecretMissingKeyReason   = "MissingKey"
+	credentialsSecretInvalidJSONReason  = "InvalidJSON"
+	credentialsSecretMissingFieldReason = "MissingField"
+)
+
+// credentialsSecretError describes the problem with the content of the credentials secret.
+type credentialsSecretError struct {
+	reason  string
+	message string
+}
+
+// computeCredentialsSecretValidCondition returns an externalDNS condition based on the content of the credentials secret.
+// The secret referenced in the provider options is checked if any,
+// otherwise the secret provisioned by the operator in the operand namespace is checked.
+func (r *reconciler) computeCredentialsSecretValidCondition(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS) metav1.Condition {
+	nsName := controlleroperator.ExternalDNSDestCredentialsSecretName(r.config.Namespace, externalDNS.Name)
+	referenced := false
+	if name := controlleroperator.ExternalDNSCredentialsSecretNameFromProvider(externalDNS); len(name) > 0 {
+		nsName = types.NamespacedName{Namespace: r.config.CredentialsSourceNamespace, Name: name}
+		referenced = true
+	}
+
+	exists, secret, err := r.currentExternalDNSSecret(ctx, nsName)
+	if err != nil {
+		return metav1.Condition{
+			Type:    ExternalDNSCredentialsSecretValidConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "SecretUnknown",
+			Message: "Unable to get the credentials secret: " + err.Error(),
+		}
+	}
+	if !exists {
+		if referenced {
+			return metav1.Condition{
+				Type:    ExternalDNSCredentialsSecretValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "SecretNotFound",
+				Message: fmt.Sprintf("The credentials secret %q not found in %q namespace.", nsName.Name, nsName.Namespace),
+			}
+		}
+		return metav1.Condition{
+			Type:    ExternalDNSCredentialsSecretValidConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "SecretNotProvisioned",
+			Message: "The credentials secret has not been provisioned yet.",
+		}
+	}
+
+	if err := validateCredentialsSecret(externalDNS, secret); err != nil {
+		return metav1.Condition{
+			Type:    ExternalDNSCredentialsSecretValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  err.reason,
+			Message: fmt.Sprintf("The credentials secret %q is invalid: %s.", secret.Name, err.message),
+		}
+	}
+
+	return metav1.Condition{
+		Type:    ExternalDNSCredentialsSecretValidConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "SecretValid",
+		Message: "The credentials secret has all the keys required by the provider.",
+	}
+}
+
+// validateCredentialsSecret checks that the given secret has the keys required by the provider
+// and that the JSON configs contain the required fields.
+// Both the source secret and the secret provisioned by the operator are accepted.
+func validateCredentialsSecret(externalDNS *operatorv1beta1.ExternalDNS, secret *corev1.Secret) *credentialsSecretError {
+	switch externalDNS.Spec.Provider.Type {
+	case operatorv1beta1.ProviderTypeAWS:
+		if len(secret.Data[awsCredentialsFileKey]) == 0 && (len(secret.Data[awsAccessKeyIDKey]) == 0 || len(secret.Data[awsSecretAccessKeyKey]) == 0) {
+			return missingKeysError(fmt.Sprintf("%q key or %q and %q keys", awsCredentialsFileKey, awsAccessKeyIDKey, awsSecretAccessKeyKey))
+		}
+	case operatorv1beta1.ProviderTypeAzure:
+		config, err := jsonConfig(secret, azureConfigFileKey)
+		if err != nil {
+			return err
+		}
+		required := []string{"subscriptionId", "resourceGroup"}
+		if config["useManagedIdentityExtension"] != true && config["useWorkloadIdentityExtension"] != true {
+			// the tenant is only needed to authenticate the service principal
+			required = append(required, "tenantId", "aadClientId", "aadClientSecret")
+		}
+		return requiredFields(azureConfigFileKey, config, required...)
+	case operatorv1beta1.ProviderTypeGCP:
+		config, err := jsonConfig(secret, gcpCredentialsFileKey)
+		if err != nil {
+			return err
+		}
+		return requiredFields(gcpCredentialsFileKey, config, "type")
+	case operatorv1beta1.ProviderTypeBlueCat:
+		if options := externalDNS.Spec.Provider.BlueCat; options != nil && (options.ConfigFile == nil || len(options.ConfigFile.Name) == 0) && len(secret.Data[blueCatConfigFileKey]) == 0 {
+			// the config is generated from the options and the credentials
+			if len(secret.Data[blueCatUsernameKey]) == 0 || len(secret.Data[blueCatPasswordKey]) == 0 {
+				return missingKeysError(fmt.Sprintf("%q and %q keys", blueCatUsernameKey, blueCatPasswordKey))
+			}
+			return nil
+		}
+		config, err := jsonConfig(secret, blueCatConfigFileKey)
+		if err != nil {
+			return err
+		}
+		return requiredFields(blueCatConfigFileKey, config, "gatewayHost", "gatewayUsername", "gatewayPassword", "dnsConfiguration")
+	case operatorv1beta1.ProviderTypeInfoblox:
+		if len(secret.Data[infobloxWAPIUsernameKey]) == 0 || len(secret.Data[infobloxWAPIPasswordKey]) == 0 {
+			return missingKeysError(fmt.Sprintf("%q and %q keys", infobloxWAPIUsernameKey, infobloxWAPIPasswordKey))
+		}
+	}
+	return nil
+}
+
+// jsonConfig returns the JSON object from the given key of the secret.
+func jsonConfig(secret *corev1.Secret, key string) (map[string]interface{}, *credentialsSecretError) {
+	data, exists := secret.Data[key]
+	if !exists || len(data) == 0 {
+		return nil, missingKeysError(fmt.Sprintf("%q key", key))
+	}
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, &credentialsSecretError{
+			reason:  credentialsSecretInvalidJSONReason,
+			message: fmt.Sprintf("%q key is not a valid JSON object: %v", key, err),
+		}
+	}
+	return config, nil
+}
+
+// requiredFields checks that the given fields of the JSON config are set.
+func requiredFields(key string, config map[string]interface{}, fields ...string) *credentialsSecretError {
+	missing := []string{}
+	for _, field := range fields {
+		if value, exists := config[field]; !exists || value == nil || value == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) != 0 {
+		return &credentialsSecretError{
+			reason:  credentialsSecretMissingFieldReason,
+			message: fmt.Sprintf(`%q key misses the required fields "%s"`, key, strings.Join(missing, `", "`)),
+		}
+	}
+	return nil
+}
+
+// missingKeysError returns the error about the missing keys described by the given string.
+func missingKeysError(keys string) *credentialsSecretError {
+	return &credentialsSecretError{
+		reason:  credentialsSecretMissingKeyReason,
+		message: keys + " not found",
+	}
+}
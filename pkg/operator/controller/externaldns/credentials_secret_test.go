@@ -0,0 +1,307 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestValidateCredentialsSecret(t *testing.T) {
+	testCases := []struct {
+		name           string
+		provider       operatorv1beta1.ExternalDNSProvider
+		data           map[string]string
+		expectedReason string
+	}{
+		{
+			name:     "AWS credentials file",
+			provider: operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAWS},
+			data:     map[string]string{"credentials": "[default]"},
+		},
+		{
+			name:     "AWS static credentials",
+			provider: operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAWS},
+			data:     map[string]string{"aws_access_key_id": "id", "aws_secret_access_key": "secret"},
+		},
+		{
+			name:           "AWS key typo",
+			provider:       operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAWS},
+			data:           map[string]string{"aws_access_key_id": "id", "aws_secret_key": "secret"},
+			expectedReason: credentialsSecretMissingKeyReason,
+		},
+		{
+			name:     "Azure client secret",
+			provider: operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAzure},
+			data:     map[string]string{"azure.json": `{"tenantId":"t","subscriptionId":"s","resourceGroup":"rg","aadClientId":"id","aadClientSecret":"secret"}`},
+		},
+		{
+			name:     "Azure managed identity",
+			provider: operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAzure},
+			data:     map[string]string{"azure.json": `{"tenantId":"t","subscriptionId":"s","resourceGroup":"rg","useManagedIdentityExtension":true}`},
+		},
+		{
+			name:     "Azure managed identity without tenant",
+			provider: operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAzure},
+			data:     map[string]string{"azure.json": `{"subscriptionId":"s","resourceGroup":"rg","useManagedIdentityExtension":true}`},
+		},
+		{
+			name:           "Azure client secret without tenant",
+			provider:       operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAzure},
+			data:           map[string]string{"azure.json": `{"subscriptionId":"s","resourceGroup":"rg","aadClientId":"id","aadClientSecret":"secret"}`},
+			expectedReason: credentialsSecretMissingFieldReason,
+		},
+		{
+			name:           "Azure missing client secret",
+			provider:       operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAzure},
+			data:           map[string]string{"azure.json": `{"tenantId":"t","subscriptionId":"s","resourceGroup":"rg","aadClientId":"id"}`},
+			expectedReason: credentialsSecretMissingFieldReason,
+		},
+		{
+			name:           "Azure invalid JSON",
+			provider:       operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAzure},
+			data:           map[string]string{"azure.json": `{"tenantId":"t",}`},
+			expectedReason: credentialsSecretInvalidJSONReason,
+		},
+		{
+			name:           "Azure key typo",
+			provider:       operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeAzure},
+			data:           map[string]string{"azure.jsn": `{}`},
+			expectedReason: credentialsSecretMissingKeyReason,
+		},
+		{
+			name:     "GCP service account",
+			provider: operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeGCP},
+			data:     map[string]string{"gcp-credentials.json": `{"type":"service_account"}`},
+		},
+		{
+			name:           "GCP missing type",
+			provider:       operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeGCP},
+			data:           map[string]string{"gcp-credentials.json": `{"client_email":"sa@example.com"}`},
+			expectedReason: credentialsSecretMissingFieldReason,
+		},
+		{
+			name: "BlueCat config file",
+			provider: operatorv1beta1.ExternalDNSProvider{
+				Type:    operatorv1beta1.ProviderTypeBlueCat,
+				BlueCat: &operatorv1beta1.ExternalDNSBlueCatProviderOptions{ConfigFile: &operatorv1beta1.SecretReference{Name: "bluecat-config"}},
+			},
+			data: map[string]string{"bluecat.json": `{"gatewayHost":"https://gw","gatewayUsername":"u","gatewayPassword":"p","dnsConfiguration":"c"}`},
+		},
+		{
+			name: "BlueCat config file missing gateway",
+			provider: operatorv1beta1.ExternalDNSProvider{
+				Type:    operatorv1beta1.ProviderTypeBlueCat,
+				BlueCat: &operatorv1beta1.ExternalDNSBlueCatProviderOptions{ConfigFile: &operatorv1beta1.SecretReference{Name: "bluecat-config"}},
+			},
+			data:           map[string]string{"bluecat.json": `{"gatewayUsername":"u","gatewayPassword":"p","dnsConfiguration":"c"}`},
+			expectedReason: credentialsSecretMissingFieldReason,
+		},
+		{
+			name: "BlueCat credentials",
+			provider: operatorv1beta1.ExternalDNSProvider{
+				Type:    operatorv1beta1.ProviderTypeBlueCat,
+				BlueCat: &operatorv1beta1.ExternalDNSBlueCatProviderOptions{Credentials: &operatorv1beta1.SecretReference{Name: "bluecat-credentials"}},
+			},
+			data: map[string]string{"username": "u", "password": "p"},
+		},
+		{
+			name: "BlueCat credentials missing password",
+			provider: operatorv1beta1.ExternalDNSProvider{
+				Type:    operatorv1beta1.ProviderTypeBlueCat,
+				BlueCat: &operatorv1beta1.ExternalDNSBlueCatProviderOptions{Credentials: &operatorv1beta1.SecretReference{Name: "bluecat-credentials"}},
+			},
+			data:           map[string]string{"username": "u"},
+			expectedReason: credentialsSecretMissingKeyReason,
+		},
+		{
+			name:     "Infoblox credentials",
+			provider: operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeInfoblox},
+			data:     map[string]string{"EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME": "u", "EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD": "p"},
+		},
+		{
+			name:           "Infoblox missing password",
+			provider:       operatorv1beta1.ExternalDNSProvider{Type: operatorv1beta1.ProviderTypeInfoblox},
+			data:           map[string]string{"EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME": "u"},
+			expectedReason: credentialsSecretMissingKeyReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extDNS := test.NewExternalDNS(test.Name).Build()
+			extDNS.Spec.Provider = tc.provider
+			secret := &corev1.Secret{Data: map[string][]byte{}}
+			for k, v := range tc.data {
+				secret.Data[k] = []byte(v)
+			}
+			err := validateCredentialsSecret(extDNS, secret)
+			if tc.expectedReason == "" {
+				if err != nil {
+					t.Fatalf("expected no error but got %q", err.message)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected %q error but got none", tc.expectedReason)
+			}
+			if err.reason != tc.expectedReason {
+				t.Errorf("expected %q reason but got %q: %s", tc.expectedReason, err.reason, err.message)
+			}
+		})
+	}
+}
+
+func TestComputeCredentialsSecretValidCondition(t *testing.T) {
+	testCases := []struct {
+		name              string
+		existingObjects   []runtime.Object
+		externalDNS       *operatorv1beta1.ExternalDNS
+		expectedCondition metav1.Condition
+	}{
+		{
+			name:        "Referenced secret not found",
+			externalDNS: testCredentialsSecretExternalDNS("aws-secret"),
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSCredentialsSecretValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "SecretNotFound",
+				Message: `The credentials secret "aws-secret" not found in "external-dns-operator" namespace.`,
+			},
+		},
+		{
+			name: "Referenced secret with wrong key",
+			existingObjects: []runtime.Object{
+				testCredentialsSecret(test.OperatorNamespace, "aws-secret", map[string][]byte{"credential": []byte("[default]")}),
+			},
+			externalDNS: testCredentialsSecretExternalDNS("aws-secret"),
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSCredentialsSecretValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  credentialsSecretMissingKeyReason,
+				Message: `The credentials secret "aws-secret" is invalid: "credentials" key or "aws_access_key_id" and "aws_secret_access_key" keys not found.`,
+			},
+		},
+		{
+			name: "Referenced secret valid",
+			existingObjects: []runtime.Object{
+				testCredentialsSecret(test.OperatorNamespace, "aws-secret", map[string][]byte{"credentials": []byte("[default]")}),
+			},
+			externalDNS: testCredentialsSecretExternalDNS("aws-secret"),
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSCredentialsSecretValidConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "SecretValid",
+				Message: "The credentials secret has all the keys required by the provider.",
+			},
+		},
+		{
+			name:        "Provisioned secret not found",
+			externalDNS: testCredentialsSecretExternalDNS(""),
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSCredentialsSecretValidConditionType,
+				Status:  metav1.ConditionUnknown,
+				Reason:  "SecretNotProvisioned",
+				Message: "The credentials secret has not been provisioned yet.",
+			},
+		},
+		{
+			name: "Provisioned secret valid",
+			existingObjects: []runtime.Object{
+				testCredentialsSecret(test.OperandNamespace, "external-dns-credentials-"+test.Name, map[string][]byte{"credentials": []byte("[default]")}),
+			},
+			externalDNS: testCredentialsSecretExternalDNS(""),
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSCredentialsSecretValidConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "SecretValid",
+				Message: "The credentials secret has all the keys required by the provider.",
+			},
+		},
+		{
+			name: "Provisioned Azure managed identity config without tenant",
+			existingObjects: []runtime.Object{
+				testCredentialsSecret(test.OperandNamespace, "external-dns-credentials-"+test.Name, map[string][]byte{
+					"azure.json": []byte(`{"resourceGroup":"dns-rg","subscriptionId":"sub","useManagedIdentityExtension":true}`),
+				}),
+			},
+			externalDNS: testManagedIdentityExternalDNS(),
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSCredentialsSecretValidConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "SecretValid",
+				Message: "The credentials secret has all the keys required by the provider.",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build()
+			config := testConfig()
+			config.CredentialsSourceNamespace = test.OperatorNamespace
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				config: config,
+				log:    zap.New(zap.UseDevMode(true)),
+			}
+			cond := r.computeCredentialsSecretValidCondition(context.TODO(), tc.externalDNS)
+			if diff := cmp.Diff(tc.expectedCondition, cond); diff != "" {
+				t.Errorf("unexpected condition (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func testCredentialsSecretExternalDNS(secretName string) *operatorv1beta1.ExternalDNS {
+	extDNS := test.NewExternalDNS(test.Name).WithAWS().Build()
+	extDNS.Spec.Provider.AWS = &operatorv1beta1.ExternalDNSAWSProviderOptions{
+		Credentials: operatorv1beta1.SecretReference{Name: secretName},
+	}
+	return extDNS
+}
+
+func testManagedIdentityExternalDNS() *operatorv1beta1.ExternalDNS {
+	extDNS := test.NewExternalDNS(test.Name).WithAzure().Build()
+	extDNS.Spec.Provider.Azure = &operatorv1beta1.ExternalDNSAzureProviderOptions{
+		SubscriptionID:              "sub",
+		ResourceGroup:               "dns-rg",
+		UseManagedIdentityExtension: true,
+	}
+	return extDNS
+}
+
+func testCredentialsSecret(namespace, name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Data: data,
+	}
+}
@@ -21,23 +21,25 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	configv1 "github.com/openshift/api/config/v1"
 
 	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
 	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
 )
 
 const (
@@ -85,17 +87,28 @@ type deploymentConfig struct {
 	secretHash             string
 	trustedCAConfigMapName string
 	trustedCAConfigMapHash string
+	operandDefaults        *operatorv1beta1.ExternalDNSConfigSpec
+	clusterProxy           *configv1.Proxy
+	// providerTrustedCAConfigMapHash is the hash of the trusted CA configmap of the instance,
+	// its name replaces the trusted CA configmap name
+	providerTrustedCAConfigMapHash string
+	// namespaces are the namespaces of the source resources, nil for all the namespaces
+	namespaces []string
 }
 
 // ensureExternalDNSDeployment ensures that the externalDNS deployment exists.
-// Returns a Boolean value indicating whether the deployment exists, a pointer to the deployment, and an error when relevant.
-func (r *reconciler) ensureExternalDNSDeployment(ctx context.Context, namespace, image string, serviceAccount *corev1.ServiceAccount, credSecret *corev1.Secret, trustCAConfigMap *corev1.ConfigMap, externalDNS *operatorv1beta1.ExternalDNS) (bool, *appsv1.Deployment, error) {
+// If the preflight check is enabled, the deployment is created or updated only after the check succeeded.
+// Returns a Boolean value indicating whether the deployment exists, a pointer to the deployment,
+// the result of the preflight check if it was run, and an error when relevant.
+// The trusted CA configmap of the provider, if given, is mounted instead of the trusted CA configmap of the operator.
+// The source resources are read from the given namespaces, nil means all the namespaces.
+func (r *reconciler) ensureExternalDNSDeployment(ctx context.Context, namespace, image string, serviceAccount *corev1.ServiceAccount, credSecret *corev1.Secret, trustCAConfigMap, providerTrustCAConfigMap *corev1.ConfigMap, sourceNamespaces []string, externalDNS *operatorv1beta1.ExternalDNS) (bool, *appsv1.Deployment, *preflightResult, error) {
 	nsName := types.NamespacedName{Namespace: namespace, Name: controller.ExternalDNSResourceName(externalDNS)}
 
 	// build credentials secret's hash
 	credSecretHash, err := buildMapHash(credSecret.Data)
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to build the credentials secret's hash: %w", err)
+		return false, nil, nil, fmt.Errorf("failed to build the credentials secret's hash: %w", err)
 	}
 
 	// build trusted CA configmap's hash
@@ -104,53 +117,91 @@ func (r *reconciler) ensureExternalDNSDeployment(ctx context.Context, namespace,
 		trustCAConfigMapName = trustCAConfigMap.Name
 		trustCAConfigMapHash, err = buildStringMapHash(trustCAConfigMap.Data)
 		if err != nil {
-			return false, nil, fmt.Errorf("failed to build the CA configmap's hash: %w", err)
+			return false, nil, nil, fmt.Errorf("failed to build the CA configmap's hash: %w", err)
 		}
 	}
 
+	// build the provider trusted CA configmap's hash,
+	// the configmap already contains the trusted CA of the operator
+	providerTrustCAConfigMapHash := ""
+	if providerTrustCAConfigMap != nil {
+		trustCAConfigMapName = providerTrustCAConfigMap.Name
+		providerTrustCAConfigMapHash, err = buildStringMapHash(providerTrustCAConfigMap.Data)
+		if err != nil {
+			return false, nil, nil, fmt.Errorf("failed to build the provider CA configmap's hash: %w", err)
+		}
+	}
+
+	operandDefaults, err := r.currentExternalDNSConfig(ctx)
+	if err != nil {
+		return false, nil, nil, fmt.Errorf("failed to get the externalDNS config: %w", err)
+	}
+
 	desired, err := desiredExternalDNSDeployment(&deploymentConfig{
 		namespace,
 		image,
 		serviceAccount,
 		externalDNS,
 		r.config.IsOpenShift,
-		r.config.PlatformStatus,
+		r.config.Platform.PlatformStatus(),
 		credSecret.Name,
 		credSecretHash,
 		trustCAConfigMapName,
 		trustCAConfigMapHash,
+		operandDefaults,
+		r.config.Platform.ClusterProxy(),
+		providerTrustCAConfigMapHash,
+		sourceNamespaces,
 	})
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to build externalDNS deployment: %w", err)
+		return false, nil, nil, fmt.Errorf("failed to build externalDNS deployment: %w", err)
 	}
 
 	if err := controllerutil.SetControllerReference(externalDNS, desired, r.scheme); err != nil {
-		return false, nil, fmt.Errorf("failed to set the controller reference for deployment: %w", err)
+		return false, nil, nil, fmt.Errorf("failed to set the controller reference for deployment: %w", err)
 	}
 
 	exist, current, err := r.currentExternalDNSDeployment(ctx, nsName)
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to get externalDNS deployment: %w", err)
+		return false, nil, nil, fmt.Errorf("failed to get externalDNS deployment: %w", err)
 	}
 
-	// create the deployment
-	if !exist {
-		if err := r.createExternalDNSDeployment(ctx, desired); err != nil {
-			return false, nil, err
+	var preflight *preflightResult
+	if r.config.EnablePreflight {
+		rollout := !exist
+		if exist {
+			// the dry run reveals the changes to the fields owned by the operator
+			applied := desired.DeepCopy()
+			if err := r.applyExternalDNSDeployment(ctx, current, applied, client.DryRunAll); err != nil {
+				return exist, current, nil, err
+			}
+			rollout = !equality.Semantic.DeepEqual(current.Spec, applied.Spec)
+		}
+		if rollout {
+			preflight, err = r.ensurePreflight(ctx, externalDNS, desired)
+			if err != nil {
+				return exist, current, nil, err
+			}
+			if !preflight.passed {
+				// keep the current deployment running until the new one is known to work
+				return exist, current, preflight, nil
+			}
 		}
-		// get the deployment from API to catch up the fields added/updated by API and webhooks
-		return r.currentExternalDNSDeployment(ctx, nsName)
 	}
 
-	// update the deployment
-	if updated, err := r.updateExternalDNSDeployment(ctx, current, desired); err != nil {
-		return true, current, err
-	} else if updated {
-		// get the deployment from API to catch up the fields added/updated by API and webhooks
-		return r.currentExternalDNSDeployment(ctx, nsName)
+	if exist {
+		// no container manages the records of the released owners after the rollout
+		if err := r.ensureReleasedOwnersCleanup(ctx, externalDNS, current, desired); err != nil {
+			return exist, current, preflight, err
+		}
 	}
 
-	return true, current, nil
+	applied := desired.DeepCopy()
+	if err := r.applyExternalDNSDeployment(ctx, current, applied); err != nil {
+		return exist, current, preflight, err
+	}
+	reportRolloutMetrics(externalDNS, current, applied)
+	return true, applied, preflight, nil
 }
 
 // currentExternalDNSDeployment gets the current externalDNS deployment resource.
@@ -212,6 +263,21 @@ func desiredExternalDNSDeployment(cfg *deploymentConfig) (*appsv1.Deployment, er
 		},
 	}
 
+	operandConfig := effectiveOperandConfig(cfg.operandDefaults, cfg.externalDNS)
+	if placement := operandConfig.NodePlacement; placement != nil {
+		if placement.NodeSelector != nil {
+			nodeSelectorLbl = placement.NodeSelector
+		}
+		if placement.Tolerations != nil {
+			tolerations = placement.Tolerations
+		}
+	}
+
+	image := cfg.image
+	if operandConfig.Image != "" {
+		image = operandConfig.Image
+	}
+
 	annotations := map[string]string{
 		credentialsAnnotation: cfg.secretHash,
 	}
@@ -220,6 +286,21 @@ func desiredExternalDNSDeployment(cfg *deploymentConfig) (*appsv1.Deployment, er
 		annotations[trustedCAAnnotation] = cfg.trustedCAConfigMapHash
 	}
 
+	if cfg.providerTrustedCAConfigMapHash != "" {
+		annotations[providerTrustedCAAnnotation] = cfg.providerTrustedCAConfigMapHash
+	}
+
+	// the proxy environment variables are the same for all the containers,
+	// the hash makes the rollout reason explicit
+	proxy := desiredProxy(operandConfig, cfg.externalDNS, cfg.isOpenShift, cfg.clusterProxy)
+	if proxy != nil {
+		proxyHash, err := buildProxyHash(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the proxy hash: %w", err)
+		}
+		annotations[proxyAnnotation] = proxyHash
+	}
+
 	depl := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      controller.ExternalDNSResourceName(cfg.externalDNS),
@@ -261,7 +342,7 @@ func desiredExternalDNSDeployment(cfg *deploymentConfig) (*appsv1.Deployment, er
 	depl.Spec.Template.Spec.Volumes = append(depl.Spec.Template.Spec.Volumes, volumes...)
 
 	cbld := &externalDNSContainerBuilder{
-		image:          cfg.image,
+		image:          image,
 		provider:       provider,
 		source:         source,
 		secretName:     cfg.secret,
@@ -269,339 +350,100 @@ func desiredExternalDNSDeployment(cfg *deploymentConfig) (*appsv1.Deployment, er
 		externalDNS:    cfg.externalDNS,
 		isOpenShift:    cfg.isOpenShift,
 		platformStatus: cfg.platformStatus,
+		operandConfig:  operandConfig,
+		proxy:          proxy,
 	}
 
-	if len(cfg.externalDNS.Spec.Zones) == 0 {
-		// an empty list means publish to all zones
-		// this is a special case for Azure
-		// both public and private zones will need to be published to
-		providerList := []string{provider}
-		if cbld.provider == externalDNSProviderTypeAzure {
-			providerList = append(providerList, externalDNSProviderTypeAzurePrivate)
+	// ExternalDNS watches either all the namespaces or a single one:
+	// the containers are repeated for every namespace
+	sourceNamespaces := []string{""}
+	if cfg.namespaces != nil {
+		if len(cfg.namespaces) == 0 {
+			return nil, fmt.Errorf("no source namespace selected")
 		}
-		for _, p := range providerList {
-			cbld.provider = p
-			container, err := cbld.build("")
-			if err != nil {
-				return nil, fmt.Errorf("failed to build container: %w", err)
+		sourceNamespaces = cfg.namespaces
+	}
+
+	for _, ns := range sourceNamespaces {
+		cbld.namespace = ns
+		if len(cfg.externalDNS.Spec.Zones) == 0 {
+			// an empty list means publish to all zones
+			// this is a special case for Azure
+			// both public and private zones will need to be published to
+			providerList := []string{provider}
+			if provider == externalDNSProviderTypeAzure {
+				providerList = append(providerList, externalDNSProviderTypeAzurePrivate)
 			}
-			depl.Spec.Template.Spec.Containers = append(depl.Spec.Template.Spec.Containers, *container)
-		}
-	} else {
-		for _, zone := range cfg.externalDNS.Spec.Zones {
-			container, err := cbld.build(zone)
-			if err != nil {
-				return nil, fmt.Errorf("failed to build container for zone %s: %w", zone, err)
-			}
-			depl.Spec.Template.Spec.Containers = append(depl.Spec.Template.Spec.Containers, *container)
-		}
-	}
-	return depl, nil
-}
-
-// createExternalDNSDeployment creates the given deployment using the reconciler's client.
-func (r *reconciler) createExternalDNSDeployment(ctx context.Context, depl *appsv1.Deployment) error {
-	if err := r.client.Create(ctx, depl); err != nil {
-		return fmt.Errorf("failed to create externalDNS deployment %s/%s: %w", depl.Namespace, depl.Name, err)
-	}
-	r.log.Info("created externalDNS deployment", "namespace", depl.Namespace, "name", depl.Name)
-	return nil
-}
-
-// updateExternalDNSDeployment updates the in-cluster externalDNS deployment.
-// Returns a boolean if an update was made, and an error when relevant.
-func (r *reconciler) updateExternalDNSDeployment(ctx context.Context, current, desired *appsv1.Deployment) (bool, error) {
-	// don't always update (or do simple DeepEqual with) the operand's deployment
-	// as this may result into a "fight" between API/admission webhooks and this controller
-	// example:
-	//  - this controller creates a deployment with the desired fields A, B, C
-	//  - API adds some default fields D, E, F (e.g. metadata, imagePullPullPolicy, dnsPolicy)
-	//  - mutating webhooks add default values to fields E, F
-	//  - this controller gets into reconcile loop and starts all from step 1
-	//  - checking that fields A, B, C are the same as desired would save us the before mentioned round trips:
-	changed, updated := externalDNSDeploymentChanged(current, desired)
-	if !changed {
-		return false, nil
-	}
-
-	if err := r.client.Update(ctx, updated); err != nil {
-		return false, fmt.Errorf("failed to update externalDNS deployment %s/%s: %w", desired.Namespace, desired.Name, err)
-	}
-	r.log.Info("updated externalDNS deployment", "namespace", desired.Namespace, "name", desired.Name)
-	return true, nil
-}
-
-// externalDNSDeploymentChanged evaluates whether or not a deployment update is necessary.
-// Returns a boolean if an update is necessary, and the deployment resource to update to.
-func externalDNSDeploymentChanged(current, expected *appsv1.Deployment) (bool, *appsv1.Deployment) {
-	changed := false
-	updated := current.DeepCopy()
-
-	if expected.Spec.Replicas != nil {
-		expReplicas := *expected.Spec.Replicas
-		if current.Spec.Replicas == nil {
-			updated.Spec.Replicas = &expReplicas
-			changed = true
-		} else if *expected.Spec.Replicas != *current.Spec.Replicas {
-			updated.Spec.Replicas = &expReplicas
-			changed = true
-		}
-	}
-
-	if externalDNSDeploymentStrategyChanged(current, expected, updated) {
-		changed = true
-	}
-
-	if externalDNSAnnotationsChanged(current, expected, updated) {
-		changed = true
-	}
-
-	if externalDNSContainersChanged(current, expected, updated) {
-		changed = true
-	}
-
-	if externalDNSVolumesChanged(current, expected, updated) {
-		changed = true
-	}
-
-	return changed, updated
-}
-
-// externalDNSAnnotationsChanged returns true if any annotation from the podspec differs from the expected.```
-func externalDNSAnnotationsChanged(current, expected, updated *appsv1.Deployment) bool {
-	changed := false
-	if len(current.Spec.Template.Annotations) == 0 {
-		if len(expected.Spec.Template.Annotations) == 0 {
-			return false
-		}
-		updated.Spec.Template.Annotations = expected.Spec.Template.Annotations
-		return true
-	}
-	for expectedKey, expectedValue := range expected.Spec.Template.Annotations {
-		currentVal, currentExists := current.Spec.Template.Annotations[expectedKey]
-		if !currentExists || currentVal != expectedValue {
-			updated.Spec.Template.Annotations[expectedKey] = expectedValue
-			changed = true
-		}
-	}
-	return changed
-}
-
-// externalDNSContainersChanged returns true if the current containers differ from the expected.
-func externalDNSContainersChanged(current, expected, updated *appsv1.Deployment) bool {
-	changed := false
-
-	currentContMap := buildIndexedContainerMap(current.Spec.Template.Spec.Containers)
-	expectedContMap := buildIndexedContainerMap(expected.Spec.Template.Spec.Containers)
-
-	// ensure all expected containers are present,
-	for expName, expCont := range expectedContMap {
-		// expected container is present
-		if currCont, found := currentContMap[expName]; found {
-			if currCont.Image != expCont.Image {
-				updated.Spec.Template.Spec.Containers[currCont.Index].Image = expCont.Image
-				changed = true
-			}
-			if !equalStringSliceContent(expCont.Args, currCont.Args) {
-				updated.Spec.Template.Spec.Containers[currCont.Index].Args = expCont.Args
-				changed = true
-			}
-			if !equalEnvVars(currCont.Env, expCont.Env) {
-				updated.Spec.Template.Spec.Containers[currCont.Index].Env = expCont.Env
-				changed = true
-			}
-			if vmChanged, updatedVolumeMounts := volumeMountsChanged(currCont.VolumeMounts, expCont.VolumeMounts, updated.Spec.Template.Spec.Containers[currCont.Index].VolumeMounts); vmChanged {
-				updated.Spec.Template.Spec.Containers[currCont.Index].VolumeMounts = updatedVolumeMounts
-				changed = true
-			}
-			if scChanged, updatedContext := securityContextChanged(currCont.SecurityContext, updated.Spec.Template.Spec.Containers[currCont.Index].SecurityContext, expCont.SecurityContext); scChanged {
-				updated.Spec.Template.Spec.Containers[currCont.Index].SecurityContext = updatedContext
-				changed = true
+			for _, p := range providerList {
+				cbld.provider = p
+				container, err := cbld.build()
+				if err != nil {
+					return nil, fmt.Errorf("failed to build container: %w", err)
+				}
+				depl.Spec.Template.Spec.Containers = append(depl.Spec.Template.Spec.Containers, *container)
 			}
+			cbld.provider = provider
 		} else {
-			// expected container is not present - add it
-			updated.Spec.Template.Spec.Containers = append(updated.Spec.Template.Spec.Containers, expCont.Container)
-			changed = true
-		}
-	}
-
-	// remove unexpected containers
-	updatedNew := []corev1.Container{}
-	for _, updCont := range updated.Spec.Template.Spec.Containers {
-		if _, found := expectedContMap[updCont.Name]; found {
-			updatedNew = append(updatedNew, updCont)
-		}
-	}
-	if len(updated.Spec.Template.Spec.Containers) != len(updatedNew) {
-		updated.Spec.Template.Spec.Containers = updatedNew
-		changed = true
-	}
-
-	return changed
-}
-
-// externalDNSVolumesChanged returns true if the current volumes differ from the expected.
-func externalDNSVolumesChanged(current, expected, updated *appsv1.Deployment) bool {
-	if len(current.Spec.Template.Spec.Volumes) == 0 {
-		if len(expected.Spec.Template.Spec.Volumes) == 0 {
-			return false
-		}
-		updated.Spec.Template.Spec.Volumes = expected.Spec.Template.Spec.Volumes
-		return true
-	}
-
-	changed := false
-
-	currentVolumeMap := buildIndexedVolumeMap(current.Spec.Template.Spec.Volumes)
-	expectedVolumeMap := buildIndexedVolumeMap(expected.Spec.Template.Spec.Volumes)
-
-	// ignore defaultMode to prevent the fight with the defaulting
-	ignoreFieldsConfigMap := cmpopts.IgnoreFields(corev1.ConfigMapVolumeSource{}, "DefaultMode")
-	ignoreFieldsSecret := cmpopts.IgnoreFields(corev1.SecretVolumeSource{}, "DefaultMode")
-
-	// ensure all expected volumes are present,
-	// unsolicited ones are kept (e.g. kube api token)
-	for expName, expVol := range expectedVolumeMap {
-		if currVol, currExists := currentVolumeMap[expName]; !currExists {
-			updated.Spec.Template.Spec.Volumes = append(updated.Spec.Template.Spec.Volumes, expVol.Volume)
-			changed = true
-		} else {
-			if !cmp.Equal(currVol.Volume, expVol.Volume, cmpopts.EquateEmpty(), ignoreFieldsConfigMap, ignoreFieldsSecret) {
-				updated.Spec.Template.Spec.Volumes[currVol.Index] = expVol.Volume
-				changed = true
+			for _, zones := range containerZones(cfg.externalDNS, provider) {
+				container, err := cbld.build(zones...)
+				if err != nil {
+					return nil, fmt.Errorf("failed to build container for zones %s: %w", strings.Join(zones, ","), err)
+				}
+				depl.Spec.Template.Spec.Containers = append(depl.Spec.Template.Spec.Containers, *container)
 			}
 		}
 	}
-
-	return changed
-}
-
-// externalDNSDeploymentStrategyChanged returns true if the current update strategy differs from expected.
-func externalDNSDeploymentStrategyChanged(current, expected, updated *appsv1.Deployment) bool {
-	changed := false
-
-	// reset the strategy type if it differs
-	if expected.Spec.Strategy.Type != "" &&
-		expected.Spec.Strategy.Type != current.Spec.Strategy.Type {
-		updated.Spec.Strategy.Type = expected.Spec.Strategy.Type
-		changed = true
-	}
-
-	// if the expected strategy is Recreate: make sure no rolling update params are present
-	if expected.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
-		if current.Spec.Strategy.RollingUpdate != nil {
-			updated.Spec.Strategy.RollingUpdate = nil
-			changed = true
-		}
-	}
-
-	return changed
+	return depl, nil
 }
 
-// volumeMountsChanged checks that the current volume mounts have all expected ones,
-// returns true if the current volume mounts had to be changed to match the expected.
-func volumeMountsChanged(current, expected, updated []corev1.VolumeMount) (bool, []corev1.VolumeMount) {
-	if len(current) == 0 {
-		if len(expected) == 0 {
-			return false, updated
+// containerZones returns the zones of the given ExternalDNS grouped by container.
+// In the Shared zones mode the zones are grouped by the provider configuration they need,
+// every zone gets its own group otherwise. The order of the zones is preserved.
+func containerZones(externalDNS *operatorv1beta1.ExternalDNS, provider string) [][]string {
+	groups := [][]string{}
+	if externalDNS.Spec.ZonesMode != operatorv1beta1.ZonesModeShared {
+		for _, zone := range externalDNS.Spec.Zones {
+			groups = append(groups, []string{zone})
 		}
-		return true, expected
+		return groups
 	}
 
-	changed := false
-
-	currentVolumeMountMap := buildIndexedVolumeMountMap(current)
-	expectedVolumeMountMap := buildIndexedVolumeMountMap(expected)
-
-	// ensure all expected volume mounts are present,
-	// unsolicited ones are kept (e.g. kube api token)
-	for expName, expVol := range expectedVolumeMountMap {
-		if currVol, currExists := currentVolumeMountMap[expName]; !currExists {
-			updated = append(updated, expVol.VolumeMount)
-			changed = true
-		} else {
-			if !reflect.DeepEqual(currVol.VolumeMount, expVol.VolumeMount) {
-				updated[currVol.Index] = expVol.VolumeMount
-				changed = true
+	groupIndex := map[string]int{}
+	for _, zone := range externalDNS.Spec.Zones {
+		key := ""
+		switch provider {
+		case externalDNSProviderTypeAWS:
+			// the assume role flag cannot be set per zone
+			if role := awsAssumeRoleForZone(externalDNS.Spec.Provider.AWS, zone); role != nil {
+				key = role.ARN + "/" + role.ExternalID
+			}
+		case externalDNSProviderTypeAzure:
+			// the public and private zones are managed by different providers
+			if strings.Contains(strings.ToLower(zone), azurePrivateDNSZonesResourceSubStr) {
+				key = externalDNSProviderTypeAzurePrivate
 			}
 		}
-	}
-
-	return changed, updated
-}
-
-// equalEnvVars returns true if 2 env variable slices have the same content (order doesn't matter).
-func equalEnvVars(current, expected []corev1.EnvVar) bool {
-	var currentSorted, expectedSorted []string
-	for _, env := range current {
-		currentSorted = append(currentSorted, env.Name+" "+env.Value)
-	}
-	for _, env := range expected {
-		expectedSorted = append(expectedSorted, env.Name+" "+env.Value)
-	}
-	sort.Strings(currentSorted)
-	sort.Strings(expectedSorted)
-	return cmp.Equal(currentSorted, expectedSorted)
-}
-
-// indexedContainer is the standard core POD's container with additional index field
-type indexedContainer struct {
-	corev1.Container
-	Index int
-}
-
-// buildIndexedContainerMap builds a map from the given list of containers,
-// key is the container name,
-// value is the indexed container with the index being the sequence number of the given list.
-func buildIndexedContainerMap(containers []corev1.Container) map[string]indexedContainer {
-	m := map[string]indexedContainer{}
-	for i, cont := range containers {
-		m[cont.Name] = indexedContainer{
-			Container: cont,
-			Index:     i,
+		if i, found := groupIndex[key]; found {
+			groups[i] = append(groups[i], zone)
+			continue
 		}
+		groupIndex[key] = len(groups)
+		groups = append(groups, []string{zone})
 	}
-	return m
+	return groups
 }
 
-// indexedVolume is the standard core POD's volume with additional index field
-type indexedVolume struct {
-	corev1.Volume
-	Index int
-}
-
-// buildIndexedVolumeMap builds a map from the given list of volumes,
-// key is the volume name,
-// value is the indexed volume with the index being the sequence number of the given list.
-func buildIndexedVolumeMap(volumes []corev1.Volume) map[string]indexedVolume {
-	m := map[string]indexedVolume{}
-	for i, vol := range volumes {
-		m[vol.Name] = indexedVolume{
-			Volume: vol,
-			Index:  i,
-		}
+// applyExternalDNSDeployment applies the given deployment using the reconciler's client.
+// The given deployment is updated with the applied one.
+func (r *reconciler) applyExternalDNSDeployment(ctx context.Context, current, depl *appsv1.Deployment, opts ...client.PatchOption) error {
+	changed, err := utils.Apply(ctx, r.client, current, depl, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to apply externalDNS deployment %s/%s: %w", depl.Namespace, depl.Name, err)
 	}
-	return m
-}
-
-// indexedVolumeMount is the standard core POD's voume mount with additional index field
-type indexedVolumeMount struct {
-	corev1.VolumeMount
-	Index int
-}
-
-// buildIndexedVolumeMountMap builds a map from the given list of volume mounts,
-// key is the volume name,
-// value is the indexed volume mount with the index being the sequence number of the given list.
-func buildIndexedVolumeMountMap(volumeMounts []corev1.VolumeMount) map[string]indexedVolumeMount {
-	m := map[string]indexedVolumeMount{}
-	for i, vol := range volumeMounts {
-		m[vol.Name] = indexedVolumeMount{
-			VolumeMount: vol,
-			Index:       i,
-		}
+	if changed {
+		r.log.Info("applied externalDNS deployment", "namespace", depl.Namespace, "name", depl.Name)
 	}
-	return m
+	return nil
 }
 
 // equalStringSliceContent returns true if 2 string slices have the same content (order doesn't matter).
@@ -641,77 +483,3 @@ func buildStringMapHash(data map[string]string) (string, error) {
 	}
 	return buildMapHash(m)
 }
-
-func securityContextChanged(current, updated, desired *corev1.SecurityContext) (bool, *corev1.SecurityContext) {
-	changed := false
-
-	if desired == nil {
-		return false, nil
-	}
-
-	if updated == nil {
-		return true, desired
-	}
-
-	if current == nil {
-		return true, desired
-	}
-
-	if desired.Capabilities != nil {
-		cmpCapabilities := cmpopts.SortSlices(func(a, b corev1.Capability) bool { return a < b })
-		if current.Capabilities == nil {
-			updated.Capabilities = desired.Capabilities
-			changed = true
-		} else {
-			if !cmp.Equal(desired.Capabilities.Add, current.Capabilities.Add, cmpCapabilities) {
-				updated.Capabilities.Add = desired.Capabilities.Add
-				changed = true
-			}
-			if !cmp.Equal(desired.Capabilities.Drop, current.Capabilities.Drop, cmpCapabilities) {
-				updated.Capabilities.Drop = desired.Capabilities.Drop
-				changed = true
-			}
-		}
-	}
-
-	if !equalBoolPtr(current.RunAsNonRoot, desired.RunAsNonRoot) {
-		updated.RunAsNonRoot = desired.RunAsNonRoot
-		changed = true
-	}
-
-	if !equalBoolPtr(current.Privileged, desired.Privileged) {
-		updated.Privileged = desired.Privileged
-		changed = true
-	}
-	if !equalBoolPtr(current.AllowPrivilegeEscalation, desired.AllowPrivilegeEscalation) {
-		updated.AllowPrivilegeEscalation = desired.AllowPrivilegeEscalation
-		changed = true
-	}
-
-	if desired.SeccompProfile != nil {
-		if current.SeccompProfile == nil {
-			updated.SeccompProfile = desired.SeccompProfile
-			changed = true
-		} else if desired.SeccompProfile.Type != "" && desired.SeccompProfile.Type != current.SeccompProfile.Type {
-			updated.SeccompProfile = desired.SeccompProfile
-			changed = true
-		}
-	}
-
-	return changed, updated
-}
-
-func equalBoolPtr(current, desired *bool) bool {
-	if desired == nil {
-		return true
-	}
-
-	if current == nil {
-		return false
-	}
-
-	if *current != *desired {
-		return false
-	}
-	return true
-}
@@ -18,20 +18,21 @@ package externaldnscontroller
 
 import (
 	"context"
+	"fmt"
 	"os"
-	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -39,6 +40,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 
 	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
 	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
 	"github.com/openshift/external-dns-operator/pkg/utils"
 )
@@ -60,6 +62,7 @@ var (
 
 const (
 	testSecretHash              = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	testProxyHash               = "008c2c7a8d946bfd6f7b0b8da6bb03a01c4632ae6a04df4df003cd22d7a25405"
 	awsSecret                   = "awssecret"
 	azureSecret                 = "azuresecret"
 	gcpSecret                   = "gcpsecret"
@@ -528,6 +531,269 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:                "Region set AWS Gov",
+			inputSecretName:     awsSecret,
+			inputExternalDNS:    testAWSExternalDNSRegion(operatorv1beta1.SourceTypeService, "us-gov-west-1"),
+			inputPlatformStatus: testPlatformStatusAWSGov("us-east-1"),
+			expectedSpec: appsv1.DeploymentSpec{
+				Replicas: &one,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "external-dns",
+						"app.kubernetes.io/instance": "test",
+					},
+				},
+				Strategy: appsv1.DeploymentStrategy{
+					Type: "Recreate",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app.kubernetes.io/name":     "external-dns",
+							"app.kubernetes.io/instance": "test",
+						},
+						Annotations: map[string]string{
+							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: test.OperandName,
+						NodeSelector: map[string]string{
+							osLabel: linuxOS,
+						},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      masterNodeRoleLabel,
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+						Volumes: []corev1.Volume{
+							{
+								Name: awsCredentialsVolumeName,
+								VolumeSource: corev1.VolumeSource{
+									Secret: &corev1.SecretVolumeSource{
+										SecretName: "awssecret",
+										Items: []corev1.KeyToPath{
+											{
+												Key:  awsCredentialsFileKey,
+												Path: awsCredentialsFileName,
+											},
+										},
+									},
+								},
+							},
+							{
+								Name: "bound-sa-token",
+								VolumeSource: corev1.VolumeSource{
+									Projected: &corev1.ProjectedVolumeSource{
+										Sources: []corev1.VolumeProjection{
+											{
+												ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+													Audience:          "openshift",
+													ExpirationSeconds: ptr.To[int64](3600),
+													Path:              "token",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						Containers: []corev1.Container{
+							{
+								Name:  ExternalDNSContainerName,
+								Image: test.OperandImage,
+								Args: []string{
+									"--metrics-address=127.0.0.1:7979",
+									"--txt-owner-id=external-dns-test",
+									"--zone-id-filter=my-dns-public-zone",
+									"--provider=aws",
+									"--source=service",
+									"--policy=sync",
+									"--registry=txt",
+									"--log-level=debug",
+									"--service-type-filter=NodePort",
+									"--service-type-filter=LoadBalancer",
+									"--service-type-filter=ClusterIP",
+									"--service-type-filter=ExternalName",
+									"--publish-internal-services",
+									"--ignore-hostname-annotation",
+									"--fqdn-template={{.Name}}.test.com",
+									"--txt-prefix=external-dns-",
+									"--aws-prefer-cname",
+								},
+								Env: []corev1.EnvVar{
+									{
+										Name:  "AWS_REGION",
+										Value: "us-gov-west-1",
+									},
+									{
+										Name:  "AWS_SHARED_CREDENTIALS_FILE",
+										Value: "/etc/kubernetes/aws-credentials",
+									},
+								},
+								VolumeMounts: []corev1.VolumeMount{
+									{
+										Name:      awsCredentialsVolumeName,
+										MountPath: awsCredentialsMountPath,
+										ReadOnly:  true,
+									},
+									{
+										Name:      "bound-sa-token",
+										MountPath: "/var/run/secrets/openshift/serviceaccount",
+										ReadOnly:  true,
+									},
+								},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities: &corev1.Capabilities{
+										Drop: []corev1.Capability{allCapabilities},
+									},
+									Privileged:               ptr.To[bool](false),
+									RunAsNonRoot:             ptr.To[bool](true),
+									AllowPrivilegeEscalation: ptr.To[bool](false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:                "Region set AWS commercial",
+			inputSecretName:     awsSecret,
+			inputExternalDNS:    testAWSExternalDNSRegion(operatorv1beta1.SourceTypeService, "eu-west-1"),
+			inputPlatformStatus: testPlatformStatusAWSGov("us-east-1"),
+			expectedSpec: appsv1.DeploymentSpec{
+				Replicas: &one,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "external-dns",
+						"app.kubernetes.io/instance": "test",
+					},
+				},
+				Strategy: appsv1.DeploymentStrategy{
+					Type: "Recreate",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app.kubernetes.io/name":     "external-dns",
+							"app.kubernetes.io/instance": "test",
+						},
+						Annotations: map[string]string{
+							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: test.OperandName,
+						NodeSelector: map[string]string{
+							osLabel: linuxOS,
+						},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      masterNodeRoleLabel,
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+						Volumes: []corev1.Volume{
+							{
+								Name: awsCredentialsVolumeName,
+								VolumeSource: corev1.VolumeSource{
+									Secret: &corev1.SecretVolumeSource{
+										SecretName: "awssecret",
+										Items: []corev1.KeyToPath{
+											{
+												Key:  awsCredentialsFileKey,
+												Path: awsCredentialsFileName,
+											},
+										},
+									},
+								},
+							},
+							{
+								Name: "bound-sa-token",
+								VolumeSource: corev1.VolumeSource{
+									Projected: &corev1.ProjectedVolumeSource{
+										Sources: []corev1.VolumeProjection{
+											{
+												ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+													Audience:          "openshift",
+													ExpirationSeconds: ptr.To[int64](3600),
+													Path:              "token",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						Containers: []corev1.Container{
+							{
+								Name:  ExternalDNSContainerName,
+								Image: test.OperandImage,
+								Args: []string{
+									"--metrics-address=127.0.0.1:7979",
+									"--txt-owner-id=external-dns-test",
+									"--zone-id-filter=my-dns-public-zone",
+									"--provider=aws",
+									"--source=service",
+									"--policy=sync",
+									"--registry=txt",
+									"--log-level=debug",
+									"--service-type-filter=NodePort",
+									"--service-type-filter=LoadBalancer",
+									"--service-type-filter=ClusterIP",
+									"--service-type-filter=ExternalName",
+									"--publish-internal-services",
+									"--ignore-hostname-annotation",
+									"--fqdn-template={{.Name}}.test.com",
+									"--txt-prefix=external-dns-",
+								},
+								Env: []corev1.EnvVar{
+									{
+										Name:  "AWS_REGION",
+										Value: "eu-west-1",
+									},
+									{
+										Name:  "AWS_SHARED_CREDENTIALS_FILE",
+										Value: "/etc/kubernetes/aws-credentials",
+									},
+								},
+								VolumeMounts: []corev1.VolumeMount{
+									{
+										Name:      awsCredentialsVolumeName,
+										MountPath: awsCredentialsMountPath,
+										ReadOnly:  true,
+									},
+									{
+										Name:      "bound-sa-token",
+										MountPath: "/var/run/secrets/openshift/serviceaccount",
+										ReadOnly:  true,
+									},
+								},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities: &corev1.Capabilities{
+										Drop: []corev1.Capability{allCapabilities},
+									},
+									Privileged:               ptr.To[bool](false),
+									RunAsNonRoot:             ptr.To[bool](true),
+									AllowPrivilegeEscalation: ptr.To[bool](false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:             "Nominal Azure",
 			inputSecretName:  azureSecret,
@@ -1206,6 +1472,87 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:                "Host project and options GCP on OpenShift",
+			inputExternalDNS:    testGCPExternalDNSOptions(operatorv1beta1.SourceTypeService, "external-dns-gcp-host-project"),
+			inputIsOpenShift:    true,
+			inputPlatformStatus: testPlatformStatusGCP("external-dns-gcp-project"),
+			expectedSpec: appsv1.DeploymentSpec{
+				Replicas: &one,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "external-dns",
+						"app.kubernetes.io/instance": "test",
+					},
+				},
+				Strategy: appsv1.DeploymentStrategy{
+					Type: "Recreate",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app.kubernetes.io/name":     "external-dns",
+							"app.kubernetes.io/instance": "test",
+						},
+						Annotations: map[string]string{
+							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: test.OperandName,
+						NodeSelector: map[string]string{
+							osLabel: linuxOS,
+						},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      masterNodeRoleLabel,
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+						Containers: []corev1.Container{
+							{
+								Name:  ExternalDNSContainerName,
+								Image: test.OperandImage,
+								Args: []string{
+									"--metrics-address=127.0.0.1:7979",
+									"--txt-owner-id=external-dns-test",
+									"--zone-id-filter=my-dns-public-zone",
+									"--provider=google",
+									"--source=service",
+									"--policy=sync",
+									"--registry=txt",
+									"--log-level=debug",
+									"--service-type-filter=NodePort",
+									"--service-type-filter=LoadBalancer",
+									"--service-type-filter=ClusterIP",
+									"--service-type-filter=ExternalName",
+									"--publish-internal-services",
+									"--ignore-hostname-annotation",
+									"--fqdn-template={{.Name}}.test.com",
+									"--txt-prefix=external-dns-",
+									"--google-project=external-dns-gcp-host-project",
+									"--google-zone-visibility=private",
+									"--google-batch-change-size=100",
+									"--google-batch-change-interval=5s",
+								},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities: &corev1.Capabilities{
+										Drop: []corev1.Capability{allCapabilities},
+									},
+									Privileged:               ptr.To[bool](false),
+									RunAsNonRoot:             ptr.To[bool](true),
+									AllowPrivilegeEscalation: ptr.To[bool](false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:             "Nominal Bluecat",
 			inputSecretName:  bluecatsecret,
@@ -2491,6 +2838,225 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:             "Zone RoleARN set AWS Route",
+			inputExternalDNS: testAWSExternalDNSZoneRoleARN(operatorv1beta1.SourceTypeRoute, "arn:aws:iam:123456789012:role/foo", "arn:aws:iam:210987654321:role/bar", "external-id"),
+			expectedSpec: appsv1.DeploymentSpec{
+				Replicas: &one,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "external-dns",
+						"app.kubernetes.io/instance": "test",
+					},
+				},
+				Strategy: appsv1.DeploymentStrategy{
+					Type: "Recreate",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app.kubernetes.io/name":     "external-dns",
+							"app.kubernetes.io/instance": "test",
+						},
+						Annotations: map[string]string{
+							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: test.OperandName,
+						NodeSelector: map[string]string{
+							osLabel: linuxOS,
+						},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      masterNodeRoleLabel,
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+						Containers: []corev1.Container{
+							{
+								Name:  ExternalDNSContainerName,
+								Image: test.OperandImage,
+								Args: []string{
+									"--aws-assume-role=arn:aws:iam:210987654321:role/bar",
+									"--aws-assume-role-external-id=external-id",
+									"--metrics-address=127.0.0.1:7979",
+									"--txt-owner-id=external-dns-test",
+									"--zone-id-filter=my-dns-public-zone",
+									"--provider=aws",
+									"--source=openshift-route",
+									"--policy=sync",
+									"--registry=txt",
+									"--log-level=debug",
+									"--ignore-hostname-annotation",
+									`--fqdn-template={{""}}`,
+									"--txt-prefix=external-dns-",
+								},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities: &corev1.Capabilities{
+										Drop: []corev1.Capability{allCapabilities},
+									},
+									Privileged:               ptr.To[bool](false),
+									RunAsNonRoot:             ptr.To[bool](true),
+									AllowPrivilegeEscalation: ptr.To[bool](false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:             "Zone filters set AWS Route",
+			inputExternalDNS: testAWSExternalDNSZoneFilters(operatorv1beta1.SourceTypeRoute, operatorv1beta1.AWSZoneTypePrivate, []string{"env=prod", "external-dns"}, time.Hour),
+			expectedSpec: appsv1.DeploymentSpec{
+				Replicas: &one,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "external-dns",
+						"app.kubernetes.io/instance": "test",
+					},
+				},
+				Strategy: appsv1.DeploymentStrategy{
+					Type: "Recreate",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app.kubernetes.io/name":     "external-dns",
+							"app.kubernetes.io/instance": "test",
+						},
+						Annotations: map[string]string{
+							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: test.OperandName,
+						NodeSelector: map[string]string{
+							osLabel: linuxOS,
+						},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      masterNodeRoleLabel,
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+						Containers: []corev1.Container{
+							{
+								Name:  ExternalDNSContainerName,
+								Image: test.OperandImage,
+								Args: []string{
+									"--aws-zone-type=private",
+									"--aws-zone-tags=env=prod",
+									"--aws-zone-tags=external-dns",
+									"--aws-zones-cache-duration=1h0m0s",
+									"--metrics-address=127.0.0.1:7979",
+									"--txt-owner-id=external-dns-test",
+									"--zone-id-filter=my-dns-public-zone",
+									"--provider=aws",
+									"--source=openshift-route",
+									"--policy=sync",
+									"--registry=txt",
+									"--log-level=debug",
+									"--ignore-hostname-annotation",
+									`--fqdn-template={{""}}`,
+									"--txt-prefix=external-dns-",
+								},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities: &corev1.Capabilities{
+										Drop: []corev1.Capability{allCapabilities},
+									},
+									Privileged:               ptr.To[bool](false),
+									RunAsNonRoot:             ptr.To[bool](true),
+									AllowPrivilegeEscalation: ptr.To[bool](false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:             "Routing policies and alias options set AWS Route",
+			inputExternalDNS: testAWSExternalDNSRoutingPolicies(operatorv1beta1.SourceTypeRoute, operatorv1beta1.AWSRoutingPolicyFailover, operatorv1beta1.AWSRoutingPolicySetIdentifier),
+			expectedSpec: appsv1.DeploymentSpec{
+				Replicas: &one,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "external-dns",
+						"app.kubernetes.io/instance": "test",
+					},
+				},
+				Strategy: appsv1.DeploymentStrategy{
+					Type: "Recreate",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app.kubernetes.io/name":     "external-dns",
+							"app.kubernetes.io/instance": "test",
+						},
+						Annotations: map[string]string{
+							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: test.OperandName,
+						NodeSelector: map[string]string{
+							osLabel: linuxOS,
+						},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      masterNodeRoleLabel,
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+						Containers: []corev1.Container{
+							{
+								Name:  ExternalDNSContainerName,
+								Image: test.OperandImage,
+								Args: []string{
+									"--aws-prefer-cname",
+									"--no-aws-evaluate-target-health",
+									"--annotation-filter=!external-dns.alpha.kubernetes.io/aws-weight,!external-dns.alpha.kubernetes.io/aws-region,!external-dns.alpha.kubernetes.io/aws-geolocation-continent-code,!external-dns.alpha.kubernetes.io/aws-geolocation-country-code,!external-dns.alpha.kubernetes.io/aws-geolocation-subdivision-code",
+									"--metrics-address=127.0.0.1:7979",
+									"--txt-owner-id=external-dns-test",
+									"--zone-id-filter=my-dns-public-zone",
+									"--provider=aws",
+									"--source=openshift-route",
+									"--policy=sync",
+									"--registry=txt",
+									"--log-level=debug",
+									"--ignore-hostname-annotation",
+									`--fqdn-template={{""}}`,
+									"--txt-prefix=external-dns-",
+								},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities: &corev1.Capabilities{
+										Drop: []corev1.Capability{allCapabilities},
+									},
+									Privileged:               ptr.To[bool](false),
+									RunAsNonRoot:             ptr.To[bool](true),
+									AllowPrivilegeEscalation: ptr.To[bool](false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:             "Nominal Azure Route",
 			inputSecretName:  azureSecret,
@@ -2731,7 +3297,144 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 								},
 								VolumeMounts: []corev1.VolumeMount{
 									{
-										Name:      azureConfigVolumeName,
+										Name:      azureConfigVolumeName,
+										ReadOnly:  true,
+										MountPath: defaultConfigMountPath,
+									},
+								},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities: &corev1.Capabilities{
+										Drop: []corev1.Capability{allCapabilities},
+									},
+									Privileged:               ptr.To[bool](false),
+									RunAsNonRoot:             ptr.To[bool](true),
+									AllowPrivilegeEscalation: ptr.To[bool](false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+							{
+								Name:  ExternalDNSContainerNoZones,
+								Image: test.OperandImage,
+								Args: []string{
+									"--metrics-address=127.0.0.1:7980",
+									"--txt-owner-id=external-dns-test",
+									"--provider=azure-private-dns",
+									"--source=openshift-route",
+									"--policy=sync",
+									"--registry=txt",
+									"--log-level=debug",
+									"--ignore-hostname-annotation",
+									`--fqdn-template={{""}}`,
+									"--azure-config-file=/etc/kubernetes/azure.json",
+									"--txt-prefix=external-dns-",
+									"--txt-wildcard-replacement=any",
+								},
+								VolumeMounts: []corev1.VolumeMount{
+									{
+										Name:      azureConfigVolumeName,
+										ReadOnly:  true,
+										MountPath: defaultConfigMountPath,
+									},
+								},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities: &corev1.Capabilities{
+										Drop: []corev1.Capability{allCapabilities},
+									},
+									Privileged:               ptr.To[bool](false),
+									RunAsNonRoot:             ptr.To[bool](true),
+									AllowPrivilegeEscalation: ptr.To[bool](false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:             "Nominal GCP Route",
+			inputSecretName:  gcpSecret,
+			inputExternalDNS: testGCPExternalDNS(operatorv1beta1.SourceTypeRoute),
+			expectedSpec: appsv1.DeploymentSpec{
+				Replicas: &one,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "external-dns",
+						"app.kubernetes.io/instance": "test",
+					},
+				},
+				Strategy: appsv1.DeploymentStrategy{
+					Type: "Recreate",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app.kubernetes.io/name":     "external-dns",
+							"app.kubernetes.io/instance": "test",
+						},
+						Annotations: map[string]string{
+							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: test.OperandName,
+						NodeSelector: map[string]string{
+							osLabel: linuxOS,
+						},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      masterNodeRoleLabel,
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+						Volumes: []corev1.Volume{
+							{
+								Name: gcpCredentialsVolumeName,
+								VolumeSource: corev1.VolumeSource{
+									Secret: &corev1.SecretVolumeSource{
+										SecretName: gcpSecret,
+										Items: []corev1.KeyToPath{
+											{
+												Key:  gcpCredentialsFileKey,
+												Path: gcpCredentialsFileKey,
+											},
+										},
+									},
+								},
+							},
+						},
+						Containers: []corev1.Container{
+							{
+								Name:  ExternalDNSContainerName,
+								Image: test.OperandImage,
+								Args: []string{
+									"--metrics-address=127.0.0.1:7979",
+									"--txt-owner-id=external-dns-test",
+									"--zone-id-filter=my-dns-public-zone",
+									"--provider=google",
+									"--source=openshift-route",
+									"--policy=sync",
+									"--registry=txt",
+									"--log-level=debug",
+									"--ignore-hostname-annotation",
+									`--fqdn-template={{""}}`,
+									"--google-project=external-dns-gcp-project",
+									"--txt-prefix=external-dns-",
+								},
+								Env: []corev1.EnvVar{
+									{
+										Name:  gcpAppCredentialsEnvVar,
+										Value: "/etc/kubernetes/gcp-credentials.json",
+									},
+								},
+								VolumeMounts: []corev1.VolumeMount{
+									{
+										Name:      gcpCredentialsVolumeName,
 										ReadOnly:  true,
 										MountPath: defaultConfigMountPath,
 									},
@@ -2748,29 +3451,63 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 									},
 								},
 							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:             "No project GCP Route",
+			inputExternalDNS: testGCPExternalDNSNoProject(operatorv1beta1.SourceTypeRoute),
+			expectedSpec: appsv1.DeploymentSpec{
+				Replicas: &one,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "external-dns",
+						"app.kubernetes.io/instance": "test",
+					},
+				},
+				Strategy: appsv1.DeploymentStrategy{
+					Type: "Recreate",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app.kubernetes.io/name":     "external-dns",
+							"app.kubernetes.io/instance": "test",
+						},
+						Annotations: map[string]string{
+							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: test.OperandName,
+						NodeSelector: map[string]string{
+							osLabel: linuxOS,
+						},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      masterNodeRoleLabel,
+								Operator: corev1.TolerationOpExists,
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+						Containers: []corev1.Container{
 							{
-								Name:  ExternalDNSContainerNoZones,
+								Name:  ExternalDNSContainerName,
 								Image: test.OperandImage,
 								Args: []string{
-									"--metrics-address=127.0.0.1:7980",
+									"--metrics-address=127.0.0.1:7979",
 									"--txt-owner-id=external-dns-test",
-									"--provider=azure-private-dns",
+									"--zone-id-filter=my-dns-public-zone",
+									"--provider=google",
 									"--source=openshift-route",
 									"--policy=sync",
 									"--registry=txt",
 									"--log-level=debug",
 									"--ignore-hostname-annotation",
 									`--fqdn-template={{""}}`,
-									"--azure-config-file=/etc/kubernetes/azure.json",
 									"--txt-prefix=external-dns-",
-									"--txt-wildcard-replacement=any",
-								},
-								VolumeMounts: []corev1.VolumeMount{
-									{
-										Name:      azureConfigVolumeName,
-										ReadOnly:  true,
-										MountPath: defaultConfigMountPath,
-									},
 								},
 								SecurityContext: &corev1.SecurityContext{
 									Capabilities: &corev1.Capabilities{
@@ -2790,9 +3527,9 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 			},
 		},
 		{
-			name:             "Nominal GCP Route",
-			inputSecretName:  gcpSecret,
-			inputExternalDNS: testGCPExternalDNS(operatorv1beta1.SourceTypeRoute),
+			name:             "Nominal Bluecat Route",
+			inputSecretName:  bluecatsecret,
+			inputExternalDNS: testBlueCatExternalDNS(operatorv1beta1.SourceTypeRoute),
 			expectedSpec: appsv1.DeploymentSpec{
 				Replicas: &one,
 				Selector: &metav1.LabelSelector{
@@ -2828,14 +3565,14 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 						},
 						Volumes: []corev1.Volume{
 							{
-								Name: gcpCredentialsVolumeName,
+								Name: "bluecat-config-file",
 								VolumeSource: corev1.VolumeSource{
 									Secret: &corev1.SecretVolumeSource{
-										SecretName: gcpSecret,
+										SecretName: bluecatsecret,
 										Items: []corev1.KeyToPath{
 											{
-												Key:  gcpCredentialsFileKey,
-												Path: gcpCredentialsFileKey,
+												Key:  blueCatConfigFileName,
+												Path: blueCatConfigFileName,
 											},
 										},
 									},
@@ -2850,25 +3587,19 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 									"--metrics-address=127.0.0.1:7979",
 									"--txt-owner-id=external-dns-test",
 									"--zone-id-filter=my-dns-public-zone",
-									"--provider=google",
+									"--provider=bluecat",
 									"--source=openshift-route",
 									"--policy=sync",
 									"--registry=txt",
 									"--log-level=debug",
 									"--ignore-hostname-annotation",
 									`--fqdn-template={{""}}`,
-									"--google-project=external-dns-gcp-project",
+									"--bluecat-config-file=/etc/kubernetes/bluecat.json",
 									"--txt-prefix=external-dns-",
 								},
-								Env: []corev1.EnvVar{
-									{
-										Name:  gcpAppCredentialsEnvVar,
-										Value: "/etc/kubernetes/gcp-credentials.json",
-									},
-								},
 								VolumeMounts: []corev1.VolumeMount{
 									{
-										Name:      gcpCredentialsVolumeName,
+										Name:      "bluecat-config-file",
 										ReadOnly:  true,
 										MountPath: defaultConfigMountPath,
 									},
@@ -2891,8 +3622,14 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 			},
 		},
 		{
-			name:             "No project GCP Route",
-			inputExternalDNS: testGCPExternalDNSNoProject(operatorv1beta1.SourceTypeRoute),
+			name:             "Propagate proxy settings Bluecat Route",
+			inputSecretName:  bluecatsecret,
+			inputExternalDNS: testBlueCatExternalDNS(operatorv1beta1.SourceTypeRoute),
+			inputEnvVars: map[string]string{
+				"HTTP_PROXY":  httpProxy,
+				"HTTPS_PROXY": httpsProxy,
+				"NO_PROXY":    noProxy,
+			},
 			expectedSpec: appsv1.DeploymentSpec{
 				Replicas: &one,
 				Selector: &metav1.LabelSelector{
@@ -2912,6 +3649,7 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 						},
 						Annotations: map[string]string{
 							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+							"externaldns.olm.openshift.io/proxy-hash":              testProxyHash,
 						},
 					},
 					Spec: corev1.PodSpec{
@@ -2926,6 +3664,22 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 								Effect:   corev1.TaintEffectNoSchedule,
 							},
 						},
+						Volumes: []corev1.Volume{
+							{
+								Name: "bluecat-config-file",
+								VolumeSource: corev1.VolumeSource{
+									Secret: &corev1.SecretVolumeSource{
+										SecretName: bluecatsecret,
+										Items: []corev1.KeyToPath{
+											{
+												Key:  blueCatConfigFileName,
+												Path: blueCatConfigFileName,
+											},
+										},
+									},
+								},
+							},
+						},
 						Containers: []corev1.Container{
 							{
 								Name:  ExternalDNSContainerName,
@@ -2934,15 +3688,37 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 									"--metrics-address=127.0.0.1:7979",
 									"--txt-owner-id=external-dns-test",
 									"--zone-id-filter=my-dns-public-zone",
-									"--provider=google",
+									"--provider=bluecat",
 									"--source=openshift-route",
 									"--policy=sync",
 									"--registry=txt",
 									"--log-level=debug",
 									"--ignore-hostname-annotation",
 									`--fqdn-template={{""}}`,
+									"--bluecat-config-file=/etc/kubernetes/bluecat.json",
 									"--txt-prefix=external-dns-",
 								},
+								Env: []corev1.EnvVar{
+									{
+										Name:  "HTTP_PROXY",
+										Value: httpProxy,
+									},
+									{
+										Name:  "HTTPS_PROXY",
+										Value: httpsProxy,
+									},
+									{
+										Name:  "NO_PROXY",
+										Value: noProxy,
+									},
+								},
+								VolumeMounts: []corev1.VolumeMount{
+									{
+										Name:      "bluecat-config-file",
+										ReadOnly:  true,
+										MountPath: defaultConfigMountPath,
+									},
+								},
 								SecurityContext: &corev1.SecurityContext{
 									Capabilities: &corev1.Capabilities{
 										Drop: []corev1.Capability{allCapabilities},
@@ -2961,8 +3737,7 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 			},
 		},
 		{
-			name:             "Nominal Bluecat Route",
-			inputSecretName:  bluecatsecret,
+			name:             "No credentials Bluecat Route",
 			inputExternalDNS: testBlueCatExternalDNS(operatorv1beta1.SourceTypeRoute),
 			expectedSpec: appsv1.DeploymentSpec{
 				Replicas: &one,
@@ -2997,22 +3772,6 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 								Effect:   corev1.TaintEffectNoSchedule,
 							},
 						},
-						Volumes: []corev1.Volume{
-							{
-								Name: "bluecat-config-file",
-								VolumeSource: corev1.VolumeSource{
-									Secret: &corev1.SecretVolumeSource{
-										SecretName: bluecatsecret,
-										Items: []corev1.KeyToPath{
-											{
-												Key:  blueCatConfigFileName,
-												Path: blueCatConfigFileName,
-											},
-										},
-									},
-								},
-							},
-						},
 						Containers: []corev1.Container{
 							{
 								Name:  ExternalDNSContainerName,
@@ -3028,16 +3787,8 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 									"--log-level=debug",
 									"--ignore-hostname-annotation",
 									`--fqdn-template={{""}}`,
-									"--bluecat-config-file=/etc/kubernetes/bluecat.json",
 									"--txt-prefix=external-dns-",
 								},
-								VolumeMounts: []corev1.VolumeMount{
-									{
-										Name:      "bluecat-config-file",
-										ReadOnly:  true,
-										MountPath: defaultConfigMountPath,
-									},
-								},
 								SecurityContext: &corev1.SecurityContext{
 									Capabilities: &corev1.Capabilities{
 										Drop: []corev1.Capability{allCapabilities},
@@ -3056,8 +3807,9 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 			},
 		},
 		{
-			name:             "No credentials Bluecat Route",
-			inputExternalDNS: testBlueCatExternalDNS(operatorv1beta1.SourceTypeRoute),
+			name:             "Nominal Infoblox Route",
+			inputSecretName:  infobloxsecret,
+			inputExternalDNS: testInfobloxExternalDNS(operatorv1beta1.SourceTypeRoute),
 			expectedSpec: appsv1.DeploymentSpec{
 				Replicas: &one,
 				Selector: &metav1.LabelSelector{
@@ -3099,15 +3851,42 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 									"--metrics-address=127.0.0.1:7979",
 									"--txt-owner-id=external-dns-test",
 									"--zone-id-filter=my-dns-public-zone",
-									"--provider=bluecat",
+									"--provider=infoblox",
 									"--source=openshift-route",
 									"--policy=sync",
 									"--registry=txt",
 									"--log-level=debug",
 									"--ignore-hostname-annotation",
 									`--fqdn-template={{""}}`,
+									"--infoblox-wapi-port=443",
+									"--infoblox-grid-host=gridhost.example.com",
+									"--infoblox-wapi-version=2.12.2",
 									"--txt-prefix=external-dns-",
 								},
+								Env: []corev1.EnvVar{
+									{
+										Name: "EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME",
+										ValueFrom: &corev1.EnvVarSource{
+											SecretKeyRef: &corev1.SecretKeySelector{
+												LocalObjectReference: corev1.LocalObjectReference{
+													Name: infobloxsecret,
+												},
+												Key: "EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME",
+											},
+										},
+									},
+									{
+										Name: "EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD",
+										ValueFrom: &corev1.EnvVarSource{
+											SecretKeyRef: &corev1.SecretKeySelector{
+												LocalObjectReference: corev1.LocalObjectReference{
+													Name: infobloxsecret,
+												},
+												Key: "EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD",
+											},
+										},
+									},
+								},
 								SecurityContext: &corev1.SecurityContext{
 									Capabilities: &corev1.Capabilities{
 										Drop: []corev1.Capability{allCapabilities},
@@ -3126,9 +3905,9 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 			},
 		},
 		{
-			name:             "Nominal Infoblox Route",
+			name:             "Options set Infoblox Route",
 			inputSecretName:  infobloxsecret,
-			inputExternalDNS: testInfobloxExternalDNS(operatorv1beta1.SourceTypeRoute),
+			inputExternalDNS: testInfobloxExternalDNSOptions(operatorv1beta1.SourceTypeRoute),
 			expectedSpec: appsv1.DeploymentSpec{
 				Replicas: &one,
 				Selector: &metav1.LabelSelector{
@@ -3180,6 +3959,12 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 									"--infoblox-wapi-port=443",
 									"--infoblox-grid-host=gridhost.example.com",
 									"--infoblox-wapi-version=2.12.2",
+									"--infoblox-view=internal",
+									"--infoblox-create-ptr",
+									"--infoblox-ssl-verify",
+									"--infoblox-max-results=500",
+									"--infoblox-fqdn-regex=^.*\\.example\\.com$",
+									"--infoblox-name-regex=^app-.*",
 									"--txt-prefix=external-dns-",
 								},
 								Env: []corev1.EnvVar{
@@ -3766,6 +4551,7 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 						},
 						Annotations: map[string]string{
 							"externaldns.olm.openshift.io/credentials-secret-hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+							"externaldns.olm.openshift.io/proxy-hash":              testProxyHash,
 						},
 					},
 					Spec: corev1.PodSpec{
@@ -3854,6 +4640,10 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 				tc.inputSecretName,
 				testSecretHash,
 				tc.inputTrustedCAConfigMapName, "",
+				nil,
+				nil,
+				"",
+				nil,
 			})
 			if err != nil {
 				t.Errorf("expected no error from calling desiredExternalDNSDeployment, but received %v", err)
@@ -3876,252 +4666,266 @@ func TestDesiredExternalDNSDeployment(t *testing.T) {
 	}
 }
 
-func TestExternalDNSDeploymentChanged(t *testing.T) {
-	updatedSecretHashAnnotation := make(map[string]string)
-	updatedSecretHashAnnotation[credentialsAnnotation] = "31f4ea504e2efd429769e1d09b586449f0b339eb"
+func TestContainerZones(t *testing.T) {
+	awsZoneRoles := func(mode operatorv1beta1.ZonesMode) *operatorv1beta1.ExternalDNS {
+		extDNS := testAWSExternalDNSZoneRoleARN(operatorv1beta1.SourceTypeService, "arn:aws:iam::123456789012:role/foo", "arn:aws:iam::123456789012:role/bar", "")
+		extDNS.Spec.Zones = []string{test.PublicZone, test.PrivateZone, "my-dns-other-zone"}
+		extDNS.Spec.ZonesMode = mode
+		return extDNS
+	}
+	azureZones := func(mode operatorv1beta1.ZonesMode) *operatorv1beta1.ExternalDNS {
+		extDNS := testAzureExternalDNSPrivateZones([]string{test.PublicZone, test.AzurePrivateDNSZone, test.PrivateZone}, operatorv1beta1.SourceTypeService)
+		extDNS.Spec.ZonesMode = mode
+		return extDNS
+	}
+	gcpZones := func(mode operatorv1beta1.ZonesMode) *operatorv1beta1.ExternalDNS {
+		extDNS := testGCPExternalDNS(operatorv1beta1.SourceTypeService)
+		extDNS.Spec.Zones = []string{test.PublicZone, test.PrivateZone}
+		extDNS.Spec.ZonesMode = mode
+		return extDNS
+	}
+
 	testCases := []struct {
-		description        string
-		originalDeployment *appsv1.Deployment
-		mutate             func(*appsv1.Deployment)
-		expect             bool
-		expectedDeployment *appsv1.Deployment
+		name           string
+		externalDNS    *operatorv1beta1.ExternalDNS
+		provider       string
+		expectedGroups [][]string
 	}{
 		{
-			description: "if nothing changes",
-			expect:      false,
-			mutate:      func(_ *appsv1.Deployment) {},
-		},
-		{
-			description: "if externalDNS test.OperandImage changes",
-			expect:      true,
-			mutate: func(depl *appsv1.Deployment) {
-				depl.Spec.Template.Spec.Containers[0].Image = "foo.io/test:latest"
-			},
-			expectedDeployment: testDeploymentWithContainers(testContainerWithImage("foo.io/test:latest")),
-		},
-		{
-			description: "if externalDNS container args change",
-			expect:      true,
-			mutate: func(depl *appsv1.Deployment) {
-				depl.Spec.Template.Spec.Containers[0].Args = []string{"Nada"}
-			},
-			expectedDeployment: testDeploymentWithContainers(testContainerWithArgs("Nada")),
-		},
-		{
-			description: "if externalDNS container args order changes",
-			expect:      false,
-			mutate: func(depl *appsv1.Deployment) {
-				// swap the last and the first elements
-				last := len(depl.Spec.Template.Spec.Containers[0].Args) - 1
-				tmp := depl.Spec.Template.Spec.Containers[0].Args[0]
-				depl.Spec.Template.Spec.Containers[0].Args[0] = depl.Spec.Template.Spec.Containers[0].Args[last]
-				depl.Spec.Template.Spec.Containers[0].Args[last] = tmp
-			},
-		},
-		{
-			description: "if externalDNS misses container",
-			expect:      true,
-			mutate: func(depl *appsv1.Deployment) {
-				depl.Spec.Template.Spec.Containers = append(depl.Spec.Template.Spec.Containers, testContainerWithName("second"))
-			},
-			expectedDeployment: testDeploymentWithContainers(testContainer(), testContainerWithName("second")),
+			name:           "Default mode",
+			externalDNS:    gcpZones(""),
+			provider:       externalDNSProviderTypeGCP,
+			expectedGroups: [][]string{{test.PublicZone}, {test.PrivateZone}},
 		},
 		{
-			description:        "if externalDNS has extra container",
-			expect:             true,
-			originalDeployment: testDeploymentWithContainers(testContainer(), testContainerWithName("extra")),
-			mutate: func(depl *appsv1.Deployment) {
-				depl.Spec.Template.Spec.Containers = []corev1.Container{testContainer()}
-			},
-			expectedDeployment: testDeploymentWithContainers(testContainer()),
+			name:           "PerZone",
+			externalDNS:    gcpZones(operatorv1beta1.ZonesModePerZone),
+			provider:       externalDNSProviderTypeGCP,
+			expectedGroups: [][]string{{test.PublicZone}, {test.PrivateZone}},
 		},
 		{
-			description:        "if externalDNS annotation changes",
-			expect:             true,
-			originalDeployment: testDeploymentWithContainers(testContainer()),
-			mutate: func(dep1 *appsv1.Deployment) {
-				dep1.Spec.Template.Annotations = updatedSecretHashAnnotation
-			},
-			expectedDeployment: testDeploymentWithAnnotations(updatedSecretHashAnnotation),
+			name:           "Shared",
+			externalDNS:    gcpZones(operatorv1beta1.ZonesModeShared),
+			provider:       externalDNSProviderTypeGCP,
+			expectedGroups: [][]string{{test.PublicZone, test.PrivateZone}},
 		},
 		{
-			description:        "if externalDNS annotation is not present",
-			expect:             true,
-			originalDeployment: testDeploymentWithoutAnnotations(),
-			mutate: func(dep1 *appsv1.Deployment) {
-				dep1.Spec.Template.Annotations = updatedSecretHashAnnotation
-			},
-			expectedDeployment: testDeploymentWithAnnotations(updatedSecretHashAnnotation),
+			name:           "Shared AWS zones with different assume roles",
+			externalDNS:    awsZoneRoles(operatorv1beta1.ZonesModeShared),
+			provider:       externalDNSProviderTypeAWS,
+			expectedGroups: [][]string{{test.PublicZone}, {test.PrivateZone, "my-dns-other-zone"}},
 		},
 		{
-			description:        "if externalDNS security context is added",
-			expect:             true,
-			originalDeployment: testDeploymentWithContainers(testContainer()),
-			mutate: func(dep1 *appsv1.Deployment) {
-				dep1.Spec.Template.Spec.Containers = []corev1.Container{
-					testContainerWithSecurityContext(&corev1.SecurityContext{
-						Capabilities: &corev1.Capabilities{
-							Drop: []corev1.Capability{allCapabilities},
-						},
-						Privileged:               ptr.To[bool](false),
-						RunAsNonRoot:             ptr.To[bool](true),
-						AllowPrivilegeEscalation: ptr.To[bool](false),
-						SeccompProfile: &corev1.SeccompProfile{
-							Type: corev1.SeccompProfileTypeRuntimeDefault,
-						},
-					}),
-				}
-			},
-			expectedDeployment: testDeploymentWithContainers(
-				testContainerWithSecurityContext(&corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Drop: []corev1.Capability{allCapabilities},
-					},
-					Privileged:               ptr.To[bool](false),
-					RunAsNonRoot:             ptr.To[bool](true),
-					AllowPrivilegeEscalation: ptr.To[bool](false),
-					SeccompProfile: &corev1.SeccompProfile{
-						Type: corev1.SeccompProfileTypeRuntimeDefault,
-					},
-				})),
+			name:           "Shared Azure public and private zones",
+			externalDNS:    azureZones(operatorv1beta1.ZonesModeShared),
+			provider:       externalDNSProviderTypeAzure,
+			expectedGroups: [][]string{{test.PublicZone, test.PrivateZone}, {test.AzurePrivateDNSZone}},
 		},
-		{
-			description: "if externalDNS security context is updated",
-			expect:      true,
-			originalDeployment: testDeploymentWithContainers(
-				testContainerWithSecurityContext(&corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Drop: []corev1.Capability{allCapabilities},
-					},
-					Privileged:               ptr.To[bool](false),
-					RunAsNonRoot:             ptr.To[bool](true),
-					AllowPrivilegeEscalation: ptr.To[bool](true),
-					SeccompProfile: &corev1.SeccompProfile{
-						Type: corev1.SeccompProfileTypeRuntimeDefault,
-					},
-				})),
-			mutate: func(dep1 *appsv1.Deployment) {
-				dep1.Spec.Template.Spec.Containers = []corev1.Container{
-					testContainerWithSecurityContext(&corev1.SecurityContext{
-						Capabilities: &corev1.Capabilities{
-							Drop: []corev1.Capability{allCapabilities},
-						},
-						Privileged:               ptr.To[bool](false),
-						RunAsNonRoot:             ptr.To[bool](true),
-						AllowPrivilegeEscalation: ptr.To[bool](false),
-						SeccompProfile: &corev1.SeccompProfile{
-							Type: corev1.SeccompProfileTypeRuntimeDefault,
-						},
-					}),
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expectedGroups, containerZones(tc.externalDNS, tc.provider)); diff != "" {
+				t.Errorf("unexpected zones (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDesiredExternalDNSDeploymentSharedZones(t *testing.T) {
+	extDNS := testAzureExternalDNSPrivateZones([]string{test.PublicZone, test.AzurePrivateDNSZone, test.PrivateZone}, operatorv1beta1.SourceTypeService)
+	extDNS.Spec.ZonesMode = operatorv1beta1.ZonesModeShared
+	depl, err := desiredExternalDNSDeployment(&deploymentConfig{
+		namespace:      test.OperandNamespace,
+		image:          test.OperandImage,
+		serviceAccount: serviceAccount,
+		externalDNS:    extDNS,
+		secret:         "azuresecret",
+		secretHash:     testSecretHash,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type container struct {
+		name string
+		args []string
+	}
+	containers := func(depl *appsv1.Deployment) []container {
+		conts := []container{}
+		for _, c := range depl.Spec.Template.Spec.Containers {
+			args := []string{}
+			for _, arg := range c.Args {
+				if strings.HasPrefix(arg, "--zone-id-filter=") || strings.HasPrefix(arg, "--provider=") || strings.HasPrefix(arg, "--metrics-address=") {
+					args = append(args, arg)
 				}
-			},
-			expectedDeployment: testDeploymentWithContainers(
-				testContainerWithSecurityContext(&corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Drop: []corev1.Capability{allCapabilities},
-					},
-					Privileged:               ptr.To[bool](false),
-					RunAsNonRoot:             ptr.To[bool](true),
-					AllowPrivilegeEscalation: ptr.To[bool](false),
-					SeccompProfile: &corev1.SeccompProfile{
-						Type: corev1.SeccompProfileTypeRuntimeDefault,
-					},
-				})),
-		},
-		{
-			description: "if externalDNS security context is same",
-			expect:      false,
-			originalDeployment: testDeploymentWithContainers(
-				testContainerWithSecurityContext(&corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Drop: []corev1.Capability{allCapabilities},
-					},
-					Privileged:               ptr.To[bool](false),
-					RunAsNonRoot:             ptr.To[bool](true),
-					AllowPrivilegeEscalation: ptr.To[bool](false),
-					SeccompProfile: &corev1.SeccompProfile{
-						Type: corev1.SeccompProfileTypeRuntimeDefault,
-					},
-				})),
-			mutate: func(dep *appsv1.Deployment) {
-			},
-		},
+			}
+			conts = append(conts, container{name: c.Name, args: args})
+		}
+		return conts
+	}
+	expected := []container{
 		{
-			description: "if externalDNS has extra volume",
-			expect:      false,
-			originalDeployment: testDeploymentWithVolumes(
-				testSecretVolume("testcreds", "testsecret", "creds", "/run/secrets"),
-				testConfigMapVolume("extravolume", "extra", "files", "/etc"),
-			),
-			mutate: func(dep *appsv1.Deployment) {
-				dep.Spec.Template.Spec.Volumes = []corev1.Volume{testSecretVolume("testcreds", "testsecret", "creds", "/run/secrets")}
+			name: controller.ExternalDNSContainerName(test.PublicZone + "," + test.PrivateZone),
+			args: []string{
+				"--metrics-address=127.0.0.1:7979",
+				"--provider=azure",
+				"--zone-id-filter=" + test.PublicZone,
+				"--zone-id-filter=" + test.PrivateZone,
 			},
 		},
 		{
-			description:        "if API server sets defaultMode on secret volume",
-			expect:             false,
-			originalDeployment: testDeploymentWithVolumes(testSecretVolume("testcreds", "testsecret", "creds", "/run/secrets")),
-			mutate: func(dep *appsv1.Deployment) {
-				dep.Spec.Template.Spec.Volumes[0].VolumeSource.Secret.DefaultMode = nil
+			// the name of the container with a single zone doesn't depend on the zones mode
+			name: controller.ExternalDNSContainerName(test.AzurePrivateDNSZone),
+			args: []string{
+				"--metrics-address=127.0.0.1:7980",
+				"--provider=azure-private-dns",
+				"--zone-id-filter=" + test.AzurePrivateDNSZone,
 			},
 		},
-		{
-			description:        "if API server sets defaultMode on configmap volume",
-			expect:             false,
-			originalDeployment: testDeploymentWithVolumes(testConfigMapVolume("testcerts", "testcerts", "key", "/etc/pki/trust")),
-			mutate: func(dep *appsv1.Deployment) {
-				dep.Spec.Template.Spec.Volumes[0].VolumeSource.ConfigMap.DefaultMode = nil
+	}
+	if diff := cmp.Diff(expected, containers(depl), cmp.AllowUnexported(container{})); diff != "" {
+		t.Errorf("unexpected containers (-want +got):\n%s", diff)
+	}
+}
+
+func TestDesiredExternalDNSDeploymentSourceNamespaces(t *testing.T) {
+	extDNS := testAWSExternalDNSZones([]string{test.PublicZone, test.PrivateZone}, operatorv1beta1.SourceTypeService)
+	extDNS.Spec.Source.Namespaces = []string{"tenant-a", "tenant-b"}
+	depl, err := desiredExternalDNSDeployment(&deploymentConfig{
+		namespace:      test.OperandNamespace,
+		image:          test.OperandImage,
+		serviceAccount: serviceAccount,
+		externalDNS:    extDNS,
+		secret:         "awssecret",
+		secretHash:     testSecretHash,
+		namespaces:     []string{"tenant-a", "tenant-b"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type container struct {
+		name string
+		args []string
+	}
+	containers := func(depl *appsv1.Deployment) []container {
+		conts := []container{}
+		for _, c := range depl.Spec.Template.Spec.Containers {
+			args := []string{}
+			for _, arg := range c.Args {
+				if strings.HasPrefix(arg, "--zone-id-filter=") || strings.HasPrefix(arg, "--namespace=") || strings.HasPrefix(arg, "--txt-owner-id=") || strings.HasPrefix(arg, "--metrics-address=") {
+					args = append(args, arg)
+				}
+			}
+			conts = append(conts, container{name: c.Name, args: args})
+		}
+		return conts
+	}
+	expected := []container{}
+	port := defaultMetricsStartPort
+	for _, ns := range []string{"tenant-a", "tenant-b"} {
+		for _, zone := range []string{test.PublicZone, test.PrivateZone} {
+			expected = append(expected, container{
+				name: controller.ExternalDNSNamespacedContainerName(zone, ns),
+				args: []string{
+					fmt.Sprintf("--metrics-address=127.0.0.1:%d", port),
+					"--txt-owner-id=external-dns-test-" + ns,
+					"--zone-id-filter=" + zone,
+					"--namespace=" + ns,
+				},
+			})
+			port++
+		}
+	}
+	if diff := cmp.Diff(expected, containers(depl), cmp.AllowUnexported(container{})); diff != "" {
+		t.Errorf("unexpected containers (-want +got):\n%s", diff)
+	}
+
+	if _, err := desiredExternalDNSDeployment(&deploymentConfig{
+		namespace:      test.OperandNamespace,
+		image:          test.OperandImage,
+		serviceAccount: serviceAccount,
+		externalDNS:    extDNS,
+		secret:         "awssecret",
+		secretHash:     testSecretHash,
+		namespaces:     []string{},
+	}); err == nil {
+		t.Errorf("expected an error when no source namespace is selected")
+	}
+}
+
+func TestDesiredExternalDNSDeploymentOperandConfig(t *testing.T) {
+	defaults := &operatorv1beta1.ExternalDNSConfigSpec{
+		ExternalDNSOperandConfig: operatorv1beta1.ExternalDNSOperandConfig{
+			Image: "quay.io/test/external-dns:defaults",
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
 			},
-		},
-		{
-			description: "if volumes change ordering",
-			expect:      false,
-			originalDeployment: testDeploymentWithVolumes(
-				testSecretVolume("testcreds", "testsecret", "creds", "/run/secrets"),
-				testConfigMapVolume("testconfig", "config", "files", "/etc"),
-			),
-			mutate: func(dep *appsv1.Deployment) {
-				dep.Spec.Template.Spec.Volumes = []corev1.Volume{
-					testConfigMapVolume("testconfig", "config", "files", "/etc"),
-					testSecretVolume("testcreds", "testsecret", "creds", "/run/secrets"),
-				}
+			NodePlacement: &operatorv1beta1.ExternalDNSNodePlacement{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
 			},
-		},
-		{
-			description:        "if replica count drifts",
-			originalDeployment: testDeploymentWithReplicas(2),
-			mutate: func(depl *appsv1.Deployment) {
-				depl.Spec.Replicas = &replicas
+			LogLevel: operatorv1beta1.ExternalDNSLogLevelWarning,
+			Policy:   operatorv1beta1.ExternalDNSPolicyUpsertOnly,
+			Proxy: &operatorv1beta1.ExternalDNSProxy{
+				HTTPSProxy: "https://proxy.test:3128",
+				NoProxy:    ".cluster.local",
 			},
-			expect:             true,
-			expectedDeployment: testDeployment(),
 		},
 	}
+	extDNS := testAWSExternalDNS(operatorv1beta1.SourceTypeService)
+	extDNS.Spec.Image = "quay.io/test/external-dns:override"
+	extDNS.Spec.Registry = &operatorv1beta1.ExternalDNSRegistry{TXTPrefix: "owner-"}
 
-	for _, tc := range testCases {
-		t.Run(tc.description, func(t *testing.T) {
-			original := testDeployment()
-			if tc.originalDeployment != nil {
-				original = tc.originalDeployment
-			}
+	depl, err := desiredExternalDNSDeployment(&deploymentConfig{
+		namespace:       test.OperandNamespace,
+		image:           test.OperandImage,
+		serviceAccount:  serviceAccount,
+		externalDNS:     extDNS,
+		secret:          awsSecret,
+		secretHash:      testSecretHash,
+		operandDefaults: defaults,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-			mutated := original.DeepCopy()
-			tc.mutate(mutated)
-			// original is the object that's in the API.
-			// mutated is the new desired object, which mutates the original API object based on some update:
-			// changes to the externaldns, or changes to the operator itself.
-			if changed, updated := externalDNSDeploymentChanged(original, mutated); changed != tc.expect {
-				t.Errorf("Expect externalDNSDeploymentChanged to be %t, got %t", tc.expect, changed)
-			} else if changed {
-				if changedAgain, updatedAgain := externalDNSDeploymentChanged(mutated, updated); changedAgain {
-					t.Errorf("ExternalDNSDeploymentChanged does not behave as a fixed point function")
-				} else {
-					if !reflect.DeepEqual(updatedAgain, tc.expectedDeployment) {
-						t.Errorf("Expect updated deployment to be %v, got %v", tc.expectedDeployment, updatedAgain)
-					}
-				}
+	podSpec := depl.Spec.Template.Spec
+	if diff := cmp.Diff(map[string]string{"node-role.kubernetes.io/infra": ""}, podSpec.NodeSelector); diff != "" {
+		t.Errorf("unexpected node selector (-want +got):\n%s", diff)
+	}
+	// the tolerations are not set in the config
+	if len(podSpec.Tolerations) != 1 || podSpec.Tolerations[0].Key != masterNodeRoleLabel {
+		t.Errorf("expected the default tolerations, got %v", podSpec.Tolerations)
+	}
+	if len(podSpec.Containers) != 1 {
+		t.Fatalf("expected 1 container, got %d", len(podSpec.Containers))
+	}
+	container := podSpec.Containers[0]
+	if container.Image != "quay.io/test/external-dns:override" {
+		t.Errorf("expected the image of the instance, got %q", container.Image)
+	}
+	if diff := cmp.Diff(*defaults.Resources, container.Resources); diff != "" {
+		t.Errorf("unexpected resources (-want +got):\n%s", diff)
+	}
+	for _, arg := range []string{"--log-level=warning", "--policy=upsert-only", "--txt-prefix=owner-"} {
+		found := false
+		for _, containerArg := range container.Args {
+			if containerArg == arg {
+				found = true
 			}
-		})
+		}
+		if !found {
+			t.Errorf("expected arg %q, got %v", arg, container.Args)
+		}
+	}
+	expectedEnv := []corev1.EnvVar{
+		{Name: awsCredentialEnvVarName, Value: awsCredentialsFilePath},
+		{Name: httpsProxyEnvVar, Value: "https://proxy.test:3128"},
+		{Name: noProxyEnvVar, Value: ".cluster.local"},
+	}
+	if diff := cmp.Diff(expectedEnv, container.Env, cmpopts.SortSlices(func(a, b corev1.EnvVar) bool { return a.Name < b.Name })); diff != "" {
+		t.Errorf("unexpected env (-want +got):\n%s", diff)
 	}
 }
 
@@ -5900,20 +6704,6 @@ func TestEnsureExternalDNSDeployment(t *testing.T) {
 										},
 									},
 								},
-								{
-									Name: "unsolicited-vol",
-									VolumeSource: corev1.VolumeSource{
-										Secret: &corev1.SecretVolumeSource{
-											SecretName: "secretname",
-											Items: []corev1.KeyToPath{
-												{
-													Key:  "key",
-													Path: "path",
-												},
-											},
-										},
-									},
-								},
 								{
 									Name: "bound-sa-token",
 									VolumeSource: corev1.VolumeSource{
@@ -5959,11 +6749,6 @@ func TestEnsureExternalDNSDeployment(t *testing.T) {
 											MountPath: awsCredentialsMountPath,
 											ReadOnly:  true,
 										},
-										{
-											Name:      "unsolicited-vm",
-											MountPath: "somepath",
-											ReadOnly:  true,
-										},
 										{
 											Name:      "bound-sa-token",
 											MountPath: "/var/run/secrets/openshift/serviceaccount",
@@ -5992,14 +6777,14 @@ func TestEnsureExternalDNSDeployment(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build()
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).WithInterceptorFuncs(test.ServerSideApply).Build()
 			r := &reconciler{
 				client: cl,
 				scheme: test.Scheme,
 				log:    zap.New(zap.UseDevMode(true)),
 			}
 
-			gotExist, gotDepl, err := r.ensureExternalDNSDeployment(context.TODO(), test.OperandNamespace, test.OperandImage, serviceAccount, tc.credSecret, tc.trustCAConfigMap, &tc.extDNS)
+			gotExist, gotDepl, _, err := r.ensureExternalDNSDeployment(context.TODO(), test.OperandNamespace, test.OperandImage, serviceAccount, tc.credSecret, tc.trustCAConfigMap, nil, nil, &tc.extDNS)
 			if err != nil {
 				if !tc.errExpected {
 					t.Fatalf("unexpected error received: %v", err)
@@ -6073,499 +6858,6 @@ func TestBuildSecretHash(t *testing.T) {
 	}
 }
 
-func TestSecurityContextChanged(t *testing.T) {
-	for _, tc := range []struct {
-		name      string
-		currentSC *corev1.SecurityContext
-		desiredSC *corev1.SecurityContext
-		updatedSC *corev1.SecurityContext
-		changed   bool
-	}{
-		{
-			name:      "current RunAsNonRoot is nil",
-			currentSC: &corev1.SecurityContext{},
-			desiredSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			// should be ignored to handle defaulting
-			name:      "desired RunAsNonRoot is nil",
-			currentSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](false)},
-			desiredSC: &corev1.SecurityContext{},
-			updatedSC: &corev1.SecurityContext{},
-			changed:   false,
-		},
-		{
-			name:      "RunAsNonRoot changes true->false",
-			currentSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			name:      "RunAsNonRoot changes false->true",
-			currentSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			name:      "RunAsNonRoot changes is same",
-			currentSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](true)},
-			updatedSC: &corev1.SecurityContext{RunAsNonRoot: ptr.To[bool](true)},
-			changed:   false,
-		},
-		{
-			name:      "current Privileged is nil",
-			currentSC: &corev1.SecurityContext{},
-			desiredSC: &corev1.SecurityContext{Privileged: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{Privileged: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			// should be ignored to handle defaulting
-			name:      "desired Privileged is nil",
-			desiredSC: &corev1.SecurityContext{},
-			currentSC: &corev1.SecurityContext{Privileged: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{Privileged: ptr.To[bool](false)},
-			changed:   false,
-		},
-		{
-			name:      "Privileged changes true->false",
-			currentSC: &corev1.SecurityContext{Privileged: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{Privileged: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{Privileged: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			name:      "Privileged changes false->true",
-			currentSC: &corev1.SecurityContext{Privileged: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{Privileged: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{Privileged: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			name:      "Privileged is same",
-			currentSC: &corev1.SecurityContext{Privileged: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{Privileged: ptr.To[bool](true)},
-			updatedSC: &corev1.SecurityContext{Privileged: ptr.To[bool](true)},
-			changed:   false,
-		},
-		{
-			name:      "current AllowPrivilegeEscalation is nil",
-			currentSC: &corev1.SecurityContext{},
-			desiredSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			// should be ignored to handle defaulting
-			name:      "desired AllowPrivilegeEscalation is nil",
-			desiredSC: &corev1.SecurityContext{},
-			currentSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](false)},
-			changed:   false,
-		},
-		{
-			name:      "AllowPrivilegeEscalation changes true->false",
-			currentSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			name:      "AllowPrivilegeEscalation changes false->true",
-			currentSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](false)},
-			updatedSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](false)},
-			changed:   true,
-		},
-		{
-			name:      "AllowPrivilegeEscalation is same",
-			currentSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](true)},
-			desiredSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](true)},
-			updatedSC: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To[bool](true)},
-			changed:   false,
-		},
-		{
-			name:      "Add Capabilities are the same",
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"C", "B", "A"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"C", "B", "A"}}},
-			changed:   false,
-		},
-		{
-			name:      "Add Capabilities are the different",
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"C", "B", "C"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"C", "B", "C"}}},
-			changed:   true,
-		},
-		{
-			name:      "current Capabilities are nil",
-			currentSC: &corev1.SecurityContext{},
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			changed:   true,
-		},
-		{
-			// ignore the desired because the capabilities might be defaulting or set by something else.
-			name:      "desired Capabilities are nil",
-			desiredSC: &corev1.SecurityContext{},
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			changed:   false,
-		},
-		{
-			name:      "current Add Capabilities are nil",
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{}},
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			changed:   true,
-		},
-		{
-			name:      "desired Add Capabilities are nil",
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{}},
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"A", "B", "C"}}},
-			changed:   true,
-		},
-		{
-			name:      "Drop Capabilities are the same",
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"A", "B", "C"}}},
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"C", "B", "A"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"C", "B", "A"}}},
-			changed:   false,
-		},
-		{
-			name:      "Drop Capabilities are the different",
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"A", "B", "C"}}},
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"C", "B", "C"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"C", "B", "C"}}},
-			changed:   true,
-		},
-		{
-			name:      "current Drop Capabilities are nil",
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{}},
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"A", "B", "C"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"A", "B", "C"}}},
-			changed:   true,
-		},
-		{
-			name:      "desired Drop Capabilities are nil",
-			desiredSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{}},
-			currentSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"A", "B", "C"}}},
-			updatedSC: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"A", "B", "C"}}},
-			changed:   true,
-		},
-		{
-			name:      "current SeccompProfile is nil",
-			currentSC: &corev1.SecurityContext{},
-			desiredSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost}},
-			updatedSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost}},
-			changed:   true,
-		},
-		{
-			// ignore the desired seccompprofile if it is being defaulted elsewhere
-			name:      "desired SeccompProfile is nil",
-			desiredSC: &corev1.SecurityContext{},
-			currentSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost}},
-			updatedSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost}},
-			changed:   false,
-		},
-		{
-			name:      "SeccompProfile is different",
-			currentSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
-			desiredSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost}},
-			updatedSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost}},
-			changed:   true,
-		},
-		{
-			name:      "SeccompProfile is same",
-			currentSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
-			desiredSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
-			updatedSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
-			changed:   false,
-		},
-		{
-			name:      "SeccompProfile is empty",
-			currentSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{}},
-			desiredSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
-			updatedSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
-			changed:   true,
-		},
-		{
-			name:      "Only update SeccompProfile in security context",
-			currentSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{}},
-			desiredSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
-			updatedSC: &corev1.SecurityContext{
-				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
-				RunAsUser:      ptr.To[int64](1007),
-			},
-			changed: true,
-		},
-		{
-			name:      "Don't update security context if diff in other fields",
-			currentSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
-			desiredSC: &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}, RunAsUser: ptr.To[int64](1007)},
-			updatedSC: &corev1.SecurityContext{
-				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
-			},
-			changed: false,
-		},
-	} {
-		t.Run(tc.name, func(t *testing.T) {
-			changed, updated := securityContextChanged(tc.currentSC, tc.updatedSC, tc.desiredSC)
-			if changed != tc.changed {
-				t.Errorf("expected %v, instead was %v", tc.changed, changed)
-			}
-
-			if tc.changed {
-				if !equality.Semantic.DeepDerivative(tc.desiredSC, updated) {
-					t.Errorf("expected %v, instead was %v", tc.desiredSC, updated)
-				}
-
-				if !equality.Semantic.DeepDerivative(tc.updatedSC, updated) {
-					t.Errorf("expected %v, instead was %v", tc.updatedSC, updated)
-				}
-			}
-		})
-	}
-}
-
-func TestDeploymentStrategyChanged(t *testing.T) {
-	twentyPercent := intstr.FromString("20%")
-
-	for _, tc := range []struct {
-		name    string
-		current *appsv1.Deployment
-		desired *appsv1.Deployment
-		changed bool
-	}{
-		{
-			name:    "Recreate added for first time",
-			current: &appsv1.Deployment{},
-			desired: &appsv1.Deployment{
-				Spec: appsv1.DeploymentSpec{
-					Strategy: appsv1.DeploymentStrategy{
-						Type: "Recreate",
-					},
-				},
-			},
-			changed: true,
-		},
-		{
-			name: "Changed from non empty RollingUpdate to Recreate",
-			current: &appsv1.Deployment{
-				Spec: appsv1.DeploymentSpec{
-					Strategy: appsv1.DeploymentStrategy{
-						Type: "RollingUpdate",
-						RollingUpdate: &appsv1.RollingUpdateDeployment{
-							MaxUnavailable: &twentyPercent,
-							MaxSurge:       &twentyPercent,
-						},
-					},
-				},
-			},
-			desired: &appsv1.Deployment{
-				Spec: appsv1.DeploymentSpec{
-					Strategy: appsv1.DeploymentStrategy{
-						Type: "Recreate",
-					},
-				},
-			},
-			changed: true,
-		},
-		{
-			name: "No changes when Recreate is expected",
-			current: &appsv1.Deployment{
-				Spec: appsv1.DeploymentSpec{
-					Strategy: appsv1.DeploymentStrategy{
-						Type: "Recreate",
-					},
-				},
-			},
-			desired: &appsv1.Deployment{
-				Spec: appsv1.DeploymentSpec{
-					Strategy: appsv1.DeploymentStrategy{
-						Type: "Recreate",
-					},
-				},
-			},
-			changed: false,
-		},
-		{
-			name: "No changes when nothing is expected",
-			current: &appsv1.Deployment{
-				Spec: appsv1.DeploymentSpec{
-					Strategy: appsv1.DeploymentStrategy{
-						Type: "Recreate",
-					},
-				},
-			},
-			desired: &appsv1.Deployment{},
-			changed: false,
-		},
-	} {
-		t.Run(tc.name, func(t *testing.T) {
-			updated := tc.current.DeepCopy()
-			changed := externalDNSDeploymentStrategyChanged(tc.current, tc.desired, updated)
-			if changed != tc.changed {
-				t.Errorf("expected deployment strategy changed to be %t, got %t", tc.changed, changed)
-			}
-
-			if tc.changed {
-				if diff := cmp.Diff(tc.desired, updated); diff != "" {
-					t.Errorf("unexpected deployment (-want +got):\n%s", diff)
-				}
-			}
-		})
-	}
-}
-
-func testDeployment() *appsv1.Deployment {
-	return &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      testName,
-			Namespace: "testns",
-		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"testlbl": "yes",
-				},
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"testlbl": "yes",
-					},
-					Annotations: map[string]string{
-						credentialsAnnotation: testSecretHash,
-					},
-				},
-				Spec: corev1.PodSpec{
-					ServiceAccountName: "testsa",
-					NodeSelector: map[string]string{
-						"testlbl": "yes",
-					},
-					Containers: []corev1.Container{testContainer()},
-				},
-			},
-		},
-	}
-}
-
-func testDeploymentWithReplicas(r int32) *appsv1.Deployment {
-	depl := testDeployment()
-	depl.Spec.Replicas = &r
-	return depl
-}
-
-func testDeploymentWithoutAnnotations() *appsv1.Deployment {
-	depl := testDeployment()
-	depl.Spec.Template.Annotations = nil
-	return depl
-}
-
-func testDeploymentWithContainers(containers ...corev1.Container) *appsv1.Deployment {
-	depl := testDeployment()
-	depl.Spec.Template.Spec.Containers = containers
-	return depl
-}
-
-func testDeploymentWithAnnotations(annotations map[string]string) *appsv1.Deployment {
-	depl := testDeployment()
-	depl.Spec.Template.Annotations = annotations
-	return depl
-}
-
-func testDeploymentWithVolumes(volumes ...corev1.Volume) *appsv1.Deployment {
-	depl := testDeployment()
-	depl.Spec.Template.Spec.Volumes = volumes
-	return depl
-}
-
-func testContainer() corev1.Container {
-	return corev1.Container{
-		Name:                     "first",
-		Image:                    test.OperandImage,
-		ImagePullPolicy:          corev1.PullIfNotPresent,
-		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
-		Args: []string{
-			"--flag1=value1",
-			"--flag2=value2",
-		},
-	}
-}
-
-func testContainerWithSecurityContext(sc *corev1.SecurityContext) corev1.Container {
-	cont := testContainer()
-	cont.SecurityContext = sc
-	return cont
-}
-
-func testContainerWithName(name string) corev1.Container {
-	cont := testContainer()
-	cont.Name = name
-	return cont
-}
-
-func testContainerWithImage(image string) corev1.Container {
-	cont := testContainer()
-	cont.Image = image
-	return cont
-}
-
-func testContainerWithArgs(args ...string) corev1.Container {
-	cont := testContainer()
-	cont.Args = args
-	return cont
-}
-
-func testConfigMapVolume(name, cmname, key, path string) corev1.Volume {
-	mode := int32(0644)
-	return corev1.Volume{
-		Name: name,
-		VolumeSource: corev1.VolumeSource{
-			ConfigMap: &corev1.ConfigMapVolumeSource{
-				LocalObjectReference: corev1.LocalObjectReference{
-					Name: cmname,
-				},
-				Items: []corev1.KeyToPath{
-					{
-						Key:  key,
-						Path: path,
-					},
-				},
-				DefaultMode: &mode,
-			},
-		},
-	}
-}
-
-func testSecretVolume(name, sname, key, path string) corev1.Volume {
-	mode := int32(0644)
-	return corev1.Volume{
-		Name: name,
-		VolumeSource: corev1.VolumeSource{
-			Secret: &corev1.SecretVolumeSource{
-				SecretName: sname,
-				Items: []corev1.KeyToPath{
-					{
-						Key:  key,
-						Path: path,
-					},
-				},
-				DefaultMode: &mode,
-			},
-		},
-	}
-}
-
 func testExternalDNSInstance(provider operatorv1beta1.ExternalDNSProviderType,
 	source operatorv1beta1.ExternalDNSSourceType,
 	svcType []corev1.ServiceType,
@@ -6714,6 +7006,17 @@ func testCreateDNSFromSourceWRTCloudProvider(source operatorv1beta1.ExternalDNSS
 	return nil
 }
 
+func testGCPExternalDNSOptions(source operatorv1beta1.ExternalDNSSourceType, project string) *operatorv1beta1.ExternalDNS {
+	extdns := testCreateDNSFromSourceWRTCloudProvider(source, operatorv1beta1.ProviderTypeGCP, nil, "")
+	extdns.Spec.Provider.GCP = &operatorv1beta1.ExternalDNSGCPProviderOptions{
+		Project:             &project,
+		ZoneVisibility:      operatorv1beta1.GCPZoneVisibilityPrivate,
+		BatchChangeSize:     ptr.To[int32](100),
+		BatchChangeInterval: &metav1.Duration{Duration: 5 * time.Second},
+	}
+	return extdns
+}
+
 func testGCPExternalDNSNoProject(source operatorv1beta1.ExternalDNSSourceType) *operatorv1beta1.ExternalDNS {
 	return testCreateDNSFromSourceWRTCloudProvider(source, operatorv1beta1.ProviderTypeGCP, nil, "")
 }
@@ -6732,6 +7035,17 @@ func testInfobloxExternalDNS(source operatorv1beta1.ExternalDNSSourceType) *oper
 	return extdns
 }
 
+func testInfobloxExternalDNSOptions(source operatorv1beta1.ExternalDNSSourceType) *operatorv1beta1.ExternalDNS {
+	extdns := testInfobloxExternalDNS(source)
+	extdns.Spec.Provider.Infoblox.View = "internal"
+	extdns.Spec.Provider.Infoblox.CreatePTR = true
+	extdns.Spec.Provider.Infoblox.SSLVerify = ptr.To[bool](true)
+	extdns.Spec.Provider.Infoblox.MaxResults = ptr.To[int32](500)
+	extdns.Spec.Provider.Infoblox.FQDNRegex = `^.*\.example\.com$`
+	extdns.Spec.Provider.Infoblox.NameRegex = "^app-.*"
+	return extdns
+}
+
 func testAWSExternalDNSDomainFilter(zones []string, source operatorv1beta1.ExternalDNSSourceType) *operatorv1beta1.ExternalDNS {
 	extdns := testCreateDNSFromSourceWRTCloudProvider(source, operatorv1beta1.ProviderTypeAWS, zones, "")
 	extdns.Spec.Domains = []operatorv1beta1.ExternalDNSDomain{
@@ -6756,6 +7070,42 @@ func testAWSExternalDNSRoleARN(source operatorv1beta1.ExternalDNSSourceType, rol
 	return extdns
 }
 
+func testAWSExternalDNSZoneRoleARN(source operatorv1beta1.ExternalDNSSourceType, roleARN, zoneRoleARN, externalID string) *operatorv1beta1.ExternalDNS {
+	extdns := testAWSExternalDNSRoleARN(source, roleARN)
+	extdns.Spec.Provider.AWS.ZoneAssumeRoles = []operatorv1beta1.ExternalDNSAWSZoneAssumeRoleOptions{
+		{
+			ZoneID: test.PublicZone,
+			ExternalDNSAWSAssumeRoleOptions: operatorv1beta1.ExternalDNSAWSAssumeRoleOptions{
+				ARN:        zoneRoleARN,
+				ExternalID: externalID,
+			},
+		},
+	}
+	return extdns
+}
+
+func testAWSExternalDNSZoneFilters(source operatorv1beta1.ExternalDNSSourceType, zoneType operatorv1beta1.ExternalDNSAWSZoneType, zoneTags []string, cacheDuration time.Duration) *operatorv1beta1.ExternalDNS {
+	extdns := testCreateDNSFromSourceWRTCloudProvider(source, operatorv1beta1.ProviderTypeAWS, nil, "")
+	extdns.Spec.Provider.AWS = &operatorv1beta1.ExternalDNSAWSProviderOptions{
+		ZoneType:           zoneType,
+		ZoneTags:           zoneTags,
+		ZonesCacheDuration: &metav1.Duration{Duration: cacheDuration},
+	}
+	return extdns
+}
+
+func testAWSExternalDNSRoutingPolicies(source operatorv1beta1.ExternalDNSSourceType, allowed ...operatorv1beta1.ExternalDNSAWSRoutingPolicy) *operatorv1beta1.ExternalDNS {
+	extdns := testCreateDNSFromSourceWRTCloudProvider(source, operatorv1beta1.ProviderTypeAWS, nil, "")
+	extdns.Spec.Provider.AWS = &operatorv1beta1.ExternalDNSAWSProviderOptions{
+		EvaluateTargetHealth: ptr.To[bool](false),
+		PreferCNAME:          ptr.To[bool](true),
+		RoutingPolicies: &operatorv1beta1.ExternalDNSAWSRoutingPolicyOptions{
+			Allowed: allowed,
+		},
+	}
+	return extdns
+}
+
 func testPlatformStatusGCP(projectID string) *configv1.PlatformStatus {
 	return &configv1.PlatformStatus{
 		Type: configv1.GCPPlatformType,
@@ -6765,6 +7115,15 @@ func testPlatformStatusGCP(projectID string) *configv1.PlatformStatus {
 	}
 }
 
+func testAWSExternalDNSRegion(source operatorv1beta1.ExternalDNSSourceType, region string) *operatorv1beta1.ExternalDNS {
+	extdns := testAWSExternalDNS(source)
+	extdns.Spec.Provider.AWS = &operatorv1beta1.ExternalDNSAWSProviderOptions{
+		Credentials: operatorv1beta1.SecretReference{Name: test.OperandName},
+		Region:      region,
+	}
+	return extdns
+}
+
 func testPlatformStatusAWSGov(region string) *configv1.PlatformStatus {
 	return &configv1.PlatformStatus{
 		Type: configv1.AWSPlatformType,
@@ -0,0 +1,257 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+)
+
+const (
+	// dryRunPlanConfigMapKey is the key of the plan configmap which lists the planned changes.
+	dryRunPlanConfigMapKey = "plan"
+	// dryRunPlanStatusMaxChanges limits the number of changes shown in the status.
+	dryRunPlanStatusMaxChanges = 10
+	// dryRunPlanConfigMapMaxChanges limits the number of changes listed in the configmap.
+	dryRunPlanConfigMapMaxChanges = 1000
+	// dryRunLogTailLines is the number of the last log lines scanned for the planned changes.
+	// ExternalDNS logs the whole plan on every sync as the changes are never applied.
+	dryRunLogTailLines = int64(2000)
+	// dryRunPlanRefreshPeriod is the period of the plan refresh, matches the default ExternalDNS sync interval.
+	dryRunPlanRefreshPeriod = time.Minute
+
+	dryRunActionCreate = "CREATE"
+	dryRunActionUpdate = "UPDATE"
+	dryRunActionDelete = "DELETE"
+)
+
+// dryRunChangeRegexps match the planned changes logged by the providers in the dry run mode.
+// The submatches are the action, the record name and the record type.
+var dryRunChangeRegexps = []struct {
+	re                  *regexp.Regexp
+	action, name, rtype int
+}{
+	// AWS: Desired change: CREATE app.example.com A [Id: /hostedzone/Z1]
+	{re: regexp.MustCompile(`Desired change: (CREATE|UPSERT|DELETE) (\S+) (\S+)`), action: 1, name: 2, rtype: 3},
+	// Azure, Infoblox: Would create A record named 'app' for Azure DNS zone 'example.com'.
+	{re: regexp.MustCompile(`Would (create|update|delete) (\S+) record named '([^']*)'`), action: 1, name: 3, rtype: 2},
+	// GCP: Add records: app.example.com. A [10.0.0.1] 300
+	{re: regexp.MustCompile(`(Add|Del) records: (\S+) (\S+)`), action: 1, name: 2, rtype: 3},
+}
+
+// podLogReader reads the logs of the pods.
+type podLogReader interface {
+	// ReadLogs returns the last lines of the logs of the given container.
+	ReadLogs(ctx context.Context, namespace, pod, container string, tailLines int64) (string, error)
+}
+
+// clientsetPodLogReader reads the pod logs using the pods/log subresource.
+type clientsetPodLogReader struct {
+	clientset kubernetes.Interface
+}
+
+// ReadLogs returns the last lines of the logs of the given container.
+func (c *clientsetPodLogReader) ReadLogs(ctx context.Context, namespace, pod, container string, tailLines int64) (string, error) {
+	data, err := c.clientset.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{Container: container, TailLines: &tailLines}).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ensureDryRunPlan ensures that the changes planned by the operand in the dry run mode
+// are collected from its logs and listed in the plan configmap.
+// The plan configmap is removed when the dry run mode is off.
+// Returns the summary of the plan for the status, nil if the dry run mode is off or no operand pod is running yet.
+func (r *reconciler) ensureDryRunPlan(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, deployment *appsv1.Deployment) (*operatorv1beta1.ExternalDNSDryRunPlan, error) {
+	nsName := controller.ExternalDNSDryRunPlanConfigMapName(r.config.Namespace, externalDNS.Name)
+
+	if !externalDNS.Spec.DryRun {
+		cm := &corev1.ConfigMap{}
+		cm.Namespace, cm.Name = nsName.Namespace, nsName.Name
+		if err := r.client.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete dry run plan configmap %s: %w", nsName, err)
+		}
+		return nil, nil
+	}
+
+	if deployment == nil || r.podLogs == nil {
+		return nil, nil
+	}
+
+	changes, collected, err := r.collectDryRunChanges(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+	if !collected {
+		return nil, nil
+	}
+
+	plan := summarizeDryRunChanges(changes)
+	plan.ConfigMapName = nsName.Name
+
+	if len(changes) > dryRunPlanConfigMapMaxChanges {
+		changes = changes[:dryRunPlanConfigMapMaxChanges]
+	}
+	desired := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nsName.Name,
+			Namespace: nsName.Namespace,
+			Labels: map[string]string{
+				appNameLabel:     controller.ExternalDNSBaseName,
+				appInstanceLabel: externalDNS.Name,
+			},
+		},
+		Data: map[string]string{
+			dryRunPlanConfigMapKey: strings.Join(changes, "\n"),
+		},
+	}
+	if err := controllerutil.SetControllerReference(externalDNS, desired, r.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set the controller reference for dry run plan configmap: %w", err)
+	}
+
+	current := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, nsName, current); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get dry run plan configmap %s: %w", nsName, err)
+		}
+		if err := r.client.Create(ctx, desired); err != nil {
+			if errors.IsAlreadyExists(err) {
+				// the cache doesn't have the configmap created by the previous reconciliation yet,
+				// the plan is updated by the next reconciliation
+				return plan, nil
+			}
+			return nil, fmt.Errorf("failed to create dry run plan configmap %s: %w", nsName, err)
+		}
+		r.log.Info("created dry run plan configmap", "namespace", nsName.Namespace, "name", nsName.Name)
+		return plan, nil
+	}
+
+	if !reflect.DeepEqual(current.Data, desired.Data) {
+		updated := current.DeepCopy()
+		updated.Data = desired.Data
+		if err := r.client.Update(ctx, updated); err != nil {
+			return nil, fmt.Errorf("failed to update dry run plan configmap %s: %w", nsName, err)
+		}
+		r.log.Info("updated dry run plan configmap", "namespace", nsName.Namespace, "name", nsName.Name)
+	}
+
+	return plan, nil
+}
+
+// collectDryRunChanges returns the sorted list of the changes logged by the containers of the running operand pods.
+// Returns false if no container logs could be read.
+func (r *reconciler) collectDryRunChanges(ctx context.Context, deployment *appsv1.Deployment) ([]string, bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build the selector of deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+	}
+	pods, err := getFilteredPodsList(ctx, r.client, deployment.Namespace, selector)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list pods of deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+	}
+
+	collected := false
+	unique := map[string]struct{}{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			logs, err := r.podLogs.ReadLogs(ctx, pod.Namespace, pod.Name, container.Name, dryRunLogTailLines)
+			if err != nil {
+				r.log.Error(err, "failed to read the operand logs", "namespace", pod.Namespace, "pod", pod.Name, "container", container.Name)
+				continue
+			}
+			collected = true
+			for _, change := range parseDryRunChanges(logs) {
+				unique[change] = struct{}{}
+			}
+		}
+	}
+
+	changes := make([]string, 0, len(unique))
+	for change := range unique {
+		changes = append(changes, change)
+	}
+	sort.Strings(changes)
+	return changes, collected, nil
+}
+
+// parseDryRunChanges returns the changes found in the given logs
+// in the "<action> <name> <type>" format.
+func parseDryRunChanges(logs string) []string {
+	changes := []string{}
+	for _, line := range strings.Split(logs, "\n") {
+		for _, m := range dryRunChangeRegexps {
+			sub := m.re.FindStringSubmatch(line)
+			if sub == nil {
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("%s %s %s", normalizeDryRunAction(sub[m.action]), sub[m.name], sub[m.rtype]))
+			break
+		}
+	}
+	return changes
+}
+
+// normalizeDryRunAction maps the actions logged by the providers to the common ones.
+func normalizeDryRunAction(action string) string {
+	switch strings.ToLower(action) {
+	case "create", "add":
+		return dryRunActionCreate
+	case "upsert", "update":
+		return dryRunActionUpdate
+	default:
+		return dryRunActionDelete
+	}
+}
+
+// summarizeDryRunChanges counts the given changes by action.
+func summarizeDryRunChanges(changes []string) *operatorv1beta1.ExternalDNSDryRunPlan {
+	plan := &operatorv1beta1.ExternalDNSDryRunPlan{}
+	for _, change := range changes {
+		switch strings.SplitN(change, " ", 2)[0] {
+		case dryRunActionCreate:
+			plan.Create++
+		case dryRunActionUpdate:
+			plan.Update++
+		case dryRunActionDelete:
+			plan.Delete++
+		}
+	}
+	if len(changes) > dryRunPlanStatusMaxChanges {
+		plan.Changes = append([]string{}, changes[:dryRunPlanStatusMaxChanges]...)
+	} else if len(changes) > 0 {
+		plan.Changes = append([]string{}, changes...)
+	}
+	return plan
+}
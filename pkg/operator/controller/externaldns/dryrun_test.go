@@ -0,0 +1,270 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+// fakePodLogReader returns the logs from the map keyed by "<pod>/<container>".
+type fakePodLogReader map[string]string
+
+func (f fakePodLogReader) ReadLogs(_ context.Context, _, pod, container string, _ int64) (string, error) {
+	logs, found := f[pod+"/"+container]
+	if !found {
+		return "", fmt.Errorf("container %s/%s not found", pod, container)
+	}
+	return logs, nil
+}
+
+func TestParseDryRunChanges(t *testing.T) {
+	testCases := []struct {
+		name            string
+		logs            string
+		expectedChanges []string
+	}{
+		{
+			name: "AWS",
+			logs: `time="2024-01-01T00:00:00Z" level=info msg="Applying provider record filter for domains: [example.com. .example.com.]"
+time="2024-01-01T00:00:00Z" level=info msg="Desired change: CREATE app.example.com A [Id: /hostedzone/Z1]"
+time="2024-01-01T00:00:00Z" level=info msg="Desired change: UPSERT api.example.com CNAME [Id: /hostedzone/Z1]"
+time="2024-01-01T00:00:00Z" level=info msg="Desired change: DELETE old.example.com A [Id: /hostedzone/Z1]"`,
+			expectedChanges: []string{
+				"CREATE app.example.com A",
+				"UPDATE api.example.com CNAME",
+				"DELETE old.example.com A",
+			},
+		},
+		{
+			name: "Azure",
+			logs: `time="2024-01-01T00:00:00Z" level=info msg="Would create A record named 'app' to '10.0.0.1' for Azure DNS zone 'example.com'."
+time="2024-01-01T00:00:00Z" level=info msg="Would delete TXT record named 'external-dns-old' for Azure DNS zone 'example.com'."`,
+			expectedChanges: []string{
+				"CREATE app A",
+				"DELETE external-dns-old TXT",
+			},
+		},
+		{
+			name: "GCP",
+			logs: `time="2024-01-01T00:00:00Z" level=info msg="Add records: app.example.com. A [10.0.0.1] 300"
+time="2024-01-01T00:00:00Z" level=info msg="Del records: old.example.com. A [10.0.0.2] 300"`,
+			expectedChanges: []string{
+				"CREATE app.example.com. A",
+				"DELETE old.example.com. A",
+			},
+		},
+		{
+			name:            "No changes",
+			logs:            `time="2024-01-01T00:00:00Z" level=info msg="All records are already up to date"`,
+			expectedChanges: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := parseDryRunChanges(tc.logs)
+			if diff := cmp.Diff(tc.expectedChanges, changes); diff != "" {
+				t.Errorf("unexpected changes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEnsureDryRunPlan(t *testing.T) {
+	const awsLogs = `time="2024-01-01T00:00:00Z" level=info msg="Desired change: CREATE app.example.com A [Id: /hostedzone/Z1]"
+time="2024-01-01T00:00:00Z" level=info msg="Desired change: CREATE app.example.com TXT [Id: /hostedzone/Z1]"
+time="2024-01-01T00:00:00Z" level=info msg="Desired change: DELETE old.example.com A [Id: /hostedzone/Z1]"
+time="2024-01-01T00:01:00Z" level=info msg="Desired change: CREATE app.example.com A [Id: /hostedzone/Z1]"
+time="2024-01-01T00:01:00Z" level=info msg="Desired change: CREATE app.example.com TXT [Id: /hostedzone/Z1]"
+time="2024-01-01T00:01:00Z" level=info msg="Desired change: DELETE old.example.com A [Id: /hostedzone/Z1]"`
+	planName := types.NamespacedName{Namespace: test.OperandNamespace, Name: "external-dns-plan-" + test.Name}
+
+	testCases := []struct {
+		name              string
+		dryRun            bool
+		existingObjects   []runtime.Object
+		logs              fakePodLogReader
+		expectedPlan      *operatorv1beta1.ExternalDNSDryRunPlan
+		expectedConfigMap map[string]string
+	}{
+		{
+			name:            "Dry run off",
+			existingObjects: []runtime.Object{testDryRunPod(corev1.PodRunning)},
+			logs:            fakePodLogReader{"external-dns-test-abcde/external-dns": awsLogs},
+		},
+		{
+			name:   "Dry run off removes the plan",
+			dryRun: false,
+			existingObjects: []runtime.Object{
+				testDryRunPod(corev1.PodRunning),
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: planName.Namespace, Name: planName.Name}},
+			},
+			logs: fakePodLogReader{"external-dns-test-abcde/external-dns": awsLogs},
+		},
+		{
+			name:            "Pod not running",
+			dryRun:          true,
+			existingObjects: []runtime.Object{testDryRunPod(corev1.PodPending)},
+			logs:            fakePodLogReader{"external-dns-test-abcde/external-dns": awsLogs},
+		},
+		{
+			name:            "Plan collected",
+			dryRun:          true,
+			existingObjects: []runtime.Object{testDryRunPod(corev1.PodRunning)},
+			logs:            fakePodLogReader{"external-dns-test-abcde/external-dns": awsLogs},
+			expectedPlan: &operatorv1beta1.ExternalDNSDryRunPlan{
+				Create:        2,
+				Delete:        1,
+				Changes:       []string{"CREATE app.example.com A", "CREATE app.example.com TXT", "DELETE old.example.com A"},
+				ConfigMapName: planName.Name,
+			},
+			expectedConfigMap: map[string]string{
+				"plan": "CREATE app.example.com A\nCREATE app.example.com TXT\nDELETE old.example.com A",
+			},
+		},
+		{
+			name:   "Plan updated",
+			dryRun: true,
+			existingObjects: []runtime.Object{
+				testDryRunPod(corev1.PodRunning),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: planName.Namespace, Name: planName.Name},
+					Data:       map[string]string{"plan": "CREATE stale.example.com A"},
+				},
+			},
+			logs: fakePodLogReader{"external-dns-test-abcde/external-dns": `level=info msg="All records are already up to date"`},
+			expectedPlan: &operatorv1beta1.ExternalDNSDryRunPlan{
+				ConfigMapName: planName.Name,
+			},
+			expectedConfigMap: map[string]string{
+				"plan": "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build()
+			r := &reconciler{
+				client:  cl,
+				scheme:  test.Scheme,
+				config:  testConfig(),
+				log:     zap.New(zap.UseDevMode(true)),
+				podLogs: tc.logs,
+			}
+			extDNS := test.NewExternalDNS(test.Name).WithAWS().Build()
+			extDNS.Spec.DryRun = tc.dryRun
+
+			plan, err := r.ensureDryRunPlan(context.TODO(), extDNS, testDryRunDeployment())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedPlan, plan); diff != "" {
+				t.Errorf("unexpected plan (-want +got):\n%s", diff)
+			}
+
+			cm := &corev1.ConfigMap{}
+			err = cl.Get(context.TODO(), planName, cm)
+			if tc.expectedConfigMap == nil {
+				if !errors.IsNotFound(err) {
+					t.Errorf("expected plan configmap not to exist, got error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get plan configmap: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedConfigMap, cm.Data); diff != "" {
+				t.Errorf("unexpected plan configmap data (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSummarizeDryRunChangesTruncated(t *testing.T) {
+	changes := []string{}
+	for i := 0; i < 15; i++ {
+		changes = append(changes, fmt.Sprintf("CREATE app%02d.example.com A", i))
+	}
+	plan := summarizeDryRunChanges(changes)
+	if plan.Create != 15 {
+		t.Errorf("expected 15 creates, got %d", plan.Create)
+	}
+	if len(plan.Changes) != dryRunPlanStatusMaxChanges {
+		t.Errorf("expected %d changes in the status, got %d", dryRunPlanStatusMaxChanges, len(plan.Changes))
+	}
+}
+
+func TestDesiredPreflightJobDryRun(t *testing.T) {
+	extDNS := test.NewExternalDNS(test.Name).WithAWS().Build()
+	deployment := testPreflightDeployment("owner")
+	deployment.Spec.Template.Spec.Containers[0].Args = append(deployment.Spec.Template.Spec.Containers[0].Args, dryRunArg)
+
+	job, err := desiredPreflightJob(extDNS, deployment, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedArgs := []string{"--provider=aws", "--txt-owner-id=owner", "--dry-run", "--once"}
+	if diff := cmp.Diff(expectedArgs, job.Spec.Template.Spec.Containers[0].Args); diff != "" {
+		t.Errorf("unexpected container args (-want +got):\n%s", diff)
+	}
+}
+
+func testDryRunDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external-dns-test",
+			Namespace: test.OperandNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{appInstanceLabel: test.Name},
+			},
+		},
+	}
+}
+
+func testDryRunPod(phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external-dns-test-abcde",
+			Namespace: test.OperandNamespace,
+			Labels:    map[string]string{appInstanceLabel: test.Name},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "external-dns"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+}
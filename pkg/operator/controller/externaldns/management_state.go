@@ -0,0 +1,61 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+)
+
+// managementState returns the management state of the given ExternalDNS,
+// the instances created before the field was introduced are managed.
+func managementState(externalDNS *operatorv1beta1.ExternalDNS) operatorv1beta1.ManagementState {
+	if len(externalDNS.Spec.ManagementState) == 0 {
+		return operatorv1beta1.ManagementStateManaged
+	}
+	return externalDNS.Spec.ManagementState
+}
+
+// ensureExternalDNSDeploymentScaledDown ensures that the externalDNS deployment has no replicas.
+// The rest of the deployment is left as it is.
+// Returns a Boolean value indicating whether the deployment exists, a pointer to the deployment, and an error when relevant.
+func (r *reconciler) ensureExternalDNSDeploymentScaledDown(ctx context.Context, namespace string, externalDNS *operatorv1beta1.ExternalDNS) (bool, *appsv1.Deployment, error) {
+	nsName := types.NamespacedName{Namespace: namespace, Name: controller.ExternalDNSResourceName(externalDNS)}
+
+	exist, current, err := r.currentExternalDNSDeployment(ctx, nsName)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get externalDNS deployment: %w", err)
+	}
+	if !exist || (current.Spec.Replicas != nil && *current.Spec.Replicas == 0) {
+		return exist, current, nil
+	}
+
+	updated := current.DeepCopy()
+	updated.Spec.Replicas = ptr.To[int32](0)
+	if err := r.client.Update(ctx, updated); err != nil {
+		return true, current, fmt.Errorf("failed to scale down externalDNS deployment %s: %w", nsName, err)
+	}
+	r.log.Info("scaled down externalDNS deployment", "namespace", nsName.Namespace, "name", nsName.Name)
+
+	return r.currentExternalDNSDeployment(ctx, nsName)
+}
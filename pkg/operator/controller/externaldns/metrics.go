@@ -0,0 +1,166 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	ctrlutils "github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
+)
+
+const (
+	metricsNamespace = "external_dns_operator"
+
+	// rolloutReasonCredentials is the reason of the rollouts triggered by the change of the credentials secret.
+	rolloutReasonCredentials = "credentials"
+	// rolloutReasonTrustedCA is the reason of the rollouts triggered by the change of the trusted CA configmap.
+	rolloutReasonTrustedCA = "trusted_ca"
+	// rolloutReasonProviderTrustedCA is the reason of the rollouts triggered by the change of the trusted CA configmap of the provider.
+	rolloutReasonProviderTrustedCA = "provider_trusted_ca"
+	// rolloutReasonProxy is the reason of the rollouts triggered by the change of the proxy settings.
+	rolloutReasonProxy = "proxy"
+)
+
+var (
+	// conditionMetric reports the status conditions of the ExternalDNS instances:
+	// the series of the current status of a condition is set to 1, the series of the other statuses to 0.
+	conditionMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "externaldns_status_condition",
+		Help:      "The status conditions of the ExternalDNS instances, 1 for the current status of the condition.",
+	}, []string{"name", "type", "status"})
+
+	zonesMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "externaldns_zones",
+		Help:      "The number of DNS zones managed by the ExternalDNS instances, 0 if all the zones are managed.",
+	}, []string{"name"})
+
+	containersMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "externaldns_containers",
+		Help:      "The number of the external-dns containers run for the ExternalDNS instances.",
+	}, []string{"name"})
+
+	credentialsSecretRotationMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "externaldns_credentials_secret_last_rotation_timestamp_seconds",
+		Help:      "The time the credentials secret of the ExternalDNS instances was last changed by the operator.",
+	}, []string{"name"})
+
+	deploymentRolloutsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "externaldns_deployment_rollouts_total",
+		Help:      "The number of the rollouts of the ExternalDNS deployments triggered by the change of the credentials secret, the trusted CA, the trusted CA of the provider or the proxy.",
+	}, []string{"name", "reason"})
+
+	credentialsRequestUpdatesMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "credentials_request_updates_total",
+		Help:      "The number of the updates of the credentials requests.",
+	}, []string{"name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		conditionMetric,
+		zonesMetric,
+		containersMetric,
+		credentialsSecretRotationMetric,
+		deploymentRolloutsMetric,
+		credentialsRequestUpdatesMetric,
+	)
+}
+
+// reportStatusMetrics reports the status conditions and the zones of the given ExternalDNS instance.
+func reportStatusMetrics(externalDNS *operatorv1beta1.ExternalDNS) {
+	for _, cond := range externalDNS.Status.Conditions {
+		for _, status := range []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown} {
+			value := 0.0
+			if cond.Status == status {
+				value = 1
+			}
+			conditionMetric.WithLabelValues(externalDNS.Name, cond.Type, string(status)).Set(value)
+		}
+	}
+	zonesMetric.WithLabelValues(externalDNS.Name).Set(float64(len(externalDNS.Spec.Zones)))
+}
+
+// reportDeploymentMetrics reports the number of containers of the deployment of the given ExternalDNS instance.
+func reportDeploymentMetrics(externalDNS *operatorv1beta1.ExternalDNS, deployment *appsv1.Deployment) {
+	containers := 0
+	if deployment != nil {
+		containers = len(deployment.Spec.Template.Spec.Containers)
+	}
+	containersMetric.WithLabelValues(externalDNS.Name).Set(float64(containers))
+}
+
+// reportRolloutMetrics counts the rollouts of the deployment of the given ExternalDNS instance
+// caused by the change of the hashes of the credentials secret, the trusted CA configmaps and the proxy settings.
+func reportRolloutMetrics(externalDNS *operatorv1beta1.ExternalDNS, current, applied *appsv1.Deployment) {
+	if current == nil || applied == nil {
+		return
+	}
+	currentAnnotations, appliedAnnotations := current.Spec.Template.Annotations, applied.Spec.Template.Annotations
+	if currentAnnotations[credentialsAnnotation] != appliedAnnotations[credentialsAnnotation] {
+		deploymentRolloutsMetric.WithLabelValues(externalDNS.Name, rolloutReasonCredentials).Inc()
+	}
+	if currentAnnotations[trustedCAAnnotation] != appliedAnnotations[trustedCAAnnotation] {
+		deploymentRolloutsMetric.WithLabelValues(externalDNS.Name, rolloutReasonTrustedCA).Inc()
+	}
+	if currentAnnotations[providerTrustedCAAnnotation] != appliedAnnotations[providerTrustedCAAnnotation] {
+		deploymentRolloutsMetric.WithLabelValues(externalDNS.Name, rolloutReasonProviderTrustedCA).Inc()
+	}
+	if currentAnnotations[proxyAnnotation] != appliedAnnotations[proxyAnnotation] {
+		deploymentRolloutsMetric.WithLabelValues(externalDNS.Name, rolloutReasonProxy).Inc()
+	}
+}
+
+// reportCredentialsSecretMetrics reports the last time the given credentials secret was changed by the operator:
+// the time of its fields applied by the operator, its creation time if unknown.
+func reportCredentialsSecretMetrics(externalDNS *operatorv1beta1.ExternalDNS, secret *corev1.Secret) {
+	rotation := secret.CreationTimestamp
+	for _, entry := range secret.ManagedFields {
+		if entry.Manager == ctrlutils.FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply && entry.Time != nil {
+			rotation = *entry.Time
+		}
+	}
+	if rotation.IsZero() {
+		return
+	}
+	credentialsSecretRotationMetric.WithLabelValues(externalDNS.Name).Set(float64(rotation.Unix()))
+}
+
+// deleteMetrics removes the series of the given ExternalDNS instance.
+func deleteMetrics(name string) {
+	labels := prometheus.Labels{"name": name}
+	conditionMetric.DeletePartialMatch(labels)
+	zonesMetric.DeletePartialMatch(labels)
+	containersMetric.DeletePartialMatch(labels)
+	credentialsSecretRotationMetric.DeletePartialMatch(labels)
+	deploymentRolloutsMetric.DeletePartialMatch(labels)
+}
+
+// deleteCredentialsRequestMetrics deletes the metrics of the given credentials request.
+func deleteCredentialsRequestMetrics(name string) {
+	credentialsRequestUpdatesMetric.DeleteLabelValues(name)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	ctrlutils "github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestReportStatusMetrics(t *testing.T) {
+	extDNS := &operatorv1beta1.ExternalDNS{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-status-metrics"},
+		Spec: operatorv1beta1.ExternalDNSSpec{
+			Zones: []string{test.PublicZone, test.PrivateZone},
+		},
+		Status: operatorv1beta1.ExternalDNSStatus{
+			Conditions: []metav1.Condition{
+				{Type: ExternalDNSDeploymentAvailableConditionType, Status: metav1.ConditionFalse},
+				{Type: ExternalDNSConflictConditionType, Status: metav1.ConditionUnknown},
+			},
+		},
+	}
+	defer deleteMetrics(extDNS.Name)
+
+	reportStatusMetrics(extDNS)
+
+	expected := map[[2]string]float64{
+		{ExternalDNSDeploymentAvailableConditionType, "True"}:    0,
+		{ExternalDNSDeploymentAvailableConditionType, "False"}:   1,
+		{ExternalDNSDeploymentAvailableConditionType, "Unknown"}: 0,
+		{ExternalDNSConflictConditionType, "True"}:               0,
+		{ExternalDNSConflictConditionType, "False"}:              0,
+		{ExternalDNSConflictConditionType, "Unknown"}:            1,
+	}
+	for labels, value := range expected {
+		if got := metricValue(t, conditionMetric.WithLabelValues(extDNS.Name, labels[0], labels[1])); got != value {
+			t.Errorf("expected condition %s with status %s to be reported as %v, got %v", labels[0], labels[1], value, got)
+		}
+	}
+	if got := metricValue(t, zonesMetric.WithLabelValues(extDNS.Name)); got != 2 {
+		t.Errorf("expected 2 zones, got %v", got)
+	}
+
+	deleteMetrics(extDNS.Name)
+	if conditionMetric.DeleteLabelValues(extDNS.Name, ExternalDNSConflictConditionType, "Unknown") {
+		t.Errorf("expected the condition series to be deleted")
+	}
+}
+
+func TestReportRolloutMetrics(t *testing.T) {
+	extDNS := &operatorv1beta1.ExternalDNS{ObjectMeta: metav1.ObjectMeta{Name: "test-rollout-metrics"}}
+	defer deleteMetrics(extDNS.Name)
+
+	deploymentWithHashes := func(secretHash, caHash string) *appsv1.Deployment {
+		depl := &appsv1.Deployment{}
+		depl.Spec.Template.Annotations = map[string]string{credentialsAnnotation: secretHash}
+		if caHash != "" {
+			depl.Spec.Template.Annotations[trustedCAAnnotation] = caHash
+		}
+		return depl
+	}
+
+	reportRolloutMetrics(extDNS, nil, deploymentWithHashes("a", ""))
+	reportRolloutMetrics(extDNS, deploymentWithHashes("a", ""), deploymentWithHashes("a", ""))
+	reportRolloutMetrics(extDNS, deploymentWithHashes("a", ""), deploymentWithHashes("b", ""))
+	reportRolloutMetrics(extDNS, deploymentWithHashes("b", ""), deploymentWithHashes("c", "x"))
+
+	if got := metricValue(t, deploymentRolloutsMetric.WithLabelValues(extDNS.Name, rolloutReasonCredentials)); got != 2 {
+		t.Errorf("expected 2 rollouts caused by the credentials, got %v", got)
+	}
+	if got := metricValue(t, deploymentRolloutsMetric.WithLabelValues(extDNS.Name, rolloutReasonTrustedCA)); got != 1 {
+		t.Errorf("expected 1 rollout caused by the trusted CA, got %v", got)
+	}
+}
+
+func TestReportCredentialsSecretMetrics(t *testing.T) {
+	created := metav1.NewTime(time.Unix(1000, 0))
+	applied := metav1.NewTime(time.Unix(2000, 0))
+
+	testCases := []struct {
+		name          string
+		secret        *corev1.Secret
+		expectedValue float64
+	}{
+		{
+			name:          "Not applied",
+			secret:        &corev1.Secret{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created}},
+			expectedValue: 1000,
+		},
+		{
+			name: "Applied by the operator",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: created,
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{Manager: "other", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: time.Unix(3000, 0)}},
+					{Manager: ctrlutils.FieldManager, Operation: metav1.ManagedFieldsOperationApply, Time: &applied},
+				},
+			}},
+			expectedValue: 2000,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extDNS := &operatorv1beta1.ExternalDNS{ObjectMeta: metav1.ObjectMeta{Name: "test-secret-metrics"}}
+			defer deleteMetrics(extDNS.Name)
+
+			reportCredentialsSecretMetrics(extDNS, tc.secret)
+			if got := metricValue(t, credentialsSecretRotationMetric.WithLabelValues(extDNS.Name)); got != tc.expectedValue {
+				t.Errorf("expected the last rotation timestamp %v, got %v", tc.expectedValue, got)
+			}
+		})
+	}
+}
+
+// metricValue returns the value of the given gauge or counter.
+func metricValue(t *testing.T, m prometheus.Metric) float64 {
+	t.Helper()
+	out := &dto.Metric{}
+	if err := m.Write(out); err != nil {
+		t.Fatalf("failed to read the metric: %v", err)
+	}
+	if out.Gauge != nil {
+		return out.Gauge.GetValue()
+	}
+	return out.Counter.GetValue()
+}
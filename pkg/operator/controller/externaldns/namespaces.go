@@ -0,0 +1,99 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+)
+
+// namespaceScoped returns true if the given ExternalDNS publishes the source resources
+// of the selected namespaces only.
+func namespaceScoped(externalDNS *operatorv1beta1.ExternalDNS) bool {
+	return len(externalDNS.Spec.Source.Namespaces) > 0 || externalDNS.Spec.Source.NamespaceSelector != nil
+}
+
+// currentSourceNamespaces returns the sorted namespaces from which the given ExternalDNS publishes the source resources:
+// the listed namespaces merged with the ones matching the namespace selector.
+// The namespaces which don't exist or are being deleted are left out.
+// Returns nil if the source resources are published from all the namespaces.
+func (r *reconciler) currentSourceNamespaces(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS) ([]string, error) {
+	if !namespaceScoped(externalDNS) {
+		return nil, nil
+	}
+
+	selector := labels.Nothing()
+	if externalDNS.Spec.Source.NamespaceSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(externalDNS.Spec.Source.NamespaceSelector); err != nil {
+			return nil, fmt.Errorf("failed to parse the namespace selector: %w", err)
+		}
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.client.List(ctx, namespaceList); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	listed := sets.New(externalDNS.Spec.Source.Namespaces...)
+	selected := sets.New[string]()
+	for _, ns := range namespaceList.Items {
+		if !ns.DeletionTimestamp.IsZero() {
+			// the role binding cannot be created in the terminating namespace
+			continue
+		}
+		if listed.Has(ns.Name) || selector.Matches(labels.Set(ns.Labels)) {
+			selected.Insert(ns.Name)
+		}
+	}
+	return sets.List(selected), nil
+}
+
+// computeSourceNamespacesCondition returns the condition which reports
+// the namespaces from which the source resources are published.
+func computeSourceNamespacesCondition(namespaces []string) metav1.Condition {
+	switch {
+	case namespaces == nil:
+		return metav1.Condition{
+			Type:    ExternalDNSSourceNamespacesSelectedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "AllNamespaces",
+			Message: "The source resources are published from all the namespaces",
+		}
+	case len(namespaces) == 0:
+		return metav1.Condition{
+			Type:    ExternalDNSSourceNamespacesSelectedConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoNamespacesSelected",
+			Message: "No existing namespace is listed or matches the namespace selector, the deployment is scaled down",
+		}
+	}
+	return metav1.Condition{
+		Type:    ExternalDNSSourceNamespacesSelectedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "NamespacesSelected",
+		Message: fmt.Sprintf("The source resources are published from the namespaces: %s", strings.Join(namespaces, ", ")),
+	}
+}
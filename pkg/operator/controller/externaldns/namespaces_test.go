@@ -0,0 +1,113 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestCurrentSourceNamespaces(t *testing.T) {
+	namespaces := []runtime.Object{
+		testNamespace("tenant-a", map[string]string{"tenant": "a"}),
+		testNamespace("tenant-b", map[string]string{"tenant": "b"}),
+		testNamespace("tenant-c", map[string]string{"tenant": "c"}),
+		testNamespace("other", nil),
+	}
+	terminating := testNamespace("tenant-d", map[string]string{"tenant": "d"})
+	terminating.DeletionTimestamp = ptr.To(metav1.Now())
+	terminating.Finalizers = []string{"kubernetes"}
+	namespaces = append(namespaces, terminating)
+
+	testCases := []struct {
+		name       string
+		namespaces []string
+		selector   *metav1.LabelSelector
+		expected   []string
+	}{
+		{
+			name: "All namespaces",
+		},
+		{
+			name:       "Listed namespaces",
+			namespaces: []string{"tenant-b", "tenant-a"},
+			expected:   []string{"tenant-a", "tenant-b"},
+		},
+		{
+			name: "Selected namespaces",
+			selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tenant", Operator: metav1.LabelSelectorOpIn, Values: []string{"b", "c", "d"}},
+				},
+			},
+			expected: []string{"tenant-b", "tenant-c"},
+		},
+		{
+			name:       "Listed and selected namespaces",
+			namespaces: []string{"other", "tenant-a"},
+			selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}},
+			expected:   []string{"other", "tenant-a"},
+		},
+		{
+			name:       "Missing and terminating namespaces",
+			namespaces: []string{"missing", "tenant-d"},
+			expected:   []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(namespaces...).Build()
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				log:    zap.New(zap.UseDevMode(true)),
+			}
+			extDNS := testAWSExternalDNS(operatorv1beta1.SourceTypeService)
+			extDNS.Spec.Source.Namespaces = tc.namespaces
+			extDNS.Spec.Source.NamespaceSelector = tc.selector
+
+			got, err := r.currentSourceNamespaces(context.TODO(), extDNS)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected source namespaces (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func testNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
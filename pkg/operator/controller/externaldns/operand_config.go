@@ -0,0 +1,122 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+)
+
+const (
+	defaultLogLevel = "debug"
+	defaultPolicy   = "sync"
+)
+
+var (
+	// logLevelArgs maps the log levels to the values of the ExternalDNS log level flag
+	logLevelArgs = map[operatorv1beta1.ExternalDNSLogLevel]string{
+		operatorv1beta1.ExternalDNSLogLevelDebug:   "debug",
+		operatorv1beta1.ExternalDNSLogLevelInfo:    "info",
+		operatorv1beta1.ExternalDNSLogLevelWarning: "warning",
+		operatorv1beta1.ExternalDNSLogLevelError:   "error",
+	}
+
+	// policyArgs maps the policies to the values of the ExternalDNS policy flag
+	policyArgs = map[operatorv1beta1.ExternalDNSPolicy]string{
+		operatorv1beta1.ExternalDNSPolicySync:       "sync",
+		operatorv1beta1.ExternalDNSPolicyUpsertOnly: "upsert-only",
+		operatorv1beta1.ExternalDNSPolicyCreateOnly: "create-only",
+	}
+)
+
+// currentExternalDNSConfig returns the spec of the ExternalDNSConfig singleton,
+// an empty spec if it doesn't exist.
+func (r *reconciler) currentExternalDNSConfig(ctx context.Context) (*operatorv1beta1.ExternalDNSConfigSpec, error) {
+	config := &operatorv1beta1.ExternalDNSConfig{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: operatorv1beta1.ExternalDNSConfigName}, config); err != nil {
+		if errors.IsNotFound(err) {
+			return &operatorv1beta1.ExternalDNSConfigSpec{}, nil
+		}
+		return nil, err
+	}
+	return &config.Spec, nil
+}
+
+// effectiveOperandConfig returns the operand settings of the given ExternalDNS instance:
+// the fields set in the instance override the defaults from the ExternalDNSConfig.
+func effectiveOperandConfig(defaults *operatorv1beta1.ExternalDNSConfigSpec, externalDNS *operatorv1beta1.ExternalDNS) operatorv1beta1.ExternalDNSOperandConfig {
+	effective := operatorv1beta1.ExternalDNSOperandConfig{}
+	if defaults != nil {
+		effective = *defaults.ExternalDNSOperandConfig.DeepCopy()
+	}
+	overrides := externalDNS.Spec.ExternalDNSOperandConfig.DeepCopy()
+	if overrides.Image != "" {
+		effective.Image = overrides.Image
+	}
+	if overrides.Resources != nil {
+		effective.Resources = overrides.Resources
+	}
+	if overrides.NodePlacement != nil {
+		effective.NodePlacement = overrides.NodePlacement
+	}
+	if overrides.LogLevel != "" {
+		effective.LogLevel = overrides.LogLevel
+	}
+	if overrides.Policy != "" {
+		effective.Policy = overrides.Policy
+	}
+	if overrides.Registry != nil {
+		effective.Registry = overrides.Registry
+	}
+	if overrides.Proxy != nil {
+		effective.Proxy = overrides.Proxy
+	}
+	return effective
+}
+
+// logLevelArg returns the value of the log level flag of ExternalDNS.
+// The dry run needs at least the info level to log the planned changes.
+func logLevelArg(config operatorv1beta1.ExternalDNSOperandConfig, dryRun bool) string {
+	level, ok := logLevelArgs[config.LogLevel]
+	if !ok {
+		return defaultLogLevel
+	}
+	if dryRun && (config.LogLevel == operatorv1beta1.ExternalDNSLogLevelWarning || config.LogLevel == operatorv1beta1.ExternalDNSLogLevelError) {
+		return logLevelArgs[operatorv1beta1.ExternalDNSLogLevelInfo]
+	}
+	return level
+}
+
+// policyArg returns the value of the policy flag of ExternalDNS.
+func policyArg(config operatorv1beta1.ExternalDNSOperandConfig) string {
+	if policy, ok := policyArgs[config.Policy]; ok {
+		return policy
+	}
+	return defaultPolicy
+}
+
+// txtPrefix returns the prefix of the TXT records of the registry.
+func txtPrefix(config operatorv1beta1.ExternalDNSOperandConfig) string {
+	if config.Registry != nil && config.Registry.TXTPrefix != "" {
+		return config.Registry.TXTPrefix
+	}
+	return defaultTXTRecordPrefix
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestEffectiveOperandConfig(t *testing.T) {
+	infraPlacement := &operatorv1beta1.ExternalDNSNodePlacement{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+	}
+	workerPlacement := &operatorv1beta1.ExternalDNSNodePlacement{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+		Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+	}
+
+	testCases := []struct {
+		name      string
+		defaults  *operatorv1beta1.ExternalDNSConfigSpec
+		overrides operatorv1beta1.ExternalDNSOperandConfig
+		expected  operatorv1beta1.ExternalDNSOperandConfig
+	}{
+		{
+			name: "No config",
+		},
+		{
+			name: "No config, instance settings",
+			overrides: operatorv1beta1.ExternalDNSOperandConfig{
+				LogLevel: operatorv1beta1.ExternalDNSLogLevelInfo,
+			},
+			expected: operatorv1beta1.ExternalDNSOperandConfig{
+				LogLevel: operatorv1beta1.ExternalDNSLogLevelInfo,
+			},
+		},
+		{
+			name: "Config defaults",
+			defaults: &operatorv1beta1.ExternalDNSConfigSpec{
+				ExternalDNSOperandConfig: operatorv1beta1.ExternalDNSOperandConfig{
+					Image:         "quay.io/test/external-dns:defaults",
+					NodePlacement: infraPlacement,
+					Policy:        operatorv1beta1.ExternalDNSPolicyCreateOnly,
+				},
+			},
+			expected: operatorv1beta1.ExternalDNSOperandConfig{
+				Image:         "quay.io/test/external-dns:defaults",
+				NodePlacement: infraPlacement,
+				Policy:        operatorv1beta1.ExternalDNSPolicyCreateOnly,
+			},
+		},
+		{
+			name: "Instance overrides config defaults",
+			defaults: &operatorv1beta1.ExternalDNSConfigSpec{
+				ExternalDNSOperandConfig: operatorv1beta1.ExternalDNSOperandConfig{
+					Image:         "quay.io/test/external-dns:defaults",
+					NodePlacement: infraPlacement,
+					LogLevel:      operatorv1beta1.ExternalDNSLogLevelError,
+					Registry:      &operatorv1beta1.ExternalDNSRegistry{TXTPrefix: "defaults-"},
+					Proxy:         &operatorv1beta1.ExternalDNSProxy{HTTPSProxy: "https://proxy.test:3128"},
+				},
+			},
+			overrides: operatorv1beta1.ExternalDNSOperandConfig{
+				NodePlacement: workerPlacement,
+				Policy:        operatorv1beta1.ExternalDNSPolicyUpsertOnly,
+				Registry:      &operatorv1beta1.ExternalDNSRegistry{TXTPrefix: "instance-"},
+				Proxy:         &operatorv1beta1.ExternalDNSProxy{},
+			},
+			expected: operatorv1beta1.ExternalDNSOperandConfig{
+				Image:         "quay.io/test/external-dns:defaults",
+				NodePlacement: workerPlacement,
+				LogLevel:      operatorv1beta1.ExternalDNSLogLevelError,
+				Policy:        operatorv1beta1.ExternalDNSPolicyUpsertOnly,
+				Registry:      &operatorv1beta1.ExternalDNSRegistry{TXTPrefix: "instance-"},
+				Proxy:         &operatorv1beta1.ExternalDNSProxy{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extDNS := &operatorv1beta1.ExternalDNS{
+				ObjectMeta: metav1.ObjectMeta{Name: test.Name},
+				Spec:       operatorv1beta1.ExternalDNSSpec{ExternalDNSOperandConfig: tc.overrides},
+			}
+			if diff := cmp.Diff(tc.expected, effectiveOperandConfig(tc.defaults, extDNS)); diff != "" {
+				t.Errorf("unexpected operand config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLogLevelArg(t *testing.T) {
+	testCases := []struct {
+		level    operatorv1beta1.ExternalDNSLogLevel
+		dryRun   bool
+		expected string
+	}{
+		{level: "", expected: "debug"},
+		{level: operatorv1beta1.ExternalDNSLogLevelInfo, expected: "info"},
+		{level: operatorv1beta1.ExternalDNSLogLevelWarning, expected: "warning"},
+		{level: operatorv1beta1.ExternalDNSLogLevelError, dryRun: true, expected: "info"},
+		{level: operatorv1beta1.ExternalDNSLogLevelDebug, dryRun: true, expected: "debug"},
+	}
+
+	for _, tc := range testCases {
+		config := operatorv1beta1.ExternalDNSOperandConfig{LogLevel: tc.level}
+		if got := logLevelArg(config, tc.dryRun); got != tc.expected {
+			t.Errorf("expected log level %q for %q with dry run %t, got %q", tc.expected, tc.level, tc.dryRun, got)
+		}
+	}
+}
+
+func TestCurrentExternalDNSConfig(t *testing.T) {
+	config := &operatorv1beta1.ExternalDNSConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorv1beta1.ExternalDNSConfigName},
+		Spec: operatorv1beta1.ExternalDNSConfigSpec{
+			ExternalDNSOperandConfig: operatorv1beta1.ExternalDNSOperandConfig{
+				LogLevel: operatorv1beta1.ExternalDNSLogLevelInfo,
+			},
+		},
+	}
+	otherConfig := &operatorv1beta1.ExternalDNSConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec: operatorv1beta1.ExternalDNSConfigSpec{
+			ExternalDNSOperandConfig: operatorv1beta1.ExternalDNSOperandConfig{
+				LogLevel: operatorv1beta1.ExternalDNSLogLevelError,
+			},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		existingObjects []runtime.Object
+		expected        *operatorv1beta1.ExternalDNSConfigSpec
+	}{
+		{
+			name:     "Does not exist",
+			expected: &operatorv1beta1.ExternalDNSConfigSpec{},
+		},
+		{
+			name:            "Only the cluster config is used",
+			existingObjects: []runtime.Object{otherConfig},
+			expected:        &operatorv1beta1.ExternalDNSConfigSpec{},
+		},
+		{
+			name:            "Exists",
+			existingObjects: []runtime.Object{config, otherConfig},
+			expected:        &config.Spec,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &reconciler{
+				client: fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build(),
+			}
+			got, err := r.currentExternalDNSConfig(context.TODO())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,7 +18,6 @@ package externaldnscontroller
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -42,6 +41,8 @@ const (
 	defaultTXTRecordPrefix        = "external-dns-"
 	defaultTXTWildcardReplacement = "any"
 	providerArg                   = "--provider="
+	txtOwnerIDArg                 = "--txt-owner-id="
+	dryRunArg                     = "--dry-run"
 	httpProxyEnvVar               = "HTTP_PROXY"
 	httpsProxyEnvVar              = "HTTPS_PROXY"
 	noProxyEnvVar                 = "NO_PROXY"
@@ -71,6 +72,7 @@ const (
 	boundSATokenExpirationSeconds = 3600
 	boundSATokenPath              = "token"
 	boundSATokenMountPath         = "/var/run/secrets/openshift/serviceaccount"
+	awsAnnotationPrefix           = "external-dns.alpha.kubernetes.io/"
 	//
 	// Azure
 	//
@@ -112,25 +114,34 @@ type externalDNSContainerBuilder struct {
 	externalDNS    *operatorv1beta1.ExternalDNS
 	isOpenShift    bool
 	platformStatus *configv1.PlatformStatus
-	counter        int
+	operandConfig  operatorv1beta1.ExternalDNSOperandConfig
+	// proxy is the proxy to reach the DNS provider, nil if the provider is reached directly
+	proxy *operatorv1beta1.ExternalDNSProxy
+	// namespace is the namespace of the source resources of the next containers,
+	// empty for all the namespaces
+	namespace string
+	counter   int
 }
 
-// build returns the definition of a single container for the given DNS zone with unique metrics port
-func (b *externalDNSContainerBuilder) build(zone string) (*corev1.Container, error) {
+// build returns the definition of a single container for the given DNS zones with unique metrics port,
+// no zones means all the zones
+func (b *externalDNSContainerBuilder) build(zones ...string) (*corev1.Container, error) {
 	seq := b.counter
 	b.counter++
-	return b.buildSeq(seq, zone)
+	return b.buildSeq(seq, zones)
 }
 
-// buildSeq returns the definition of a single container for the given DNS zone
+// buildSeq returns the definition of a single container for the given DNS zones
 // sequence param is used to create the unique metrics port
-func (b *externalDNSContainerBuilder) buildSeq(seq int, zone string) (*corev1.Container, error) {
-	container := b.defaultContainer(controller.ExternalDNSContainerName(zone))
-	err := b.fillProviderAgnosticFields(seq, zone, container)
+func (b *externalDNSContainerBuilder) buildSeq(seq int, zones []string) (*corev1.Container, error) {
+	// the name of the container with a single zone
+	// doesn't depend on the zones mode
+	container := b.defaultContainer(controller.ExternalDNSNamespacedContainerName(strings.Join(zones, ","), b.namespace))
+	err := b.fillProviderAgnosticFields(seq, zones, container)
 	if err != nil {
 		return nil, err
 	}
-	b.fillProviderSpecificFields(zone, container)
+	b.fillProviderSpecificFields(zones, container)
 	return container, nil
 }
 
@@ -157,24 +168,35 @@ func (b *externalDNSContainerBuilder) defaultContainer(name string) *corev1.Cont
 }
 
 // fillProviderAgnosticFields fills the given container with the data agnostic to any provider
-func (b *externalDNSContainerBuilder) fillProviderAgnosticFields(seq int, zone string, container *corev1.Container) error {
+func (b *externalDNSContainerBuilder) fillProviderAgnosticFields(seq int, zones []string, container *corev1.Container) error {
 	//
 	// ARGS
 	//
+	ownerID := fmt.Sprintf("%s-%s", defaultOwnerPrefix, b.externalDNS.Name)
+	if b.namespace != "" {
+		// the containers of the other namespaces would remove the records
+		// of the source resources they don't see if they shared the owner
+		ownerID += "-" + b.namespace
+	}
+
 	args := []string{
 		fmt.Sprintf("--metrics-address=%s:%d", defaultMetricsAddress, defaultMetricsStartPort+seq),
-		fmt.Sprintf("--txt-owner-id=%s-%s", defaultOwnerPrefix, b.externalDNS.Name),
+		txtOwnerIDArg + ownerID,
 		fmt.Sprintf("--provider=%s", b.provider),
 		fmt.Sprintf("--source=%s", b.source),
-		"--policy=sync",
+		fmt.Sprintf("--policy=%s", policyArg(b.operandConfig)),
 		"--registry=txt",
-		"--log-level=debug",
+		fmt.Sprintf("--log-level=%s", logLevelArg(b.operandConfig, b.externalDNS.Spec.DryRun)),
 	}
 
-	if zone != "" {
+	for _, zone := range zones {
 		args = append(args, fmt.Sprintf("--zone-id-filter=%s", zone))
 	}
 
+	if b.namespace != "" {
+		args = append(args, fmt.Sprintf("--namespace=%s", b.namespace))
+	}
+
 	if b.externalDNS.Spec.Source.LabelFilter != nil {
 		args = append(args, fmt.Sprintf("--label-filter=%s", metav1.FormatLabelSelector(b.externalDNS.Spec.Source.LabelFilter)))
 	}
@@ -205,6 +227,7 @@ func (b *externalDNSContainerBuilder) fillProviderAgnosticFields(seq int, zone s
 		// ExternalDNS needs FQDNTemplate if the hostname annotation is ignored even for Route source.
 		// However it doesn't make much sense as the hostname is retrieved from the route's spec.
 		// Feeding ExternalDNS with some dummy template just to pass the validation.
+		// The defaulting webhook sets the same template, this covers the operator running without the webhook.
 		if b.externalDNS.Spec.Source.HostnameAnnotationPolicy == operatorv1beta1.HostnameAnnotationPolicyIgnore &&
 			b.externalDNS.Spec.Source.Type == operatorv1beta1.SourceTypeRoute {
 			args = append(args, "--fqdn-template={{\"\"}}")
@@ -215,6 +238,10 @@ func (b *externalDNSContainerBuilder) fillProviderAgnosticFields(seq int, zone s
 		args = append(args, fmt.Sprintf("--openshift-router-name=%s", b.externalDNS.Spec.Source.OpenShiftRoute.RouterName))
 	}
 
+	if b.externalDNS.Spec.DryRun {
+		args = append(args, dryRunArg)
+	}
+
 	filterArgs, err := b.domainFilters()
 	if err != nil {
 		return err
@@ -226,18 +253,25 @@ func (b *externalDNSContainerBuilder) fillProviderAgnosticFields(seq int, zone s
 	//
 	// ENV
 	//
-	if utils.EnvProxySupportedProvider(b.externalDNS) {
-		if val := os.Getenv(httpProxyEnvVar); val != "" {
-			container.Env = append(container.Env, corev1.EnvVar{Name: httpProxyEnvVar, Value: val})
+	if b.proxy != nil {
+		if b.proxy.HTTPProxy != "" {
+			container.Env = append(container.Env, corev1.EnvVar{Name: httpProxyEnvVar, Value: b.proxy.HTTPProxy})
 		}
-		if val := os.Getenv(httpsProxyEnvVar); val != "" {
-			container.Env = append(container.Env, corev1.EnvVar{Name: httpsProxyEnvVar, Value: val})
+		if b.proxy.HTTPSProxy != "" {
+			container.Env = append(container.Env, corev1.EnvVar{Name: httpsProxyEnvVar, Value: b.proxy.HTTPSProxy})
 		}
-		if val := os.Getenv(noProxyEnvVar); val != "" {
-			container.Env = append(container.Env, corev1.EnvVar{Name: noProxyEnvVar, Value: val})
+		if b.proxy.NoProxy != "" {
+			container.Env = append(container.Env, corev1.EnvVar{Name: noProxyEnvVar, Value: b.proxy.NoProxy})
 		}
 	}
 
+	//
+	// RESOURCES
+	//
+	if b.operandConfig.Resources != nil {
+		container.Resources = *b.operandConfig.Resources.DeepCopy()
+	}
+
 	//
 	// VOLUME MOUNTS
 	//
@@ -321,10 +355,15 @@ func combineRegexps(patterns []string) string {
 }
 
 // fillProviderSpecificFields fills the fields specific to the provider of given ExternalDNS
-func (b *externalDNSContainerBuilder) fillProviderSpecificFields(zone string, container *corev1.Container) {
+func (b *externalDNSContainerBuilder) fillProviderSpecificFields(zones []string, container *corev1.Container) {
+	// the zones of the same container share the provider configuration
+	zone := ""
+	if len(zones) > 0 {
+		zone = zones[0]
+	}
 	switch b.provider {
 	case externalDNSProviderTypeAWS:
-		b.fillAWSFields(container)
+		b.fillAWSFields(zone, container)
 	case externalDNSProviderTypeAzure, externalDNSProviderTypeAzurePrivate:
 		b.fillAzureFields(zone, container)
 	case externalDNSProviderTypeGCP:
@@ -337,17 +376,63 @@ func (b *externalDNSContainerBuilder) fillProviderSpecificFields(zone string, co
 }
 
 // fillAWSFields fills the given container with the data specific to AWS provider
-func (b *externalDNSContainerBuilder) fillAWSFields(container *corev1.Container) {
-	container.Args = addTXTPrefixFlag(container.Args)
+func (b *externalDNSContainerBuilder) fillAWSFields(zone string, container *corev1.Container) {
+	container.Args = b.addTXTPrefixFlag(container.Args)
+
+	region, explicitRegion := "", false
+	if b.platformStatus != nil && b.platformStatus.AWS != nil {
+		region = b.platformStatus.AWS.Region
+	}
+	if b.externalDNS.Spec.Provider.AWS != nil && len(b.externalDNS.Spec.Provider.AWS.Region) > 0 {
+		region, explicitRegion = b.externalDNS.Spec.Provider.AWS.Region, true
+	}
 
-	if b.platformStatus != nil && b.platformStatus.AWS != nil && utils.IsUSGovAWSRegion(b.platformStatus.AWS.Region) {
+	preferCNAME := false
+	if explicitRegion || utils.IsUSGovAWSRegion(region) {
+		container.Env = append(container.Env, corev1.EnvVar{Name: awsRegionEnvVarName, Value: region})
+	}
+	if utils.IsUSGovAWSRegion(region) {
 		// See https://github.com/kubernetes-sigs/external-dns/blob/master/docs/tutorials/aws.md#govcloud-caveats
-		container.Env = append(container.Env, corev1.EnvVar{Name: awsRegionEnvVarName, Value: b.platformStatus.AWS.Region})
+		preferCNAME = true
+	}
+	if b.externalDNS.Spec.Provider.AWS != nil && b.externalDNS.Spec.Provider.AWS.PreferCNAME != nil {
+		preferCNAME = *b.externalDNS.Spec.Provider.AWS.PreferCNAME
+	}
+	if preferCNAME {
 		container.Args = append(container.Args, "--aws-prefer-cname")
 	}
 
-	if b.externalDNS.Spec.Provider.AWS != nil && b.externalDNS.Spec.Provider.AWS.AssumeRole != nil {
-		container.Args = append(container.Args, fmt.Sprintf("--aws-assume-role=%s", b.externalDNS.Spec.Provider.AWS.AssumeRole.ARN))
+	if assumeRole := awsAssumeRoleForZone(b.externalDNS.Spec.Provider.AWS, zone); assumeRole != nil {
+		container.Args = append(container.Args, fmt.Sprintf("--aws-assume-role=%s", assumeRole.ARN))
+		if len(assumeRole.ExternalID) > 0 {
+			container.Args = append(container.Args, fmt.Sprintf("--aws-assume-role-external-id=%s", assumeRole.ExternalID))
+		}
+	}
+
+	if b.externalDNS.Spec.Provider.AWS != nil {
+		awsOptions := b.externalDNS.Spec.Provider.AWS
+		if len(awsOptions.ZoneType) > 0 {
+			// ExternalDNS expects the zone type in lower case
+			container.Args = append(container.Args, fmt.Sprintf("--aws-zone-type=%s", strings.ToLower(string(awsOptions.ZoneType))))
+		}
+		for _, tag := range awsOptions.ZoneTags {
+			container.Args = append(container.Args, fmt.Sprintf("--aws-zone-tags=%s", tag))
+		}
+		if awsOptions.ZonesCacheDuration != nil {
+			container.Args = append(container.Args, fmt.Sprintf("--aws-zones-cache-duration=%s", awsOptions.ZonesCacheDuration.Duration))
+		}
+		if awsOptions.EvaluateTargetHealth != nil {
+			if *awsOptions.EvaluateTargetHealth {
+				container.Args = append(container.Args, "--aws-evaluate-target-health")
+			} else {
+				container.Args = append(container.Args, "--no-aws-evaluate-target-health")
+			}
+		}
+		if awsOptions.RoutingPolicies != nil {
+			if filter := awsRoutingPolicyAnnotationFilter(awsOptions.RoutingPolicies.Allowed); len(filter) > 0 {
+				container.Args = appendAnnotationFilter(container.Args, filter)
+			}
+		}
 	}
 
 	// don't add empty credentials environment variables if no secret was given
@@ -374,10 +459,96 @@ func (b *externalDNSContainerBuilder) fillAWSFields(container *corev1.Container)
 	}
 }
 
+// awsAssumeRoleForZone returns the IAM role to assume for the given zone.
+// The zone specific role takes precedence over the one set for all the zones.
+func awsAssumeRoleForZone(awsOptions *operatorv1beta1.ExternalDNSAWSProviderOptions, zone string) *operatorv1beta1.ExternalDNSAWSAssumeRoleOptions {
+	if awsOptions == nil {
+		return nil
+	}
+	for i := range awsOptions.ZoneAssumeRoles {
+		if len(zone) > 0 && awsOptions.ZoneAssumeRoles[i].ZoneID == zone {
+			return &awsOptions.ZoneAssumeRoles[i].ExternalDNSAWSAssumeRoleOptions
+		}
+	}
+	return awsOptions.AssumeRole
+}
+
+// awsRoutingPolicyAnnotations maps the Route 53 routing policies
+// to the source annotations which ExternalDNS uses to request them.
+// The slice is used instead of a map to keep the order of the annotations stable.
+var awsRoutingPolicyAnnotations = []struct {
+	policy      operatorv1beta1.ExternalDNSAWSRoutingPolicy
+	annotations []string
+}{
+	{
+		policy:      operatorv1beta1.AWSRoutingPolicyWeighted,
+		annotations: []string{awsAnnotationPrefix + "aws-weight"},
+	},
+	{
+		policy:      operatorv1beta1.AWSRoutingPolicyLatency,
+		annotations: []string{awsAnnotationPrefix + "aws-region"},
+	},
+	{
+		policy:      operatorv1beta1.AWSRoutingPolicyFailover,
+		annotations: []string{awsAnnotationPrefix + "aws-failover"},
+	},
+	{
+		policy: operatorv1beta1.AWSRoutingPolicyGeolocation,
+		annotations: []string{
+			awsAnnotationPrefix + "aws-geolocation-continent-code",
+			awsAnnotationPrefix + "aws-geolocation-country-code",
+			awsAnnotationPrefix + "aws-geolocation-subdivision-code",
+		},
+	},
+	{
+		policy:      operatorv1beta1.AWSRoutingPolicySetIdentifier,
+		annotations: []string{awsAnnotationPrefix + "set-identifier"},
+	},
+}
+
+// awsNotAllowedRoutingPolicyAnnotations returns the source annotations of the routing policies which are not allowed.
+func awsNotAllowedRoutingPolicyAnnotations(allowed []operatorv1beta1.ExternalDNSAWSRoutingPolicy) []string {
+	allowedSet := map[operatorv1beta1.ExternalDNSAWSRoutingPolicy]bool{}
+	for _, policy := range allowed {
+		allowedSet[policy] = true
+	}
+
+	annotations := []string{}
+	for _, rp := range awsRoutingPolicyAnnotations {
+		if !allowedSet[rp.policy] {
+			annotations = append(annotations, rp.annotations...)
+		}
+	}
+	return annotations
+}
+
+// awsRoutingPolicyAnnotationFilter returns the annotation filter (label selector syntax)
+// which makes ExternalDNS ignore the sources annotated with the routing policies which are not allowed.
+func awsRoutingPolicyAnnotationFilter(allowed []operatorv1beta1.ExternalDNSAWSRoutingPolicy) string {
+	requirements := []string{}
+	for _, annotation := range awsNotAllowedRoutingPolicyAnnotations(allowed) {
+		// "!key" requirement matches the sources which don't have the annotation
+		requirements = append(requirements, "!"+annotation)
+	}
+	return strings.Join(requirements, ",")
+}
+
+// appendAnnotationFilter adds the given requirements (label selector syntax) to the annotation filter of the given args.
+// ExternalDNS accepts a single annotation filter: the requirements are merged into the existing filter if any.
+func appendAnnotationFilter(args []string, requirements string) []string {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, annotationFilterArg) {
+			args[i] = arg + "," + requirements
+			return args
+		}
+	}
+	return append(args, annotationFilterArg+requirements)
+}
+
 // fillAzureFields fills the given container with the data specific to Azure provider
 func (b *externalDNSContainerBuilder) fillAzureFields(zone string, container *corev1.Container) {
 	// https://github.com/kubernetes-sigs/external-dns/issues/2082
-	container.Args = addTXTPrefixFlag(container.Args)
+	container.Args = b.addTXTPrefixFlag(container.Args)
 
 	// https://github.com/kubernetes-sigs/external-dns/issues/2922
 	container.Args = append(container.Args, fmt.Sprintf("--txt-wildcard-replacement=%s", defaultTXTWildcardReplacement))
@@ -410,20 +581,31 @@ func (b *externalDNSContainerBuilder) fillAzureFields(zone string, container *co
 // fillGCPFields fills the given container with the data specific to Google provider
 func (b *externalDNSContainerBuilder) fillGCPFields(container *corev1.Container) {
 	// https://github.com/kubernetes-sigs/external-dns/issues/262
-	container.Args = addTXTPrefixFlag(container.Args)
+	container.Args = b.addTXTPrefixFlag(container.Args)
 
-	if !b.isOpenShift {
-		// don't add empty args if GCP provider is not given
-		if b.externalDNS.Spec.Provider.GCP == nil {
-			return
-		}
+	project := ""
+	if b.isOpenShift && b.platformStatus != nil && b.platformStatus.GCP != nil {
+		project = b.platformStatus.GCP.ProjectID
+	}
+	// explicitly given project takes precedence over the cluster's one:
+	// DNS zones may be in another project (e.g. shared VPC host project)
+	if b.externalDNS.Spec.Provider.GCP != nil && b.externalDNS.Spec.Provider.GCP.Project != nil && len(*b.externalDNS.Spec.Provider.GCP.Project) > 0 {
+		project = *b.externalDNS.Spec.Provider.GCP.Project
+	}
+	if len(project) > 0 {
+		container.Args = append(container.Args, fmt.Sprintf("--google-project=%s", project))
+	}
 
-		if b.externalDNS.Spec.Provider.GCP.Project != nil && len(*b.externalDNS.Spec.Provider.GCP.Project) > 0 {
-			container.Args = append(container.Args, fmt.Sprintf("--google-project=%s", *b.externalDNS.Spec.Provider.GCP.Project))
+	if gcpOptions := b.externalDNS.Spec.Provider.GCP; gcpOptions != nil {
+		if len(gcpOptions.ZoneVisibility) > 0 {
+			// ExternalDNS expects the zone visibility in lower case
+			container.Args = append(container.Args, fmt.Sprintf("--google-zone-visibility=%s", strings.ToLower(string(gcpOptions.ZoneVisibility))))
 		}
-	} else {
-		if b.platformStatus != nil && b.platformStatus.GCP != nil && len(b.platformStatus.GCP.ProjectID) > 0 {
-			container.Args = append(container.Args, fmt.Sprintf("--google-project=%s", b.platformStatus.GCP.ProjectID))
+		if gcpOptions.BatchChangeSize != nil {
+			container.Args = append(container.Args, fmt.Sprintf("--google-batch-change-size=%d", *gcpOptions.BatchChangeSize))
+		}
+		if gcpOptions.BatchChangeInterval != nil {
+			container.Args = append(container.Args, fmt.Sprintf("--google-batch-change-interval=%s", gcpOptions.BatchChangeInterval.Duration))
 		}
 	}
 
@@ -444,7 +626,7 @@ func (b *externalDNSContainerBuilder) fillGCPFields(container *corev1.Container)
 func (b *externalDNSContainerBuilder) fillBlueCatFields(container *corev1.Container) {
 	// only standard CNAME records are supported
 	// https://docs.bluecatnetworks.com/r/Address-Manager-API-Guide/ENUM-number-generic-methods/9.2.0
-	container.Args = addTXTPrefixFlag(container.Args)
+	container.Args = b.addTXTPrefixFlag(container.Args)
 
 	// no volume mounts will be added if there is no config volume added before
 	for _, v := range b.volumes {
@@ -479,7 +661,32 @@ func (b *externalDNSContainerBuilder) fillInfobloxFields(container *corev1.Conta
 		args = append(args, fmt.Sprintf("--infoblox-wapi-version=%s", b.externalDNS.Spec.Provider.Infoblox.WAPIVersion))
 	}
 
-	args = addTXTPrefixFlag(args)
+	infobloxOptions := b.externalDNS.Spec.Provider.Infoblox
+	if len(infobloxOptions.View) > 0 {
+		args = append(args, fmt.Sprintf("--infoblox-view=%s", infobloxOptions.View))
+	}
+	if infobloxOptions.CreatePTR {
+		args = append(args, "--infoblox-create-ptr")
+	}
+	if infobloxOptions.SSLVerify != nil {
+		if *infobloxOptions.SSLVerify {
+			// the trusted CA bundle is used for the verification if it's injected
+			args = append(args, "--infoblox-ssl-verify")
+		} else {
+			args = append(args, "--no-infoblox-ssl-verify")
+		}
+	}
+	if infobloxOptions.MaxResults != nil {
+		args = append(args, fmt.Sprintf("--infoblox-max-results=%d", *infobloxOptions.MaxResults))
+	}
+	if len(infobloxOptions.FQDNRegex) > 0 {
+		args = append(args, fmt.Sprintf("--infoblox-fqdn-regex=%s", infobloxOptions.FQDNRegex))
+	}
+	if len(infobloxOptions.NameRegex) > 0 {
+		args = append(args, fmt.Sprintf("--infoblox-name-regex=%s", infobloxOptions.NameRegex))
+	}
+
+	args = b.addTXTPrefixFlag(args)
 
 	env := []corev1.EnvVar{
 		{
@@ -681,8 +888,8 @@ func (b *externalDNSVolumeBuilder) bluecatVolumes() []corev1.Volume {
 	}
 }
 
-// addTXTPrefixFlag adds the txt prefix flag with default value
+// addTXTPrefixFlag adds the txt prefix flag with the prefix of the registry, the default value if not set
 // needed if CNAME records are used: https://github.com/kubernetes-sigs/external-dns#note
-func addTXTPrefixFlag(args []string) []string {
-	return append(args, fmt.Sprintf("--txt-prefix=%s", defaultTXTRecordPrefix))
+func (b *externalDNSContainerBuilder) addTXTPrefixFlag(args []string) []string {
+	return append(args, fmt.Sprintf("--txt-prefix=%s", txtPrefix(b.operandConfig)))
 }
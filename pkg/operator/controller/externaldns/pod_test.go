@@ -260,3 +260,126 @@ func TestDomainFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSRoutingPolicyAnnotationFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		allowed        []v1beta1.ExternalDNSAWSRoutingPolicy
+		expectedFilter string
+	}{
+		{
+			name:           "no routing policy allowed",
+			allowed:        nil,
+			expectedFilter: "!external-dns.alpha.kubernetes.io/aws-weight,!external-dns.alpha.kubernetes.io/aws-region,!external-dns.alpha.kubernetes.io/aws-failover,!external-dns.alpha.kubernetes.io/aws-geolocation-continent-code,!external-dns.alpha.kubernetes.io/aws-geolocation-country-code,!external-dns.alpha.kubernetes.io/aws-geolocation-subdivision-code,!external-dns.alpha.kubernetes.io/set-identifier",
+		},
+		{
+			name:           "failover allowed",
+			allowed:        []v1beta1.ExternalDNSAWSRoutingPolicy{v1beta1.AWSRoutingPolicyFailover, v1beta1.AWSRoutingPolicySetIdentifier},
+			expectedFilter: "!external-dns.alpha.kubernetes.io/aws-weight,!external-dns.alpha.kubernetes.io/aws-region,!external-dns.alpha.kubernetes.io/aws-geolocation-continent-code,!external-dns.alpha.kubernetes.io/aws-geolocation-country-code,!external-dns.alpha.kubernetes.io/aws-geolocation-subdivision-code",
+		},
+		{
+			name: "all routing policies allowed",
+			allowed: []v1beta1.ExternalDNSAWSRoutingPolicy{
+				v1beta1.AWSRoutingPolicyWeighted,
+				v1beta1.AWSRoutingPolicyLatency,
+				v1beta1.AWSRoutingPolicyFailover,
+				v1beta1.AWSRoutingPolicyGeolocation,
+				v1beta1.AWSRoutingPolicySetIdentifier,
+			},
+			expectedFilter: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if filter := awsRoutingPolicyAnnotationFilter(tc.allowed); filter != tc.expectedFilter {
+				t.Errorf("expected annotation filter %q, got %q", tc.expectedFilter, filter)
+			}
+		})
+	}
+}
+
+func TestAppendAnnotationFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		args         []string
+		requirements string
+		expectedArgs []string
+	}{
+		{
+			name:         "no annotation filter",
+			args:         []string{"--provider=aws"},
+			requirements: "!external-dns.alpha.kubernetes.io/aws-weight",
+			expectedArgs: []string{"--provider=aws", "--annotation-filter=!external-dns.alpha.kubernetes.io/aws-weight"},
+		},
+		{
+			name:         "existing annotation filter",
+			args:         []string{"--annotation-filter=team=a", "--provider=aws"},
+			requirements: "!external-dns.alpha.kubernetes.io/aws-weight",
+			expectedArgs: []string{"--annotation-filter=team=a,!external-dns.alpha.kubernetes.io/aws-weight", "--provider=aws"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if args := appendAnnotationFilter(tc.args, tc.requirements); !reflect.DeepEqual(args, tc.expectedArgs) {
+				t.Errorf("expected args %v, got %v", tc.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestAWSAssumeRoleForZone(t *testing.T) {
+	awsOptions := &v1beta1.ExternalDNSAWSProviderOptions{
+		AssumeRole: &v1beta1.ExternalDNSAWSAssumeRoleOptions{
+			ARN: "arn:aws:iam::123456789012:role/dns",
+		},
+		ZoneAssumeRoles: []v1beta1.ExternalDNSAWSZoneAssumeRoleOptions{
+			{
+				ZoneID: "private-zone",
+				ExternalDNSAWSAssumeRoleOptions: v1beta1.ExternalDNSAWSAssumeRoleOptions{
+					ARN:        "arn:aws:iam::210987654321:role/network",
+					ExternalID: "external-id",
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		awsOptions   *v1beta1.ExternalDNSAWSProviderOptions
+		zone         string
+		expectedRole *v1beta1.ExternalDNSAWSAssumeRoleOptions
+	}{
+		{
+			name:         "no AWS options",
+			awsOptions:   nil,
+			zone:         "private-zone",
+			expectedRole: nil,
+		},
+		{
+			name:       "zone specific role",
+			awsOptions: awsOptions,
+			zone:       "private-zone",
+			expectedRole: &v1beta1.ExternalDNSAWSAssumeRoleOptions{
+				ARN:        "arn:aws:iam::210987654321:role/network",
+				ExternalID: "external-id",
+			},
+		},
+		{
+			name:         "zone without specific role",
+			awsOptions:   awsOptions,
+			zone:         "public-zone",
+			expectedRole: awsOptions.AssumeRole,
+		},
+		{
+			name:         "all zones",
+			awsOptions:   awsOptions,
+			zone:         "",
+			expectedRole: awsOptions.AssumeRole,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			role := awsAssumeRoleForZone(tc.awsOptions, tc.zone)
+			if !reflect.DeepEqual(role, tc.expectedRole) {
+				t.Errorf("expected assume role %v, got %v", tc.expectedRole, role)
+			}
+		})
+	}
+}
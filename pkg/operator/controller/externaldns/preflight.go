@@ -0,0 +1,333 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+)
+
+const (
+	preflightAppName = "external-dns-preflight"
+	// jobNameLabel is the label set by the job controller on the job's pods.
+	jobNameLabel = "job-name"
+	// onceJobActiveDeadlineSeconds is the time given to the job to sync the records once.
+	onceJobActiveDeadlineSeconds = int64(300)
+	// jobMessageMaxLength limits the length of the container messages shown in the status.
+	jobMessageMaxLength = 512
+	// preflightTemplateHashAnnotation is the hash of the pod template checked by the preflight job.
+	preflightTemplateHashAnnotation = "externaldns.olm.openshift.io/preflight-template-hash"
+	// preflightAttemptAnnotation is the attempt of the preflight check for the same pod template.
+	preflightAttemptAnnotation = "externaldns.olm.openshift.io/preflight-attempt"
+	// preflightRetryBaseDelay is the delay before the failed preflight check is retried,
+	// it doubles with every failed attempt up to preflightRetryMaxDelay.
+	preflightRetryBaseDelay = time.Minute
+	preflightRetryMaxDelay  = 30 * time.Minute
+	// preflightFailedReason is the reason of the failed preflight check
+	// which doesn't report an authentication error.
+	preflightFailedReason = "PreflightFailed"
+)
+
+// authFailurePattern matches the authentication and credential errors
+// reported by the providers in the termination messages of the containers.
+var authFailurePattern = regexp.MustCompile(`(?i)(InvalidClientTokenId|SignatureDoesNotMatch|ExpiredToken|UnrecognizedClient|AccessDenied|Unauthorized|Forbidden|\b40[13]\b|AADSTS\d+|invalid_client|invalid_grant|invalid[ _-]credentials|NoCredentialProviders|could not find default credentials|authentication failed|permission denied)`)
+
+// preflightResult describes the outcome of the preflight check.
+type preflightResult struct {
+	// passed is true if the check succeeded.
+	passed bool
+	// condition describes the check.
+	condition metav1.Condition
+	// retryAfter is the time to wait before the failed check is retried.
+	retryAfter time.Duration
+}
+
+// ensurePreflight ensures that the preflight job for the given desired deployment has been run.
+// The job runs the containers of the desired deployment once in the dry run mode
+// to check that the provider is reachable with the given credentials.
+// The failed job is replaced with a new attempt after a delay which doubles with every attempt.
+// Returns the result of the check and an error when relevant.
+func (r *reconciler) ensurePreflight(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, desiredDeployment *appsv1.Deployment) (*preflightResult, error) {
+	templateHash, err := buildPodTemplateHash(&desiredDeployment.Spec.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the pod template's hash: %w", err)
+	}
+
+	current, err := r.currentPreflightJob(ctx, externalDNS, desiredDeployment.Namespace, templateHash)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return r.createPreflightJob(ctx, externalDNS, desiredDeployment, 1)
+	}
+
+	for _, cond := range current.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return &preflightResult{
+				passed: true,
+				condition: metav1.Condition{
+					Type:    ExternalDNSProviderReachableConditionType,
+					Status:  metav1.ConditionTrue,
+					Reason:  "PreflightSucceeded",
+					Message: "The preflight check reached the provider with the given credentials",
+				},
+			}, nil
+		case batchv1.JobFailed:
+			attempt := preflightAttempt(current)
+			retryAt := cond.LastTransitionTime.Add(preflightRetryDelay(attempt))
+			retryAfter := time.Until(retryAt)
+			if retryAfter <= 0 {
+				return r.createPreflightJob(ctx, externalDNS, desiredDeployment, attempt+1)
+			}
+			retry := fmt.Sprintf(" The check is retried at %s.", retryAt.UTC().Format(time.RFC3339))
+			if cond.Reason == batchv1.JobReasonDeadlineExceeded {
+				return &preflightResult{
+					condition: metav1.Condition{
+						Type:    ExternalDNSProviderReachableConditionType,
+						Status:  metav1.ConditionFalse,
+						Reason:  "PreflightTimeout",
+						Message: fmt.Sprintf("The preflight check didn't complete in %ds, the provider may be unreachable. The current deployment is kept.", onceJobActiveDeadlineSeconds) + retry,
+					},
+					retryAfter: retryAfter,
+				}, nil
+			}
+			failure := r.jobFailureMessage(ctx, current)
+			reason := preflightFailedReason
+			if authFailurePattern.MatchString(failure) {
+				reason = operatorv1beta1.ExternalDNSProviderAuthFailedReasonType
+			}
+			return &preflightResult{
+				condition: metav1.Condition{
+					Type:    ExternalDNSProviderReachableConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  reason,
+					Message: "The preflight check failed, the current deployment is kept." + failure + retry,
+				},
+				retryAfter: retryAfter,
+			}, nil
+		}
+	}
+
+	return &preflightResult{condition: preflightInProgressCondition()}, nil
+}
+
+// currentPreflightJob returns the latest attempt of the preflight job for the given pod template hash,
+// nil if no job was run for the pod template.
+func (r *reconciler) currentPreflightJob(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, namespace, templateHash string) (*batchv1.Job, error) {
+	jobs := &batchv1.JobList{}
+	if err := r.client.List(ctx, jobs, client.InNamespace(namespace), client.MatchingLabels{appNameLabel: preflightAppName, appInstanceLabel: externalDNS.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list %s jobs: %w", preflightAppName, err)
+	}
+	var current *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if job.Annotations[preflightTemplateHashAnnotation] != templateHash {
+			continue
+		}
+		if current == nil || preflightAttempt(job) > preflightAttempt(current) {
+			current = job
+		}
+	}
+	return current, nil
+}
+
+// createPreflightJob creates the given attempt of the preflight job for the given desired deployment.
+// The jobs of the previous attempts and of the previous desired deployments are deleted.
+func (r *reconciler) createPreflightJob(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, desiredDeployment *appsv1.Deployment, attempt int) (*preflightResult, error) {
+	desired, err := desiredPreflightJob(externalDNS, desiredDeployment, attempt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build preflight job: %w", err)
+	}
+
+	if err := controllerutil.SetControllerReference(externalDNS, desired, r.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set the controller reference for preflight job: %w", err)
+	}
+
+	if err := r.deleteOnceJobs(ctx, externalDNS, preflightAppName, desired.Namespace, desired.Name); err != nil {
+		return nil, err
+	}
+	if err := r.client.Create(ctx, desired); err != nil {
+		if errors.IsAlreadyExists(err) {
+			// the cache doesn't have the job created by the previous reconciliation yet
+			return &preflightResult{condition: preflightInProgressCondition()}, nil
+		}
+		return nil, fmt.Errorf("failed to create preflight job %s/%s: %w", desired.Namespace, desired.Name, err)
+	}
+	r.log.Info("created preflight job", "namespace", desired.Namespace, "name", desired.Name, "attempt", attempt)
+	return &preflightResult{condition: preflightInProgressCondition()}, nil
+}
+
+// desiredPreflightJob returns the given attempt of the job which runs the containers of the given deployment once in the dry run mode.
+// The name of the job depends on the whole pod template, including the annotations with the hashes of the mounted secret and configmaps,
+// so that a new check is run for every change of the deployment.
+func desiredPreflightJob(externalDNS *operatorv1beta1.ExternalDNS, deployment *appsv1.Deployment, attempt int) (*batchv1.Job, error) {
+	templateHash, err := buildPodTemplateHash(&deployment.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	job := desiredOnceJob(externalDNS, deployment, controller.ExternalDNSPreflightJobName(externalDNS, templateHash, attempt), preflightAppName)
+	job.Annotations = map[string]string{
+		preflightTemplateHashAnnotation: templateHash,
+		preflightAttemptAnnotation:      strconv.Itoa(attempt),
+	}
+	for i := range job.Spec.Template.Spec.Containers {
+		// the flag cannot be repeated, the operand may already run in the dry run mode
+		if !slices.Contains(job.Spec.Template.Spec.Containers[i].Args, dryRunArg) {
+			job.Spec.Template.Spec.Containers[i].Args = append(job.Spec.Template.Spec.Containers[i].Args, dryRunArg)
+		}
+	}
+	return job, nil
+}
+
+// preflightAttempt returns the attempt of the given preflight job.
+func preflightAttempt(job *batchv1.Job) int {
+	attempt, err := strconv.Atoi(job.Annotations[preflightAttemptAnnotation])
+	if err != nil || attempt < 1 {
+		return 1
+	}
+	return attempt
+}
+
+// preflightRetryDelay returns the delay before the given failed attempt of the preflight check is retried.
+func preflightRetryDelay(attempt int) time.Duration {
+	delay := preflightRetryBaseDelay
+	for i := 1; i < attempt && delay < preflightRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > preflightRetryMaxDelay {
+		return preflightRetryMaxDelay
+	}
+	return delay
+}
+
+// desiredOnceJob returns the job with the given name which runs the containers of the given deployment once.
+func desiredOnceJob(externalDNS *operatorv1beta1.ExternalDNS, deployment *appsv1.Deployment, name, appName string) *batchv1.Job {
+	jobLabels := map[string]string{
+		appNameLabel:     appName,
+		appInstanceLabel: externalDNS.Name,
+	}
+
+	template := deployment.Spec.Template.DeepCopy()
+	// the deployment's labels are not used to not mix the job pods with the operand ones
+	template.Labels = jobLabels
+	template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Args = append(template.Spec.Containers[i].Args, "--once")
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: deployment.Namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          ptr.To[int32](0),
+			ActiveDeadlineSeconds: ptr.To[int64](onceJobActiveDeadlineSeconds),
+			Template:              *template,
+		},
+	}
+}
+
+// deleteOnceJobs deletes the jobs of the given app and ExternalDNS except the one with the given name.
+func (r *reconciler) deleteOnceJobs(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, appName, namespace, keep string) error {
+	jobs := &batchv1.JobList{}
+	if err := r.client.List(ctx, jobs, client.InNamespace(namespace), client.MatchingLabels{appNameLabel: appName, appInstanceLabel: externalDNS.Name}); err != nil {
+		return fmt.Errorf("failed to list %s jobs: %w", appName, err)
+	}
+	for i := range jobs.Items {
+		if jobs.Items[i].Name == keep {
+			continue
+		}
+		if err := r.client.Delete(ctx, &jobs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s job %s/%s: %w", appName, namespace, jobs.Items[i].Name, err)
+		}
+		r.log.Info("deleted job", "app", appName, "namespace", namespace, "name", jobs.Items[i].Name)
+	}
+	return nil
+}
+
+// jobFailureMessage returns the termination messages of the failed containers of the given job.
+func (r *reconciler) jobFailureMessage(ctx context.Context, job *batchv1.Job) string {
+	pods, err := getFilteredPodsList(ctx, r.client, job.Namespace, labels.SelectorFromSet(labels.Set{jobNameLabel: job.Name}))
+	if err != nil {
+		r.log.Error(err, "failed to list job pods", "namespace", job.Namespace, "job", job.Name)
+		return ""
+	}
+
+	messages := []string{}
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated == nil || status.State.Terminated.ExitCode == 0 {
+				continue
+			}
+			message := strings.TrimSpace(status.State.Terminated.Message)
+			if len(message) > jobMessageMaxLength {
+				// the most recent logs are at the end
+				message = "..." + message[len(message)-jobMessageMaxLength:]
+			}
+			messages = append(messages, fmt.Sprintf(" Container %q exited with code %d: %s", status.Name, status.State.Terminated.ExitCode, message))
+		}
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "")
+}
+
+func preflightInProgressCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ExternalDNSProviderReachableConditionType,
+		Status:  metav1.ConditionUnknown,
+		Reason:  "PreflightInProgress",
+		Message: "The preflight check is running, the current deployment is kept until it succeeds",
+	}
+}
+
+// buildPodTemplateHash returns the hash of the given pod template.
+func buildPodTemplateHash(template *corev1.PodTemplateSpec) (string, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestDesiredPreflightJob(t *testing.T) {
+	extDNS := test.NewExternalDNS(test.Name).WithAWS().Build()
+	deployment := testPreflightDeployment("first")
+
+	job, err := desiredPreflightJob(extDNS, deployment, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if job.Namespace != test.OperandNamespace {
+		t.Errorf("expected namespace %q, got %q", test.OperandNamespace, job.Namespace)
+	}
+	if job.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("expected restart policy %q, got %q", corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	}
+	if job.Spec.Template.Labels[appNameLabel] != preflightAppName {
+		t.Errorf("expected pod template label %q, got %q", preflightAppName, job.Spec.Template.Labels[appNameLabel])
+	}
+	expectedArgs := []string{"--provider=aws", "--txt-owner-id=first", "--once", "--dry-run"}
+	if diff := cmp.Diff(expectedArgs, job.Spec.Template.Spec.Containers[0].Args); diff != "" {
+		t.Errorf("unexpected container args (-want +got):\n%s", diff)
+	}
+	if len(deployment.Spec.Template.Spec.Containers[0].Args) != 2 {
+		t.Errorf("expected the deployment not to be modified, got args %v", deployment.Spec.Template.Spec.Containers[0].Args)
+	}
+
+	if job.Annotations[preflightAttemptAnnotation] != "1" {
+		t.Errorf("expected attempt annotation %q, got %q", "1", job.Annotations[preflightAttemptAnnotation])
+	}
+
+	same, err := desiredPreflightJob(extDNS, testPreflightDeployment("first"), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if same.Name != job.Name {
+		t.Errorf("expected the same job name for the same deployment, got %q and %q", job.Name, same.Name)
+	}
+
+	other, err := desiredPreflightJob(extDNS, testPreflightDeployment("second"), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.Name == job.Name {
+		t.Errorf("expected different job names for different deployments, got %q", job.Name)
+	}
+
+	// the hashes of the mounted secret and configmaps are in the pod template's annotations
+	annotated := testPreflightDeployment("first")
+	annotated.Spec.Template.Annotations = map[string]string{credentialsAnnotation: "new-hash"}
+	rotated, err := desiredPreflightJob(extDNS, annotated, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated.Name == job.Name {
+		t.Errorf("expected different job names for different pod template annotations, got %q", job.Name)
+	}
+
+	retried, err := desiredPreflightJob(extDNS, testPreflightDeployment("first"), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retried.Name == job.Name {
+		t.Errorf("expected different job names for different attempts, got %q", job.Name)
+	}
+	if retried.Annotations[preflightTemplateHashAnnotation] != job.Annotations[preflightTemplateHashAnnotation] {
+		t.Errorf("expected the same template hash for different attempts, got %q and %q", job.Annotations[preflightTemplateHashAnnotation], retried.Annotations[preflightTemplateHashAnnotation])
+	}
+}
+
+func TestPreflightRetryDelay(t *testing.T) {
+	testCases := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 1, expected: time.Minute},
+		{attempt: 2, expected: 2 * time.Minute},
+		{attempt: 5, expected: 16 * time.Minute},
+		{attempt: 6, expected: 30 * time.Minute},
+		{attempt: 100, expected: 30 * time.Minute},
+	}
+	for _, tc := range testCases {
+		if got := preflightRetryDelay(tc.attempt); got != tc.expected {
+			t.Errorf("attempt %d: expected delay %v, got %v", tc.attempt, tc.expected, got)
+		}
+	}
+}
+
+func TestEnsurePreflight(t *testing.T) {
+	extDNS := test.NewExternalDNS(test.Name).WithAWS().Build()
+	deployment := testPreflightDeployment("owner")
+	desiredJob, err := desiredPreflightJob(extDNS, deployment, 1)
+	if err != nil {
+		t.Fatalf("failed to build the preflight job: %v", err)
+	}
+	retriedJob, err := desiredPreflightJob(extDNS, deployment, 2)
+	if err != nil {
+		t.Fatalf("failed to build the preflight job: %v", err)
+	}
+	oldJob, err := desiredPreflightJob(extDNS, testPreflightDeployment("old-owner"), 1)
+	if err != nil {
+		t.Fatalf("failed to build the preflight job: %v", err)
+	}
+	// the conditions' times are stored with the second precision
+	failedAt := metav1.NewTime(time.Now().Truncate(time.Second))
+	retryAt := " The check is retried at " + failedAt.Add(time.Minute).UTC().Format(time.RFC3339) + "."
+	longAgo := metav1.NewTime(failedAt.Add(-time.Hour))
+
+	testCases := []struct {
+		name              string
+		existingObjects   []runtime.Object
+		expectedPassed    bool
+		expectedRetry     bool
+		expectedCondition metav1.Condition
+		expectedJobs      []string
+	}{
+		{
+			name:              "Job created",
+			expectedCondition: preflightInProgressCondition(),
+			expectedJobs:      []string{desiredJob.Name},
+		},
+		{
+			name:              "Old job deleted",
+			existingObjects:   []runtime.Object{oldJob},
+			expectedCondition: preflightInProgressCondition(),
+			expectedJobs:      []string{desiredJob.Name},
+		},
+		{
+			name:              "Job in progress",
+			existingObjects:   []runtime.Object{testPreflightJob(desiredJob, batchv1.JobCondition{Type: batchv1.JobSuspended, Status: corev1.ConditionFalse})},
+			expectedCondition: preflightInProgressCondition(),
+			expectedJobs:      []string{desiredJob.Name},
+		},
+		{
+			name:            "Job complete",
+			existingObjects: []runtime.Object{testPreflightJob(desiredJob, batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})},
+			expectedPassed:  true,
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSProviderReachableConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "PreflightSucceeded",
+				Message: "The preflight check reached the provider with the given credentials",
+			},
+			expectedJobs: []string{desiredJob.Name},
+		},
+		{
+			name: "Job failed with authentication error",
+			existingObjects: []runtime.Object{
+				testPreflightJob(desiredJob, batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonBackoffLimitExceeded, LastTransitionTime: failedAt}),
+				testPreflightPod(desiredJob.Name, "level=fatal msg=\"InvalidClientTokenId: The security token included in the request is invalid.\""),
+			},
+			expectedRetry: true,
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSProviderReachableConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  operatorv1beta1.ExternalDNSProviderAuthFailedReasonType,
+				Message: "The preflight check failed, the current deployment is kept. Container \"" + ExternalDNSContainerName + "\" exited with code 1: level=fatal msg=\"InvalidClientTokenId: The security token included in the request is invalid.\"" + retryAt,
+			},
+			expectedJobs: []string{desiredJob.Name},
+		},
+		{
+			name: "Job failed with other error",
+			existingObjects: []runtime.Object{
+				testPreflightJob(desiredJob, batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonBackoffLimitExceeded, LastTransitionTime: failedAt}),
+				testPreflightPod(desiredJob.Name, "level=fatal msg=\"dial tcp: lookup route53.amazonaws.com: no such host\""),
+			},
+			expectedRetry: true,
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSProviderReachableConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "PreflightFailed",
+				Message: "The preflight check failed, the current deployment is kept. Container \"" + ExternalDNSContainerName + "\" exited with code 1: level=fatal msg=\"dial tcp: lookup route53.amazonaws.com: no such host\"" + retryAt,
+			},
+			expectedJobs: []string{desiredJob.Name},
+		},
+		{
+			name: "Job failed without termination message",
+			existingObjects: []runtime.Object{
+				testPreflightJob(desiredJob, batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonBackoffLimitExceeded, LastTransitionTime: failedAt}),
+			},
+			expectedRetry: true,
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSProviderReachableConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "PreflightFailed",
+				Message: "The preflight check failed, the current deployment is kept." + retryAt,
+			},
+			expectedJobs: []string{desiredJob.Name},
+		},
+		{
+			name:            "Job deadline exceeded",
+			existingObjects: []runtime.Object{testPreflightJob(desiredJob, batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonDeadlineExceeded, LastTransitionTime: failedAt})},
+			expectedRetry:   true,
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSProviderReachableConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "PreflightTimeout",
+				Message: "The preflight check didn't complete in 300s, the provider may be unreachable. The current deployment is kept." + retryAt,
+			},
+			expectedJobs: []string{desiredJob.Name},
+		},
+		{
+			name:              "Failed job retried after the delay",
+			existingObjects:   []runtime.Object{testPreflightJob(desiredJob, batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonBackoffLimitExceeded, LastTransitionTime: longAgo})},
+			expectedCondition: preflightInProgressCondition(),
+			expectedJobs:      []string{retriedJob.Name},
+		},
+		{
+			name: "Latest attempt used",
+			existingObjects: []runtime.Object{
+				testPreflightJob(desiredJob, batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonBackoffLimitExceeded, LastTransitionTime: longAgo}),
+				testPreflightJob(retriedJob, batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}),
+			},
+			expectedPassed: true,
+			expectedCondition: metav1.Condition{
+				Type:    ExternalDNSProviderReachableConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "PreflightSucceeded",
+				Message: "The preflight check reached the provider with the given credentials",
+			},
+			expectedJobs: []string{desiredJob.Name, retriedJob.Name},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).WithInterceptorFuncs(test.ServerSideApply).Build()
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				config: testConfig(),
+				log:    zap.New(zap.UseDevMode(true)),
+			}
+			result, err := r.ensurePreflight(context.TODO(), extDNS, deployment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.passed != tc.expectedPassed {
+				t.Errorf("expected passed %t, got %t", tc.expectedPassed, result.passed)
+			}
+			if diff := cmp.Diff(tc.expectedCondition, result.condition); diff != "" {
+				t.Errorf("unexpected condition (-want +got):\n%s", diff)
+			}
+			if tc.expectedRetry != (result.retryAfter > 0) || result.retryAfter > preflightRetryBaseDelay {
+				t.Errorf("unexpected retry delay %v", result.retryAfter)
+			}
+
+			jobs := &batchv1.JobList{}
+			if err := cl.List(context.TODO(), jobs, client.InNamespace(test.OperandNamespace)); err != nil {
+				t.Fatalf("failed to list jobs: %v", err)
+			}
+			names := []string{}
+			for _, job := range jobs.Items {
+				names = append(names, job.Name)
+			}
+			if diff := cmp.Diff(tc.expectedJobs, names); diff != "" {
+				t.Errorf("unexpected jobs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func testPreflightDeployment(owner string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "external-dns-test",
+			Namespace: test.OperandNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{appNameLabel: ExternalDNSBaseName},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  ExternalDNSContainerName,
+							Image: test.OperandImage,
+							Args:  []string{"--provider=aws", "--txt-owner-id=" + owner},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func testPreflightJob(desired *batchv1.Job, cond batchv1.JobCondition) *batchv1.Job {
+	job := desired.DeepCopy()
+	job.Status.Conditions = []batchv1.JobCondition{cond}
+	return job
+}
+
+func testPreflightPod(jobName, message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName + "-abcde",
+			Namespace: test.OperandNamespace,
+			Labels:    map[string]string{jobNameLabel: jobName},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: ExternalDNSContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestEnsureExternalDNSDeploymentPreflight(t *testing.T) {
+	extDNS := test.NewExternalDNS(test.Name).WithAWS().WithServiceSource().Build()
+	existing := testPreflightDeployment("owner")
+	existing.Name = test.OperandName
+
+	cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(existing).Build()
+	config := testConfig()
+	config.EnablePreflight = true
+	r := &reconciler{
+		client: cl,
+		scheme: test.Scheme,
+		config: config,
+		log:    zap.New(zap.UseDevMode(true)),
+	}
+
+	exist, current, preflight, err := r.ensureExternalDNSDeployment(context.TODO(), test.OperandNamespace, test.OperandImage, serviceAccount, testSecret(), nil, nil, nil, extDNS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exist {
+		t.Fatalf("expected the deployment to exist")
+	}
+	if preflight == nil {
+		t.Fatalf("expected the preflight result to be returned")
+	}
+	if diff := cmp.Diff(preflightInProgressCondition(), preflight.condition); diff != "" {
+		t.Errorf("unexpected condition (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(existing.Spec.Template.Spec.Containers, current.Spec.Template.Spec.Containers); diff != "" {
+		t.Errorf("expected the deployment to be kept until the preflight check succeeds (-want +got):\n%s", diff)
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := cl.List(context.TODO(), jobs, client.InNamespace(test.OperandNamespace)); err != nil {
+		t.Fatalf("failed to list jobs: %v", err)
+	}
+	if len(jobs.Items) != 1 {
+		t.Errorf("expected 1 preflight job, got %d", len(jobs.Items))
+	}
+}
+
+func TestAuthFailurePattern(t *testing.T) {
+	testCases := []struct {
+		message  string
+		expected bool
+	}{
+		{message: `level=fatal msg="InvalidClientTokenId: The security token included in the request is invalid."`, expected: true},
+		{message: `level=fatal msg="AccessDenied: User is not authorized to perform: route53:ListHostedZones"`, expected: true},
+		{message: `level=fatal msg="AADSTS7000215: Invalid client secret provided."`, expected: true},
+		{message: `level=fatal msg="googleapi: Error 403: Request had insufficient authentication scopes."`, expected: true},
+		{message: `level=fatal msg="google: could not find default credentials."`, expected: true},
+		{message: `level=fatal msg="dial tcp: lookup route53.amazonaws.com: no such host"`, expected: false},
+		{message: `level=fatal msg="context deadline exceeded"`, expected: false},
+		{message: `level=fatal msg="found 4031 records"`, expected: false},
+		{message: "", expected: false},
+	}
+	for _, tc := range testCases {
+		if got := authFailurePattern.MatchString(tc.message); got != tc.expected {
+			t.Errorf("expected %t for %q, got %t", tc.expected, tc.message, got)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+	ctrlutils "github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
+	"github.com/openshift/external-dns-operator/pkg/utils"
+)
+
+const (
+	// injectTrustedCABundleLabel makes the OpenShift cluster network operator inject
+	// the trusted CA bundle of the cluster-wide proxy into the labeled configmap.
+	injectTrustedCABundleLabel = "config.openshift.io/inject-trusted-cabundle"
+	proxyAnnotation            = "externaldns.olm.openshift.io/proxy-hash"
+)
+
+// desiredProxy returns the proxy ExternalDNS uses to reach the DNS provider of the given instance,
+// nil if the provider is reached directly. The proxy set in the operand config comes first,
+// then the cluster-wide proxy on OpenShift, then the proxy environment variables of the operator.
+func desiredProxy(operandConfig operatorv1beta1.ExternalDNSOperandConfig, externalDNS *operatorv1beta1.ExternalDNS, isOpenShift bool, clusterProxy *configv1.Proxy) *operatorv1beta1.ExternalDNSProxy {
+	if !utils.EnvProxySupportedProvider(externalDNS) {
+		return nil
+	}
+
+	proxy := operandConfig.Proxy
+	if proxy == nil {
+		if isOpenShift {
+			if clusterProxy != nil {
+				// the status holds the proxy in effect, including the computed no proxy list
+				proxy = &operatorv1beta1.ExternalDNSProxy{
+					HTTPProxy:  clusterProxy.Status.HTTPProxy,
+					HTTPSProxy: clusterProxy.Status.HTTPSProxy,
+					NoProxy:    clusterProxy.Status.NoProxy,
+				}
+			}
+		} else {
+			proxy = &operatorv1beta1.ExternalDNSProxy{
+				HTTPProxy:  os.Getenv(httpProxyEnvVar),
+				HTTPSProxy: os.Getenv(httpsProxyEnvVar),
+				NoProxy:    os.Getenv(noProxyEnvVar),
+			}
+		}
+	}
+
+	if proxy == nil || *proxy == (operatorv1beta1.ExternalDNSProxy{}) {
+		return nil
+	}
+	return proxy
+}
+
+// buildProxyHash returns the checksum of the given proxy settings.
+func buildProxyHash(proxy *operatorv1beta1.ExternalDNSProxy) (string, error) {
+	return buildStringMapHash(map[string]string{
+		httpProxyEnvVar:  proxy.HTTPProxy,
+		httpsProxyEnvVar: proxy.HTTPSProxy,
+		noProxyEnvVar:    proxy.NoProxy,
+	})
+}
+
+// hasProxyTrustedCA returns true if the given cluster-wide proxy references a trusted CA bundle.
+func hasProxyTrustedCA(clusterProxy *configv1.Proxy) bool {
+	return clusterProxy != nil && clusterProxy.Spec.TrustedCA.Name != ""
+}
+
+// ensureProxyTrustedCAConfigMap ensures that the configmap for the trusted CA bundle
+// of the cluster-wide proxy exists in the operand namespace.
+// The bundle is injected by the cluster network operator, merged with the system CAs.
+// Returns true if the bundle was injected, the configmap, and an error when relevant.
+func (r *reconciler) ensureProxyTrustedCAConfigMap(ctx context.Context) (bool, *corev1.ConfigMap, error) {
+	nsName := controller.ExternalDNSProxyTrustedCAConfigMapName(r.config.Namespace)
+
+	var current *corev1.ConfigMap
+	cm := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, nsName, cm); err != nil {
+		if !errors.IsNotFound(err) {
+			return false, nil, fmt.Errorf("failed to get the proxy trusted CA configmap %s: %w", nsName, err)
+		}
+	} else {
+		current = cm
+	}
+
+	// the label is the only field set by the operator
+	if current != nil && current.Labels[injectTrustedCABundleLabel] == "true" {
+		return current.Data[trustedCAFileKey] != "", current, nil
+	}
+
+	// the data is owned by the cluster network operator
+	desired := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nsName.Name,
+			Namespace: nsName.Namespace,
+			Labels: map[string]string{
+				injectTrustedCABundleLabel: "true",
+			},
+		},
+	}
+	changed, err := ctrlutils.Apply(ctx, r.client, current, desired)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to apply the proxy trusted CA configmap %s: %w", nsName, err)
+	}
+	if changed {
+		r.log.Info("applied proxy trusted CA configmap", "namespace", desired.Namespace, "name", desired.Name)
+	}
+
+	return desired.Data[trustedCAFileKey] != "", desired, nil
+}
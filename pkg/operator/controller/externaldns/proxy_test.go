@@ -0,0 +1,195 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestDesiredProxy(t *testing.T) {
+	clusterProxy := &configv1.Proxy{
+		Spec: configv1.ProxySpec{
+			HTTPSProxy: "https://proxy.cluster:3128",
+		},
+		Status: configv1.ProxyStatus{
+			HTTPSProxy: "https://proxy.cluster:3128",
+			NoProxy:    ".cluster.local,.svc,10.0.0.0/16",
+		},
+	}
+	instanceProxy := &operatorv1beta1.ExternalDNSProxy{
+		HTTPSProxy: "https://proxy.instance:3128",
+	}
+
+	testCases := []struct {
+		name          string
+		provider      operatorv1beta1.ExternalDNSProviderType
+		operandConfig operatorv1beta1.ExternalDNSOperandConfig
+		isOpenShift   bool
+		clusterProxy  *configv1.Proxy
+		envVars       map[string]string
+		expected      *operatorv1beta1.ExternalDNSProxy
+	}{
+		{
+			name:     "No proxy",
+			provider: operatorv1beta1.ProviderTypeAWS,
+		},
+		{
+			name:     "Operator environment",
+			provider: operatorv1beta1.ProviderTypeAWS,
+			envVars: map[string]string{
+				httpsProxyEnvVar: "https://proxy.env:3128",
+				noProxyEnvVar:    ".cluster.local",
+			},
+			expected: &operatorv1beta1.ExternalDNSProxy{
+				HTTPSProxy: "https://proxy.env:3128",
+				NoProxy:    ".cluster.local",
+			},
+		},
+		{
+			name:         "Cluster-wide proxy",
+			provider:     operatorv1beta1.ProviderTypeAWS,
+			isOpenShift:  true,
+			clusterProxy: clusterProxy,
+			envVars: map[string]string{
+				httpsProxyEnvVar: "https://proxy.env:3128",
+			},
+			expected: &operatorv1beta1.ExternalDNSProxy{
+				HTTPSProxy: "https://proxy.cluster:3128",
+				NoProxy:    ".cluster.local,.svc,10.0.0.0/16",
+			},
+		},
+		{
+			name:        "No cluster-wide proxy",
+			provider:    operatorv1beta1.ProviderTypeAWS,
+			isOpenShift: true,
+			envVars: map[string]string{
+				httpsProxyEnvVar: "https://proxy.env:3128",
+			},
+		},
+		{
+			name:          "Instance proxy overrides cluster-wide proxy",
+			provider:      operatorv1beta1.ProviderTypeAWS,
+			operandConfig: operatorv1beta1.ExternalDNSOperandConfig{Proxy: instanceProxy},
+			isOpenShift:   true,
+			clusterProxy:  clusterProxy,
+			expected:      instanceProxy,
+		},
+		{
+			name:          "Empty instance proxy bypasses cluster-wide proxy",
+			provider:      operatorv1beta1.ProviderTypeInfoblox,
+			operandConfig: operatorv1beta1.ExternalDNSOperandConfig{Proxy: &operatorv1beta1.ExternalDNSProxy{}},
+			isOpenShift:   true,
+			clusterProxy:  clusterProxy,
+		},
+		{
+			name:          "Empty instance proxy bypasses operator environment",
+			provider:      operatorv1beta1.ProviderTypeInfoblox,
+			operandConfig: operatorv1beta1.ExternalDNSOperandConfig{Proxy: &operatorv1beta1.ExternalDNSProxy{}},
+			envVars: map[string]string{
+				httpsProxyEnvVar: "https://proxy.env:3128",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, name := range []string{httpProxyEnvVar, httpsProxyEnvVar, noProxyEnvVar} {
+				t.Setenv(name, tc.envVars[name])
+			}
+			extDNS := &operatorv1beta1.ExternalDNS{
+				ObjectMeta: metav1.ObjectMeta{Name: test.Name},
+				Spec: operatorv1beta1.ExternalDNSSpec{
+					Provider: operatorv1beta1.ExternalDNSProvider{Type: tc.provider},
+				},
+			}
+			got := desiredProxy(tc.operandConfig, extDNS, tc.isOpenShift, tc.clusterProxy)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected proxy (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEnsureProxyTrustedCAConfigMap(t *testing.T) {
+	testCases := []struct {
+		name             string
+		existingObjects  []runtime.Object
+		expectedInjected bool
+	}{
+		{
+			name: "Configmap doesn't exist",
+		},
+		{
+			name: "Bundle not injected yet",
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "external-dns-proxy-trusted-ca", Namespace: test.OperandNamespace},
+				},
+			},
+		},
+		{
+			name: "Bundle injected",
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "external-dns-proxy-trusted-ca",
+						Namespace: test.OperandNamespace,
+						Labels:    map[string]string{injectTrustedCABundleLabel: "true"},
+					},
+					Data: map[string]string{trustedCAFileKey: "-----BEGIN CERTIFICATE-----"},
+				},
+			},
+			expectedInjected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).WithInterceptorFuncs(test.ServerSideApply).Build()
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				config: Config{Namespace: test.OperandNamespace},
+				log:    zap.New(zap.UseDevMode(true)),
+			}
+
+			injected, cm, err := r.ensureProxyTrustedCAConfigMap(context.TODO())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if injected != tc.expectedInjected {
+				t.Errorf("expected injected %t, got %t", tc.expectedInjected, injected)
+			}
+			if cm.Name != "external-dns-proxy-trusted-ca" || cm.Labels[injectTrustedCABundleLabel] != "true" {
+				t.Errorf("unexpected configmap %s/%s with labels %v", cm.Namespace, cm.Name, cm.Labels)
+			}
+		})
+	}
+}
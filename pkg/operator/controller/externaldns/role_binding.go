@@ -0,0 +1,180 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
+)
+
+// ensureExternalDNSRoleBindings ensures that the service account of the given ExternalDNS
+// is allowed to read the source resources: from all the namespaces if the given namespaces are nil,
+// from the given namespaces only otherwise. The nodes are cluster-scoped, they are readable in either case.
+// The role bindings of the namespaces which are not selected anymore are removed.
+func (r *reconciler) ensureExternalDNSRoleBindings(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, serviceAccount *corev1.ServiceAccount, namespaces []string) error {
+	clusterRole := controller.ExternalDNSGlobalResourceName()
+	if namespaces != nil {
+		clusterRole = controller.ExternalDNSNodesClusterRoleName
+	}
+	if err := r.ensureExternalDNSClusterRoleBinding(ctx, externalDNS, serviceAccount, clusterRole); err != nil {
+		return err
+	}
+
+	for _, ns := range namespaces {
+		if err := r.ensureExternalDNSRoleBinding(ctx, externalDNS, serviceAccount, ns); err != nil {
+			return err
+		}
+	}
+
+	selected := sets.New(namespaces...)
+	roleBindings := &rbacv1.RoleBindingList{}
+	if err := r.client.List(ctx, roleBindings, client.MatchingLabels(roleBindingLabels(externalDNS))); err != nil {
+		return fmt.Errorf("failed to list externalDNS role bindings: %w", err)
+	}
+	for i := range roleBindings.Items {
+		rb := &roleBindings.Items[i]
+		if selected.Has(rb.Namespace) || !metav1.IsControlledBy(rb, externalDNS) {
+			continue
+		}
+		if err := r.client.Delete(ctx, rb); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete externalDNS role binding %s/%s: %w", rb.Namespace, rb.Name, err)
+		}
+		r.log.Info("deleted externalDNS role binding", "namespace", rb.Namespace, "name", rb.Name)
+	}
+	return nil
+}
+
+// ensureExternalDNSClusterRoleBinding ensures that the service account of the given ExternalDNS
+// is bound to the given cluster role in all the namespaces.
+func (r *reconciler) ensureExternalDNSClusterRoleBinding(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, serviceAccount *corev1.ServiceAccount, clusterRole string) error {
+	desired := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   controller.ExternalDNSResourceName(externalDNS),
+			Labels: roleBindingLabels(externalDNS),
+		},
+		RoleRef:  clusterRoleRef(clusterRole),
+		Subjects: serviceAccountSubjects(serviceAccount),
+	}
+	if err := controllerutil.SetControllerReference(externalDNS, desired, r.scheme); err != nil {
+		return fmt.Errorf("failed to set the controller reference for cluster role binding: %w", err)
+	}
+
+	var current *rbacv1.ClusterRoleBinding
+	crb := &rbacv1.ClusterRoleBinding{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: desired.Name}, crb); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get externalDNS cluster role binding %s: %w", desired.Name, err)
+		}
+	} else {
+		current = crb
+	}
+
+	if current != nil && current.RoleRef != desired.RoleRef {
+		// the role reference is immutable, the binding is recreated
+		if err := r.client.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete externalDNS cluster role binding %s: %w", current.Name, err)
+		}
+		r.log.Info("deleted externalDNS cluster role binding", "name", current.Name, "clusterrole", current.RoleRef.Name)
+		current = nil
+	}
+
+	changed, err := utils.Apply(ctx, r.client, current, desired)
+	if err != nil {
+		return fmt.Errorf("failed to apply externalDNS cluster role binding %s: %w", desired.Name, err)
+	}
+	if changed {
+		r.log.Info("applied externalDNS cluster role binding", "name", desired.Name, "clusterrole", clusterRole)
+	}
+	return nil
+}
+
+// ensureExternalDNSRoleBinding ensures that the service account of the given ExternalDNS
+// is allowed to read the source resources of the given namespace.
+func (r *reconciler) ensureExternalDNSRoleBinding(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, serviceAccount *corev1.ServiceAccount, namespace string) error {
+	desired := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.ExternalDNSResourceName(externalDNS),
+			Namespace: namespace,
+			Labels:    roleBindingLabels(externalDNS),
+		},
+		// the cluster role is granted in the namespace of the binding only
+		RoleRef:  clusterRoleRef(controller.ExternalDNSGlobalResourceName()),
+		Subjects: serviceAccountSubjects(serviceAccount),
+	}
+	if err := controllerutil.SetControllerReference(externalDNS, desired, r.scheme); err != nil {
+		return fmt.Errorf("failed to set the controller reference for role binding: %w", err)
+	}
+
+	var current *rbacv1.RoleBinding
+	rb := &rbacv1.RoleBinding{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, rb); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get externalDNS role binding %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+	} else {
+		current = rb
+	}
+
+	changed, err := utils.Apply(ctx, r.client, current, desired)
+	if err != nil {
+		return fmt.Errorf("failed to apply externalDNS role binding %s/%s: %w", desired.Namespace, desired.Name, err)
+	}
+	if changed {
+		r.log.Info("applied externalDNS role binding", "namespace", desired.Namespace, "name", desired.Name)
+	}
+	return nil
+}
+
+// roleBindingLabels returns the labels of the role bindings of the given ExternalDNS,
+// the operator caches only the labeled role bindings.
+func roleBindingLabels(externalDNS *operatorv1beta1.ExternalDNS) map[string]string {
+	return map[string]string{
+		appNameLabel:     controller.ExternalDNSBaseName,
+		appInstanceLabel: externalDNS.Name,
+	}
+}
+
+func clusterRoleRef(name string) rbacv1.RoleRef {
+	return rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     name,
+	}
+}
+
+func serviceAccountSubjects(serviceAccount *corev1.ServiceAccount) []rbacv1.Subject {
+	return []rbacv1.Subject{
+		{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      serviceAccount.Name,
+			Namespace: serviceAccount.Namespace,
+		},
+	}
+}
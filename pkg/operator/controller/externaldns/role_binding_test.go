@@ -0,0 +1,165 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestEnsureExternalDNSRoleBindings(t *testing.T) {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: test.OperandName, Namespace: test.OperandNamespace},
+	}
+	expectedSubjects := []rbacv1.Subject{{Kind: "ServiceAccount", Name: test.OperandName, Namespace: test.OperandNamespace}}
+
+	testCases := []struct {
+		name                   string
+		namespaces             []string
+		existingClusterRole    string
+		existingNamespaces     []string
+		expectedClusterRole    string
+		expectedRoleBindingNss []string
+	}{
+		{
+			name:                "All namespaces",
+			expectedClusterRole: "external-dns",
+		},
+		{
+			name:                   "Selected namespaces",
+			namespaces:             []string{"tenant-a", "tenant-b"},
+			expectedClusterRole:    "external-dns-nodes",
+			expectedRoleBindingNss: []string{"tenant-a", "tenant-b"},
+		},
+		{
+			name:                   "Namespace deselected",
+			namespaces:             []string{"tenant-a"},
+			existingClusterRole:    "external-dns-nodes",
+			existingNamespaces:     []string{"tenant-a", "tenant-b"},
+			expectedClusterRole:    "external-dns-nodes",
+			expectedRoleBindingNss: []string{"tenant-a"},
+		},
+		{
+			name:                "Switched to all namespaces",
+			existingClusterRole: "external-dns-nodes",
+			existingNamespaces:  []string{"tenant-a"},
+			expectedClusterRole: "external-dns",
+		},
+		{
+			name:                   "Switched to selected namespaces",
+			namespaces:             []string{"tenant-a"},
+			existingClusterRole:    "external-dns",
+			expectedClusterRole:    "external-dns-nodes",
+			expectedRoleBindingNss: []string{"tenant-a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extDNS := testAWSExternalDNS(operatorv1beta1.SourceTypeService)
+			extDNS.UID = "1"
+
+			existing := []client.Object{}
+			if tc.existingClusterRole != "" {
+				existing = append(existing, &rbacv1.ClusterRoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: test.OperandName, Labels: roleBindingLabels(extDNS)},
+					RoleRef:    clusterRoleRef(tc.existingClusterRole),
+					Subjects:   expectedSubjects,
+				})
+			}
+			for _, ns := range tc.existingNamespaces {
+				rb := &rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: test.OperandName, Namespace: ns, Labels: roleBindingLabels(extDNS)},
+					RoleRef:    clusterRoleRef("external-dns"),
+					Subjects:   expectedSubjects,
+				}
+				if err := controllerutil.SetControllerReference(extDNS, rb, test.Scheme); err != nil {
+					t.Fatalf("failed to set the controller reference: %v", err)
+				}
+				existing = append(existing, rb)
+			}
+			// not owned by the instance
+			existing = append(existing, &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: test.OperandName, Namespace: "unrelated", Labels: roleBindingLabels(extDNS)},
+				RoleRef:    clusterRoleRef("external-dns"),
+			})
+
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(existing...).WithInterceptorFuncs(test.ServerSideApply).Build()
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				log:    zap.New(zap.UseDevMode(true)),
+			}
+
+			if err := r.ensureExternalDNSRoleBindings(context.TODO(), extDNS, sa, tc.namespaces); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			crb := &rbacv1.ClusterRoleBinding{}
+			if err := cl.Get(context.TODO(), types.NamespacedName{Name: test.OperandName}, crb); err != nil {
+				t.Fatalf("failed to get the cluster role binding: %v", err)
+			}
+			if crb.RoleRef.Name != tc.expectedClusterRole {
+				t.Errorf("expected the cluster role binding to reference %q, got %q", tc.expectedClusterRole, crb.RoleRef.Name)
+			}
+			if diff := cmp.Diff(expectedSubjects, crb.Subjects); diff != "" {
+				t.Errorf("unexpected cluster role binding subjects (-want +got):\n%s", diff)
+			}
+
+			rbs := &rbacv1.RoleBindingList{}
+			if err := cl.List(context.TODO(), rbs); err != nil {
+				t.Fatalf("failed to list the role bindings: %v", err)
+			}
+			gotNss := []string{}
+			for _, rb := range rbs.Items {
+				if rb.Namespace == "unrelated" {
+					continue
+				}
+				gotNss = append(gotNss, rb.Namespace)
+				if rb.RoleRef.Name != "external-dns" || rb.Name != test.OperandName {
+					t.Errorf("unexpected role binding %s/%s referencing %q", rb.Namespace, rb.Name, rb.RoleRef.Name)
+				}
+				if diff := cmp.Diff(expectedSubjects, rb.Subjects); diff != "" {
+					t.Errorf("unexpected role binding subjects (-want +got):\n%s", diff)
+				}
+			}
+			if len(rbs.Items) == len(gotNss) {
+				t.Errorf("expected the role binding not owned by the instance to be kept")
+			}
+			expectedNss := tc.expectedRoleBindingNss
+			if expectedNss == nil {
+				expectedNss = []string{}
+			}
+			if diff := cmp.Diff(expectedNss, gotNss); diff != "" {
+				t.Errorf("unexpected namespaces of role bindings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
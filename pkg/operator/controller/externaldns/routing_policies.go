@@ -0,0 +1,140 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+)
+
+const (
+	// routingPoliciesRefreshPeriod is the period of the check of the source resources
+	// against the allowed routing policies: the source resources are not watched.
+	routingPoliciesRefreshPeriod = 5 * time.Minute
+	// skippedSourcesMaxReported limits the number of the skipped source resources listed in the status.
+	skippedSourcesMaxReported = 10
+)
+
+var (
+	serviceGVK = schema.GroupVersionKind{Version: "v1", Kind: "ServiceList"}
+	routeGVK   = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "RouteList"}
+)
+
+// routingPoliciesRestricted returns true if the given ExternalDNS restricts the routing policies of the source resources.
+func routingPoliciesRestricted(externalDNS *operatorv1beta1.ExternalDNS) bool {
+	return externalDNS.Spec.Provider.Type == operatorv1beta1.ProviderTypeAWS && externalDNS.Spec.Provider.AWS != nil && externalDNS.Spec.Provider.AWS.RoutingPolicies != nil
+}
+
+// computeRoutingPoliciesCondition returns the condition which lists the source resources skipped by the operand
+// because they request a routing policy which is not allowed.
+// Returns nil if the routing policies are not restricted and the condition was not reported before.
+func (r *reconciler) computeRoutingPoliciesCondition(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, namespaces []string) (*metav1.Condition, error) {
+	if !routingPoliciesRestricted(externalDNS) {
+		if meta.FindStatusCondition(externalDNS.Status.Conditions, ExternalDNSRoutingPoliciesAllowedConditionType) == nil {
+			return nil, nil
+		}
+		return &metav1.Condition{
+			Type:    ExternalDNSRoutingPoliciesAllowedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "NotRestricted",
+			Message: "All the routing policies are allowed",
+		}, nil
+	}
+
+	skipped, err := r.currentSkippedSources(ctx, externalDNS, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) == 0 {
+		return &metav1.Condition{
+			Type:    ExternalDNSRoutingPoliciesAllowedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "NoSourceSkipped",
+			Message: "No source resource requests a routing policy which is not allowed",
+		}, nil
+	}
+	listed := skipped
+	if len(listed) > skippedSourcesMaxReported {
+		listed = append(listed[:skippedSourcesMaxReported:skippedSourcesMaxReported], fmt.Sprintf("and %d more", len(skipped)-skippedSourcesMaxReported))
+	}
+	return &metav1.Condition{
+		Type:    ExternalDNSRoutingPoliciesAllowedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "RoutingPolicyNotAllowed",
+		Message: fmt.Sprintf("The source resources which request a routing policy which is not allowed are not published: %s", strings.Join(listed, ", ")),
+	}, nil
+}
+
+// currentSkippedSources returns the sorted namespaced names of the source resources of the given ExternalDNS
+// which are annotated with the routing policies which are not allowed.
+// Only the metadata of the source resources is read, from the API server: the operator doesn't cache them.
+func (r *reconciler) currentSkippedSources(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, namespaces []string) ([]string, error) {
+	var gvk schema.GroupVersionKind
+	switch externalDNS.Spec.Source.Type {
+	case operatorv1beta1.SourceTypeService:
+		gvk = serviceGVK
+	case operatorv1beta1.SourceTypeRoute:
+		gvk = routeGVK
+	default:
+		return nil, nil
+	}
+
+	selector := labels.Everything()
+	if externalDNS.Spec.Source.LabelFilter != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(externalDNS.Spec.Source.LabelFilter); err != nil {
+			return nil, fmt.Errorf("failed to parse the label filter: %w", err)
+		}
+	}
+
+	listNamespaces := namespaces
+	if listNamespaces == nil {
+		// all the namespaces
+		listNamespaces = []string{metav1.NamespaceAll}
+	}
+
+	notAllowed := awsNotAllowedRoutingPolicyAnnotations(externalDNS.Spec.Provider.AWS.RoutingPolicies.Allowed)
+	skipped := []string{}
+	for _, ns := range listNamespaces {
+		sources := &metav1.PartialObjectMetadataList{}
+		sources.SetGroupVersionKind(gvk)
+		if err := r.apiReader.List(ctx, sources, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("failed to list %s source resources: %w", externalDNS.Spec.Source.Type, err)
+		}
+		for _, source := range sources.Items {
+			for _, annotation := range notAllowed {
+				if _, found := source.Annotations[annotation]; found {
+					skipped = append(skipped, source.Namespace+"/"+source.Name)
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(skipped)
+	return skipped, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestComputeRoutingPoliciesCondition(t *testing.T) {
+	services := []runtime.Object{
+		testRoutingPolicyService("tenant-a", "weighted", map[string]string{"external-dns.alpha.kubernetes.io/aws-weight": "10", "external-dns.alpha.kubernetes.io/set-identifier": "a"}),
+		testRoutingPolicyService("tenant-a", "failover", map[string]string{"external-dns.alpha.kubernetes.io/aws-failover": "PRIMARY", "external-dns.alpha.kubernetes.io/set-identifier": "a"}),
+		testRoutingPolicyService("tenant-b", "geolocation", map[string]string{"external-dns.alpha.kubernetes.io/aws-geolocation-country-code": "FR", "external-dns.alpha.kubernetes.io/set-identifier": "b"}),
+		testRoutingPolicyService("tenant-b", "plain", nil),
+	}
+	failoverOnly := testAWSExternalDNSRoutingPolicies(operatorv1beta1.SourceTypeService, operatorv1beta1.AWSRoutingPolicyFailover, operatorv1beta1.AWSRoutingPolicySetIdentifier)
+	allAllowed := testAWSExternalDNSRoutingPolicies(operatorv1beta1.SourceTypeService,
+		operatorv1beta1.AWSRoutingPolicyWeighted,
+		operatorv1beta1.AWSRoutingPolicyLatency,
+		operatorv1beta1.AWSRoutingPolicyFailover,
+		operatorv1beta1.AWSRoutingPolicyGeolocation,
+		operatorv1beta1.AWSRoutingPolicySetIdentifier,
+	)
+	previouslyRestricted := testAWSExternalDNS(operatorv1beta1.SourceTypeService)
+	previouslyRestricted.Status.Conditions = []metav1.Condition{{Type: ExternalDNSRoutingPoliciesAllowedConditionType, Status: metav1.ConditionFalse}}
+
+	testCases := []struct {
+		name              string
+		externalDNS       *operatorv1beta1.ExternalDNS
+		namespaces        []string
+		expectedCondition *metav1.Condition
+	}{
+		{
+			name:        "Not restricted",
+			externalDNS: testAWSExternalDNS(operatorv1beta1.SourceTypeService),
+		},
+		{
+			name:        "Restriction removed",
+			externalDNS: previouslyRestricted,
+			expectedCondition: &metav1.Condition{
+				Type:    ExternalDNSRoutingPoliciesAllowedConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "NotRestricted",
+				Message: "All the routing policies are allowed",
+			},
+		},
+		{
+			name:        "All routing policies allowed",
+			externalDNS: allAllowed,
+			expectedCondition: &metav1.Condition{
+				Type:    ExternalDNSRoutingPoliciesAllowedConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "NoSourceSkipped",
+				Message: "No source resource requests a routing policy which is not allowed",
+			},
+		},
+		{
+			name:        "Sources skipped",
+			externalDNS: failoverOnly,
+			expectedCondition: &metav1.Condition{
+				Type:    ExternalDNSRoutingPoliciesAllowedConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "RoutingPolicyNotAllowed",
+				Message: "The source resources which request a routing policy which is not allowed are not published: tenant-a/weighted, tenant-b/geolocation",
+			},
+		},
+		{
+			name:        "Sources of the selected namespaces skipped",
+			externalDNS: failoverOnly,
+			namespaces:  []string{"tenant-b"},
+			expectedCondition: &metav1.Condition{
+				Type:    ExternalDNSRoutingPoliciesAllowedConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "RoutingPolicyNotAllowed",
+				Message: "The source resources which request a routing policy which is not allowed are not published: tenant-b/geolocation",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(services...).Build()
+			r := &reconciler{
+				client:    cl,
+				apiReader: cl,
+				scheme:    test.Scheme,
+				log:       zap.New(zap.UseDevMode(true)),
+			}
+			cond, err := r.computeRoutingPoliciesCondition(context.TODO(), tc.externalDNS, tc.namespaces)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedCondition, cond); diff != "" {
+				t.Errorf("unexpected condition (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func testRoutingPolicyService(namespace, name string, annotations map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
 	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
 )
 
 // ensureExternalDNSServiceAccount ensures that the externalDNS service account exists.
@@ -45,14 +46,14 @@ func (r *reconciler) ensureExternalDNSServiceAccount(ctx context.Context, namesp
 		return false, nil, err
 	}
 
-	if !exist {
-		if err := r.createExternalDNSServiceAccount(ctx, desired); err != nil {
-			return false, nil, err
-		}
-		return r.currentExternalDNSServiceAccount(ctx, nsName)
+	changed, err := utils.Apply(ctx, r.client, current, desired)
+	if err != nil {
+		return exist, current, fmt.Errorf("failed to apply externalDNS service account %s/%s: %w", desired.Namespace, desired.Name, err)
 	}
-
-	return true, current, nil
+	if changed {
+		r.log.Info("applied externalDNS service account", "namespace", desired.Namespace, "name", desired.Name)
+	}
+	return true, desired, nil
 }
 
 // currentExternalDNSServiceAccount gets the current externalDNS service account resource.
@@ -76,13 +77,3 @@ func desiredExternalDNSServiceAccount(namespace string, externalDNS *operatorv1b
 		},
 	}
 }
-
-// createExternalDNSServiceAccount creates the given service account using the reconciler's client.
-func (r *reconciler) createExternalDNSServiceAccount(ctx context.Context, sa *corev1.ServiceAccount) error {
-	if err := r.client.Create(ctx, sa); err != nil {
-		return fmt.Errorf("failed to create externalDNS service account %s/%s: %w", sa.Namespace, sa.Name, err)
-	}
-
-	r.log.Info("created externalDNS service account", "namespace", sa.Namespace, "name", sa.Name)
-	return nil
-}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -25,14 +26,27 @@ const (
 	ExternalDNSDeploymentReplicasMinAvailableConditionType = "DeploymentReplicasMinAvailable"
 	ExternalDNSDeploymentReplicasAllAvailableConditionType = "DeploymentReplicasAllAvailable"
 	ExternalDNSCredentialsSecretExistsConditionType        = "CredentialsSecretExists"
+	ExternalDNSConflictConditionType                       = "Conflict"
+	ExternalDNSCredentialsSecretValidConditionType         = "CredentialsSecretValid"
+	ExternalDNSProviderReachableConditionType              = "ProviderReachable"
+	ExternalDNSRecordsCleanedUpConditionType               = "RecordsCleanedUp"
+	ExternalDNSSourceNamespacesSelectedConditionType       = "SourceNamespacesSelected"
+	ExternalDNSRoutingPoliciesAllowedConditionType         = "RoutingPoliciesAllowed"
 )
 
 // clock is to enable unit testing
 var clock utilclock.WithTickerAndDelayedExecution = utilclock.RealClock{}
 
+// operandStatus holds the status fields reported by the operand.
+type operandStatus struct {
+	dryRunPlan *operatorv1beta1.ExternalDNSDryRunPlan
+	manualSync *operatorv1beta1.ExternalDNSManualSync
+}
+
 // updateExternalDNSStatus updates the status of the given externaldns instance with
-// the status of the operand deployment and the credentials secret.
-func (r *reconciler) updateExternalDNSStatus(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, currentDeployment *appsv1.Deployment, secretExists bool) error {
+// the status of the operand deployment, the credentials secret, the operand and the given conditions.
+// The fields reported by the operand are kept as they are if the operand status is nil.
+func (r *reconciler) updateExternalDNSStatus(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, currentDeployment *appsv1.Deployment, secretExists bool, operand *operandStatus, conditions ...metav1.Condition) error {
 	extDNSWithStatus := externalDNS.DeepCopy()
 	// deployment
 	if currentDeployment != nil {
@@ -54,9 +68,18 @@ func (r *reconciler) updateExternalDNSStatus(ctx context.Context, externalDNS *o
 		secretExistsCond.Message = "The credentials secret not found."
 	}
 	extDNSWithStatus.Status.Conditions = mergeConditions(extDNSWithStatus.Status.Conditions, secretExistsCond)
+	extDNSWithStatus.Status.Conditions = mergeConditions(extDNSWithStatus.Status.Conditions, conditions...)
+	// other instances
+	extDNSWithStatus.Status.Conditions = mergeConditions(extDNSWithStatus.Status.Conditions, computeConflictCondition(ctx, r.client, externalDNS))
 
 	extDNSWithStatus.Status.ObservedGeneration = extDNSWithStatus.Generation
 	extDNSWithStatus.Status.Zones = extDNSWithStatus.Spec.Zones
+	extDNSWithStatus.Status.ManagementState = managementState(extDNSWithStatus)
+	if operand != nil {
+		extDNSWithStatus.Status.DryRunPlan = operand.dryRunPlan
+		extDNSWithStatus.Status.ManualSync = operand.manualSync
+	}
+	reportStatusMetrics(extDNSWithStatus)
 	if !externalDNSStatusesEqual(extDNSWithStatus.Status, externalDNS.Status) {
 		return r.client.Status().Update(ctx, extDNSWithStatus)
 	}
@@ -249,6 +272,45 @@ func computeDeploymentPodsScheduledCondition(ctx context.Context, cl client.Clie
 
 }
 
+// computeConflictCondition lists the ExternalDNS instances and returns an externalDNS condition
+// which is true if any of them manages the same DNS names in the same zones as the given instance.
+func computeConflictCondition(ctx context.Context, cl client.Client, externalDNS *operatorv1beta1.ExternalDNS) metav1.Condition {
+	externalDNSList := &operatorv1beta1.ExternalDNSList{}
+	if err := cl.List(ctx, externalDNSList); err != nil {
+		return metav1.Condition{
+			Type:    ExternalDNSConflictConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "ConflictUnknown",
+			Message: "Unable to list ExternalDNS instances: " + err.Error(),
+		}
+	}
+
+	// Sort instances so that the message is deterministic.
+	sort.Slice(externalDNSList.Items, func(i, j int) bool {
+		return externalDNSList.Items[i].Name < externalDNSList.Items[j].Name
+	})
+	conflicts := []string{}
+	for i := range externalDNSList.Items {
+		if overlap := externalDNS.Overlap(&externalDNSList.Items[i]); overlap != "" {
+			conflicts = append(conflicts, fmt.Sprintf("%s are also managed by ExternalDNS %q.", overlap, externalDNSList.Items[i].Name))
+		}
+	}
+	if len(conflicts) != 0 {
+		return metav1.Condition{
+			Type:    ExternalDNSConflictConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "OverlappingZonesAndDomains",
+			Message: strings.Join(conflicts, " ") + " The instances may overwrite or refuse to update each other's DNS records.",
+		}
+	}
+	return metav1.Condition{
+		Type:    ExternalDNSConflictConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NoConflicts",
+		Message: "No other ExternalDNS instance manages the same zones and domains",
+	}
+}
+
 // mergeConditions updates the conditions list with new conditions.
 // Each condition is added if no condition of the same type already exists.
 // Otherwise, the condition is merged with the existing condition of the same type.
@@ -282,9 +344,18 @@ func externalDNSStatusesEqual(a, b operatorv1beta1.ExternalDNSStatus) bool {
 	if a.ObservedGeneration != b.ObservedGeneration {
 		return false
 	}
+	if a.ManagementState != b.ManagementState {
+		return false
+	}
 	if !zonesEqual(a.Zones, b.Zones) {
 		return false
 	}
+	if !cmp.Equal(a.DryRunPlan, b.DryRunPlan, cmpopts.EquateEmpty()) {
+		return false
+	}
+	if !cmp.Equal(a.ManualSync, b.ManualSync) {
+		return false
+	}
 	return conditionsEqual(a.Conditions, b.Conditions)
 }
 
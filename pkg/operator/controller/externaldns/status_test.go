@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -429,6 +430,10 @@ func TestGetPodsList(t *testing.T) {
 func TestUpdateExternalDNSStatus(t *testing.T) {
 	aDeployment := fakeDeployment(appsv1.DeploymentAvailable, corev1.ConditionTrue, 8, "25%", "25%", 8, "external-dns-operator")
 	anExternalDNS := fakeExternalDNS()
+	namespacedExternalDNS := fakeExternalDNS()
+	namespacedExternalDNS.Spec.Source.Namespaces = []string{"team-a"}
+	otherNamespacedExternalDNS := fakeConflictingExternalDNS()
+	otherNamespacedExternalDNS.Spec.Source.Namespaces = []string{"team-b"}
 	namespacedName := types.NamespacedName{
 		Namespace: "",
 		Name:      test.Name,
@@ -439,6 +444,7 @@ func TestUpdateExternalDNSStatus(t *testing.T) {
 		existingObjects    []runtime.Object
 		existingExtDNS     *operatorv1beta1.ExternalDNS
 		secretExists       bool
+		secretValidCond    metav1.Condition
 		errExpected        bool
 		expectedResult     operatorv1beta1.ExternalDNS
 	}{
@@ -448,6 +454,7 @@ func TestUpdateExternalDNSStatus(t *testing.T) {
 			existingObjects:    append(fakeRuntimeObjectFromPodList(fakePodList()), &aDeployment, anExternalDNS),
 			existingExtDNS:     anExternalDNS,
 			secretExists:       true,
+			secretValidCond:    fakeSecretValidCondition(),
 			errExpected:        false,
 			expectedResult:     fakeExternalDNSWithStatus(),
 		},
@@ -456,9 +463,30 @@ func TestUpdateExternalDNSStatus(t *testing.T) {
 			existingObjects: append(fakeRuntimeObjectFromPodList(fakePodList()), anExternalDNS),
 			existingExtDNS:  anExternalDNS,
 			secretExists:    false,
+			secretValidCond: fakeSecretNotFoundCondition(),
 			errExpected:     false,
 			expectedResult:  fakeExternalDNSWithStatusSecretMissing(),
 		},
+		{
+			name:               "Conflicting instance",
+			existingDeployment: &aDeployment,
+			existingObjects:    append(fakeRuntimeObjectFromPodList(fakePodList()), &aDeployment, anExternalDNS, fakeConflictingExternalDNS()),
+			existingExtDNS:     anExternalDNS,
+			secretExists:       true,
+			secretValidCond:    fakeSecretValidCondition(),
+			errExpected:        false,
+			expectedResult:     fakeExternalDNSWithStatusConflict(),
+		},
+		{
+			name:               "Instance publishing from other source namespaces",
+			existingDeployment: &aDeployment,
+			existingObjects:    append(fakeRuntimeObjectFromPodList(fakePodList()), &aDeployment, namespacedExternalDNS, otherNamespacedExternalDNS),
+			existingExtDNS:     namespacedExternalDNS,
+			secretExists:       true,
+			secretValidCond:    fakeSecretValidCondition(),
+			errExpected:        false,
+			expectedResult:     fakeExternalDNSWithStatus(),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -470,7 +498,7 @@ func TestUpdateExternalDNSStatus(t *testing.T) {
 			log:    zap.New(zap.UseDevMode(true)),
 		}
 
-		err := r.updateExternalDNSStatus(context.TODO(), tc.existingExtDNS, tc.existingDeployment, tc.secretExists)
+		err := r.updateExternalDNSStatus(context.TODO(), tc.existingExtDNS, tc.existingDeployment, tc.secretExists, nil, tc.secretValidCond)
 		if tc.errExpected && err == nil {
 			t.Error("expected an error but got none")
 		} else if !tc.errExpected {
@@ -622,10 +650,65 @@ func fakeExternalDNSWithStatus() operatorv1beta1.ExternalDNS {
 	extDNS.Status.Conditions = append(extDNS.Status.Conditions, condMinReplicaAvailable)
 	extDNS.Status.Conditions = append(extDNS.Status.Conditions, condPodScheduled)
 	extDNS.Status.Conditions = append(extDNS.Status.Conditions, condSecretExists)
+	extDNS.Status.Conditions = append(extDNS.Status.Conditions, fakeSecretValidCondition())
+	extDNS.Status.Conditions = append(extDNS.Status.Conditions, fakeNoConflictCondition())
 
 	return *extDNS
 }
 
+func fakeExternalDNSWithStatusConflict() operatorv1beta1.ExternalDNS {
+	extDNS := fakeExternalDNSWithStatus()
+	extDNS.Status.Conditions[len(extDNS.Status.Conditions)-1] = metav1.Condition{
+		Type:    ExternalDNSConflictConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "OverlappingZonesAndDomains",
+		Message: `domains "apps.example.com" in zones "public-zone" are also managed by ExternalDNS "other". The instances may overwrite or refuse to update each other's DNS records.`,
+	}
+	return extDNS
+}
+
+func fakeConflictingExternalDNS() *operatorv1beta1.ExternalDNS {
+	extDNS := fakeExternalDNS()
+	extDNS.Name = "other"
+	extDNS.Spec.Domains = []operatorv1beta1.ExternalDNSDomain{
+		{
+			FilterType: operatorv1beta1.FilterTypeInclude,
+			ExternalDNSDomainUnion: operatorv1beta1.ExternalDNSDomainUnion{
+				MatchType: operatorv1beta1.DomainMatchTypeExact,
+				Name:      ptr.To("apps.example.com"),
+			},
+		},
+	}
+	return extDNS
+}
+
+func fakeSecretValidCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ExternalDNSCredentialsSecretValidConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "SecretValid",
+		Message: "The credentials secret has all the keys required by the provider.",
+	}
+}
+
+func fakeSecretNotFoundCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ExternalDNSCredentialsSecretValidConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "SecretNotFound",
+		Message: `The credentials secret "testSecret" not found in "external-dns-operator" namespace.`,
+	}
+}
+
+func fakeNoConflictCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    ExternalDNSConflictConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NoConflicts",
+		Message: "No other ExternalDNS instance manages the same zones and domains",
+	}
+}
+
 func fakeExternalDNSWithStatusSecretMissing() operatorv1beta1.ExternalDNS {
 	extDNS := fakeExternalDNS()
 	extDNS.Status.Conditions = append(extDNS.Status.Conditions, metav1.Condition{
@@ -634,6 +717,8 @@ func fakeExternalDNSWithStatusSecretMissing() operatorv1beta1.ExternalDNS {
 		Reason:  "SecretNotFound",
 		Message: "The credentials secret not found.",
 	})
+	extDNS.Status.Conditions = append(extDNS.Status.Conditions, fakeSecretNotFoundCondition())
+	extDNS.Status.Conditions = append(extDNS.Status.Conditions, fakeNoConflictCondition())
 
 	return *extDNS
 }
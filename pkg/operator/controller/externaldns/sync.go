@@ -0,0 +1,135 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+)
+
+const (
+	syncAppName = "external-dns-sync"
+)
+
+// manualSyncPending returns true if the sync requested with the sync-now annotation
+// is not started or not finished yet.
+func manualSyncPending(externalDNS *operatorv1beta1.ExternalDNS) bool {
+	request := externalDNS.Annotations[operatorv1beta1.SyncNowAnnotation]
+	if len(request) == 0 {
+		return false
+	}
+	last := externalDNS.Status.ManualSync
+	return last == nil || last.Request != request || last.Result == operatorv1beta1.ManualSyncInProgress
+}
+
+// ensureManualSync ensures that the sync requested with the sync-now annotation has been run.
+// The sync runs the containers of the current deployment once in a job,
+// the sync is run only once for every value of the annotation.
+// The deployment is expected to be scaled down while the sync is pending:
+// the operand would apply the changes concurrently with the job otherwise.
+// Returns the status of the last manual sync.
+func (r *reconciler) ensureManualSync(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, deployment *appsv1.Deployment) (*operatorv1beta1.ExternalDNSManualSync, error) {
+	last := externalDNS.Status.ManualSync
+	request := externalDNS.Annotations[operatorv1beta1.SyncNowAnnotation]
+	if len(request) == 0 || deployment == nil {
+		return last, nil
+	}
+	if !manualSyncPending(externalDNS) {
+		// the requested sync is finished
+		return last, nil
+	}
+	requested := last != nil && last.Request == request
+
+	desired := desiredOnceJob(externalDNS, deployment, controller.ExternalDNSSyncJobName(externalDNS, request), syncAppName)
+	if err := controllerutil.SetControllerReference(externalDNS, desired, r.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set the controller reference for sync job: %w", err)
+	}
+
+	now := metav1.NewTime(clock.Now())
+	current := &batchv1.Job{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, current)
+	if errors.IsNotFound(err) && requested {
+		// the cache may not have the job created by the previous reconciliation yet
+		err = r.apiReader.Get(ctx, types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, current)
+	}
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get sync job %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+		if requested {
+			return &operatorv1beta1.ExternalDNSManualSync{
+				Request:        request,
+				Result:         operatorv1beta1.ManualSyncFailed,
+				StartTime:      last.StartTime,
+				CompletionTime: &now,
+				Message:        "The sync job was deleted before it finished",
+			}, nil
+		}
+		// only the last sync is kept
+		if err := r.deleteOnceJobs(ctx, externalDNS, syncAppName, desired.Namespace, desired.Name); err != nil {
+			return nil, err
+		}
+		// the job may exist if the cache doesn't have it yet
+		if err := r.client.Create(ctx, desired); err == nil {
+			r.log.Info("created sync job", "namespace", desired.Namespace, "name", desired.Name, "request", request)
+		} else if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create sync job %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+		return &operatorv1beta1.ExternalDNSManualSync{
+			Request:   request,
+			Result:    operatorv1beta1.ManualSyncInProgress,
+			StartTime: &now,
+			Message:   "The sync job is running",
+		}, nil
+	}
+
+	sync := &operatorv1beta1.ExternalDNSManualSync{
+		Request:   request,
+		Result:    operatorv1beta1.ManualSyncInProgress,
+		StartTime: &now,
+		Message:   "The sync job is running",
+	}
+	if requested {
+		sync.StartTime = last.StartTime
+	}
+	for _, cond := range current.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			sync.Result = operatorv1beta1.ManualSyncSucceeded
+			sync.CompletionTime = &now
+			sync.Message = "The records were synced"
+		case batchv1.JobFailed:
+			sync.Result = operatorv1beta1.ManualSyncFailed
+			sync.CompletionTime = &now
+			sync.Message = fmt.Sprintf("The sync job failed (reason: %s).", cond.Reason) + r.jobFailureMessage(ctx, current)
+		}
+	}
+	return sync, nil
+}
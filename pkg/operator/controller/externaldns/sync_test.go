@@ -0,0 +1,255 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestEnsureManualSync(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC))
+	origClock := clock
+	clock = testingclock.NewFakeClock(now.Time)
+	defer func() { clock = origClock }()
+
+	deployment := testPreflightDeployment("owner")
+	syncExtDNS := func(request string, last *operatorv1beta1.ExternalDNSManualSync) *operatorv1beta1.ExternalDNS {
+		extDNS := test.NewExternalDNS(test.Name).WithAWS().Build()
+		if len(request) != 0 {
+			extDNS.Annotations = map[string]string{operatorv1beta1.SyncNowAnnotation: request}
+		}
+		extDNS.Status.ManualSync = last
+		return extDNS
+	}
+	inProgress := &operatorv1beta1.ExternalDNSManualSync{
+		Request:   "1",
+		Result:    operatorv1beta1.ManualSyncInProgress,
+		StartTime: &startTime,
+		Message:   "The sync job is running",
+	}
+	succeeded := &operatorv1beta1.ExternalDNSManualSync{
+		Request:        "1",
+		Result:         operatorv1beta1.ManualSyncSucceeded,
+		StartTime:      &startTime,
+		CompletionTime: &now,
+		Message:        "The records were synced",
+	}
+
+	testCases := []struct {
+		name               string
+		externalDNS        *operatorv1beta1.ExternalDNS
+		deployment         *appsv1.Deployment
+		existingObjects    []runtime.Object
+		expectedManualSync *operatorv1beta1.ExternalDNSManualSync
+		expectedJobs       int
+	}{
+		{
+			name:        "No annotation",
+			externalDNS: syncExtDNS("", nil),
+			deployment:  deployment,
+		},
+		{
+			name:               "No annotation keeps the last sync",
+			externalDNS:        syncExtDNS("", succeeded),
+			deployment:         deployment,
+			expectedManualSync: succeeded,
+		},
+		{
+			name:        "No deployment",
+			externalDNS: syncExtDNS("1", nil),
+		},
+		{
+			name:        "Sync requested",
+			externalDNS: syncExtDNS("1", nil),
+			deployment:  deployment,
+			expectedManualSync: &operatorv1beta1.ExternalDNSManualSync{
+				Request:   "1",
+				Result:    operatorv1beta1.ManualSyncInProgress,
+				StartTime: &now,
+				Message:   "The sync job is running",
+			},
+			expectedJobs: 1,
+		},
+		{
+			name:            "Sync requested again",
+			externalDNS:     syncExtDNS("2", succeeded),
+			deployment:      deployment,
+			existingObjects: []runtime.Object{testSyncJob(syncExtDNS("1", nil), deployment, "1")},
+			expectedManualSync: &operatorv1beta1.ExternalDNSManualSync{
+				Request:   "2",
+				Result:    operatorv1beta1.ManualSyncInProgress,
+				StartTime: &now,
+				Message:   "The sync job is running",
+			},
+			expectedJobs: 1,
+		},
+		{
+			name:               "Sync in progress",
+			externalDNS:        syncExtDNS("1", inProgress),
+			deployment:         deployment,
+			existingObjects:    []runtime.Object{testSyncJob(syncExtDNS("1", nil), deployment, "1")},
+			expectedManualSync: inProgress,
+			expectedJobs:       1,
+		},
+		{
+			name:               "Sync succeeded",
+			externalDNS:        syncExtDNS("1", inProgress),
+			deployment:         deployment,
+			existingObjects:    []runtime.Object{testSyncJob(syncExtDNS("1", nil), deployment, "1", batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})},
+			expectedManualSync: succeeded,
+			expectedJobs:       1,
+		},
+		{
+			name:            "Sync failed",
+			externalDNS:     syncExtDNS("1", inProgress),
+			deployment:      deployment,
+			existingObjects: []runtime.Object{testSyncJob(syncExtDNS("1", nil), deployment, "1", batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonBackoffLimitExceeded})},
+			expectedManualSync: &operatorv1beta1.ExternalDNSManualSync{
+				Request:        "1",
+				Result:         operatorv1beta1.ManualSyncFailed,
+				StartTime:      &startTime,
+				CompletionTime: &now,
+				Message:        "The sync job failed (reason: BackoffLimitExceeded).",
+			},
+			expectedJobs: 1,
+		},
+		{
+			name:        "Sync job deleted",
+			externalDNS: syncExtDNS("1", inProgress),
+			deployment:  deployment,
+			expectedManualSync: &operatorv1beta1.ExternalDNSManualSync{
+				Request:        "1",
+				Result:         operatorv1beta1.ManualSyncFailed,
+				StartTime:      &startTime,
+				CompletionTime: &now,
+				Message:        "The sync job was deleted before it finished",
+			},
+		},
+		{
+			name:               "Sync finished",
+			externalDNS:        syncExtDNS("1", succeeded),
+			deployment:         deployment,
+			expectedManualSync: succeeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build()
+			r := &reconciler{
+				client:    cl,
+				apiReader: cl,
+				scheme:    test.Scheme,
+				config:    testConfig(),
+				log:       zap.New(zap.UseDevMode(true)),
+			}
+			sync, err := r.ensureManualSync(context.TODO(), tc.externalDNS, tc.deployment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedManualSync, sync); diff != "" {
+				t.Errorf("unexpected manual sync (-want +got):\n%s", diff)
+			}
+
+			jobs := &batchv1.JobList{}
+			if err := cl.List(context.TODO(), jobs, client.InNamespace(test.OperandNamespace)); err != nil {
+				t.Fatalf("failed to list jobs: %v", err)
+			}
+			if len(jobs.Items) != tc.expectedJobs {
+				t.Errorf("expected %d jobs, got %d", tc.expectedJobs, len(jobs.Items))
+			}
+			for _, job := range jobs.Items {
+				if args := job.Spec.Template.Spec.Containers[0].Args; args[len(args)-1] != "--once" {
+					t.Errorf("expected the sync job to run once, got args %v", args)
+				}
+			}
+		})
+	}
+}
+
+func TestManualSyncPending(t *testing.T) {
+	testCases := []struct {
+		name     string
+		request  string
+		last     *operatorv1beta1.ExternalDNSManualSync
+		expected bool
+	}{
+		{
+			name: "No annotation",
+			last: &operatorv1beta1.ExternalDNSManualSync{Request: "1", Result: operatorv1beta1.ManualSyncInProgress},
+		},
+		{
+			name:     "First request",
+			request:  "1",
+			expected: true,
+		},
+		{
+			name:     "New request",
+			request:  "2",
+			last:     &operatorv1beta1.ExternalDNSManualSync{Request: "1", Result: operatorv1beta1.ManualSyncSucceeded},
+			expected: true,
+		},
+		{
+			name:     "Request in progress",
+			request:  "1",
+			last:     &operatorv1beta1.ExternalDNSManualSync{Request: "1", Result: operatorv1beta1.ManualSyncInProgress},
+			expected: true,
+		},
+		{
+			name:    "Request failed",
+			request: "1",
+			last:    &operatorv1beta1.ExternalDNSManualSync{Request: "1", Result: operatorv1beta1.ManualSyncFailed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extDNS := test.NewExternalDNS(test.Name).WithAWS().Build()
+			if len(tc.request) != 0 {
+				extDNS.Annotations = map[string]string{operatorv1beta1.SyncNowAnnotation: tc.request}
+			}
+			extDNS.Status.ManualSync = tc.last
+			if got := manualSyncPending(extDNS); got != tc.expected {
+				t.Errorf("expected pending %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func testSyncJob(extDNS *operatorv1beta1.ExternalDNS, deployment *appsv1.Deployment, request string, conds ...batchv1.JobCondition) *batchv1.Job {
+	job := desiredOnceJob(extDNS, deployment, controller.ExternalDNSSyncJobName(extDNS, request), syncAppName)
+	job.Status.Conditions = conds
+	return job
+}
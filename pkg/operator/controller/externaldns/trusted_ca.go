@@ -0,0 +1,109 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldnscontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	controller "github.com/openshift/external-dns-operator/pkg/operator/controller"
+	ctrlutils "github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
+)
+
+const (
+	providerTrustedCAAnnotation = "externaldns.olm.openshift.io/provider-trusted-ca-configmap-hash"
+)
+
+// ensureProviderTrustedCAConfigMap ensures that the trusted CA configmap of the given ExternalDNS instance
+// is in sync with the configmap referenced in its provider, merged with the given trusted CA configmap of the operator.
+// The configmap of the instance is removed if the provider doesn't reference any trusted CA.
+// Returns true if the referenced configmap exists, the configmap of the instance
+// (nil if the provider doesn't reference any trusted CA), and an error when relevant.
+func (r *reconciler) ensureProviderTrustedCAConfigMap(ctx context.Context, externalDNS *operatorv1beta1.ExternalDNS, globalCAConfigMap *corev1.ConfigMap) (bool, *corev1.ConfigMap, error) {
+	targetName := controller.ExternalDNSProviderTrustedCAConfigMapName(r.config.Namespace, externalDNS)
+
+	targetExists, target, err := r.currentExternalDNSTrustedCAConfigMap(ctx, targetName)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get the provider trusted CA configmap %s: %w", targetName, err)
+	}
+
+	if externalDNS.Spec.Provider.TrustedCA == nil {
+		if targetExists {
+			if err := r.client.Delete(ctx, target); err != nil && !errors.IsNotFound(err) {
+				return false, nil, fmt.Errorf("failed to delete the provider trusted CA configmap %s: %w", targetName, err)
+			}
+			r.log.Info("deleted provider trusted CA configmap", "namespace", targetName.Namespace, "name", targetName.Name)
+		}
+		return true, nil, nil
+	}
+
+	sourceName := types.NamespacedName{Namespace: r.config.OperatorNamespace, Name: externalDNS.Spec.Provider.TrustedCA.Name}
+	sourceExists, source, err := r.currentExternalDNSTrustedCAConfigMap(ctx, sourceName)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get the source provider trusted CA configmap %s: %w", sourceName, err)
+	}
+	if !sourceExists {
+		return false, nil, nil
+	}
+	if strings.TrimSpace(source.Data[trustedCAFileKey]) == "" {
+		return true, nil, fmt.Errorf("source provider trusted CA configmap %s has no %q key", sourceName, trustedCAFileKey)
+	}
+
+	desired := desiredProviderTrustedCAConfigMap(targetName, source, globalCAConfigMap)
+	if err := controllerutil.SetControllerReference(externalDNS, desired, r.scheme); err != nil {
+		return true, nil, fmt.Errorf("failed to set the controller reference for the provider trusted CA configmap: %w", err)
+	}
+
+	changed, err := ctrlutils.Apply(ctx, r.client, target, desired)
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to apply the provider trusted CA configmap %s: %w", targetName, err)
+	}
+	if changed {
+		r.log.Info("applied provider trusted CA configmap", "namespace", desired.Namespace, "name", desired.Name)
+	}
+	return true, desired, nil
+}
+
+// desiredProviderTrustedCAConfigMap returns the trusted CA configmap of an ExternalDNS instance:
+// the given trusted CA bundle of the operator followed by the bundle of the provider.
+func desiredProviderTrustedCAConfigMap(name types.NamespacedName, source, globalCAConfigMap *corev1.ConfigMap) *corev1.ConfigMap {
+	bundles := []string{}
+	if globalCAConfigMap != nil {
+		if bundle := strings.TrimSpace(globalCAConfigMap.Data[trustedCAFileKey]); bundle != "" {
+			bundles = append(bundles, bundle)
+		}
+	}
+	bundles = append(bundles, strings.TrimSpace(source.Data[trustedCAFileKey]))
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+		},
+		Data: map[string]string{
+			trustedCAFileKey: strings.Join(bundles, "\n") + "\n",
+		},
+	}
+}
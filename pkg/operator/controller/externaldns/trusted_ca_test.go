@@ -0,0 +1,163 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package externaldnscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1beta1 "github.com/openshift/external-dns-operator/api/v1beta1"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+const (
+	testProviderCAConfigMapName = "infoblox-grid-ca"
+	testProviderCABundle        = "-----BEGIN CERTIFICATE-----\nprovider\n-----END CERTIFICATE-----"
+	testGlobalCABundle          = "-----BEGIN CERTIFICATE-----\nglobal\n-----END CERTIFICATE-----"
+)
+
+func TestEnsureProviderTrustedCAConfigMap(t *testing.T) {
+	targetName := types.NamespacedName{Namespace: test.OperandNamespace, Name: "external-dns-trusted-ca-" + test.Name}
+
+	testCases := []struct {
+		name             string
+		existingObjects  []runtime.Object
+		trustedCA        *operatorv1beta1.ConfigMapReference
+		globalCA         *corev1.ConfigMap
+		expectedExist    bool
+		expectedData     map[string]string
+		expectedDeletion bool
+		errExpected      bool
+	}{
+		{
+			name:          "No trusted CA",
+			expectedExist: true,
+		},
+		{
+			name:             "Trusted CA removed",
+			existingObjects:  []runtime.Object{testConfigMap(targetName, testProviderCABundle)},
+			expectedExist:    true,
+			expectedDeletion: true,
+		},
+		{
+			name:      "Source configmap doesn't exist",
+			trustedCA: &operatorv1beta1.ConfigMapReference{Name: testProviderCAConfigMapName},
+		},
+		{
+			name: "Source configmap without bundle",
+			existingObjects: []runtime.Object{
+				testConfigMap(types.NamespacedName{Namespace: test.OperatorNamespace, Name: testProviderCAConfigMapName}, ""),
+			},
+			trustedCA:     &operatorv1beta1.ConfigMapReference{Name: testProviderCAConfigMapName},
+			expectedExist: true,
+			errExpected:   true,
+		},
+		{
+			name: "Provider bundle only",
+			existingObjects: []runtime.Object{
+				testConfigMap(types.NamespacedName{Namespace: test.OperatorNamespace, Name: testProviderCAConfigMapName}, testProviderCABundle),
+			},
+			trustedCA:     &operatorv1beta1.ConfigMapReference{Name: testProviderCAConfigMapName},
+			expectedExist: true,
+			expectedData:  map[string]string{trustedCAFileKey: testProviderCABundle + "\n"},
+		},
+		{
+			name: "Provider bundle merged with global bundle",
+			existingObjects: []runtime.Object{
+				testConfigMap(types.NamespacedName{Namespace: test.OperatorNamespace, Name: testProviderCAConfigMapName}, testProviderCABundle),
+				testConfigMap(targetName, "outdated"),
+			},
+			trustedCA:     &operatorv1beta1.ConfigMapReference{Name: testProviderCAConfigMapName},
+			globalCA:      testConfigMap(types.NamespacedName{Namespace: test.OperandNamespace, Name: "external-dns-trusted-ca"}, testGlobalCABundle),
+			expectedExist: true,
+			expectedData:  map[string]string{trustedCAFileKey: testGlobalCABundle + "\n" + testProviderCABundle + "\n"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).WithInterceptorFuncs(test.ServerSideApply).Build()
+			r := &reconciler{
+				client: cl,
+				scheme: test.Scheme,
+				config: Config{
+					Namespace:         test.OperandNamespace,
+					OperatorNamespace: test.OperatorNamespace,
+				},
+				log: zap.New(zap.UseDevMode(true)),
+			}
+			extDNS := testAWSExternalDNS(operatorv1beta1.SourceTypeService)
+			extDNS.Spec.Provider.TrustedCA = tc.trustedCA
+
+			exist, cm, err := r.ensureProviderTrustedCAConfigMap(context.TODO(), extDNS, tc.globalCA)
+			if err != nil && !tc.errExpected {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && tc.errExpected {
+				t.Fatalf("error expected but not received")
+			}
+			if exist != tc.expectedExist {
+				t.Errorf("expected exist %t, got %t", tc.expectedExist, exist)
+			}
+
+			if tc.expectedData == nil {
+				if cm != nil {
+					t.Errorf("expected no configmap, got %s/%s", cm.Namespace, cm.Name)
+				}
+			} else {
+				current := &corev1.ConfigMap{}
+				if err := cl.Get(context.TODO(), targetName, current); err != nil {
+					t.Fatalf("failed to get the provider trusted CA configmap: %v", err)
+				}
+				if diff := cmp.Diff(tc.expectedData, current.Data); diff != "" {
+					t.Errorf("unexpected configmap data (-want +got):\n%s", diff)
+				}
+				if len(current.OwnerReferences) != 1 || current.OwnerReferences[0].Name != extDNS.Name {
+					t.Errorf("expected the configmap to be owned by %q, got %v", extDNS.Name, current.OwnerReferences)
+				}
+			}
+
+			if tc.expectedDeletion {
+				if err := cl.Get(context.TODO(), targetName, &corev1.ConfigMap{}); !errors.IsNotFound(err) {
+					t.Errorf("expected the provider trusted CA configmap to be deleted, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func testConfigMap(name types.NamespacedName, bundle string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+		},
+	}
+	if bundle != "" {
+		cm.Data = map[string]string{trustedCAFileKey: bundle}
+	}
+	return cm
+}
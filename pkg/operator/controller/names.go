@@ -36,6 +36,9 @@ const (
 	ControllerName                     = "external_dns_controller"
 	SecretFromCloudCredentialsOperator = "externaldns-cloud-credentials"
 	ServiceAccountName                 = "external-dns-operator"
+	// ExternalDNSNodesClusterRoleName is the name of the cluster role which allows ExternalDNS to read the nodes,
+	// it's bound to the operands which read the source resources of the selected namespaces only.
+	ExternalDNSNodesClusterRoleName = "external-dns-nodes"
 )
 
 func ExternalDNSCredentialsRequestName(externalDNS *operatorv1beta1.ExternalDNS) types.NamespacedName {
@@ -67,6 +70,38 @@ func ExternalDNSContainerName(zone string) string {
 	return ExternalDNSBaseName + "-" + hashString(zone)
 }
 
+// ExternalDNSNamespacedContainerName returns the container name unique for the given DNS zone
+// and the namespace of the source resources, an empty namespace means all the namespaces.
+func ExternalDNSNamespacedContainerName(zone, namespace string) string {
+	if namespace == "" {
+		return ExternalDNSContainerName(zone)
+	}
+	return ExternalDNSContainerName(zone + "/" + namespace)
+}
+
+// ExternalDNSPreflightJobName returns the name of the given attempt of the preflight job for the given ExternalDNS instance
+// and the hash of the operand's pod template.
+func ExternalDNSPreflightJobName(externalDNS *operatorv1beta1.ExternalDNS, templateHash string, attempt int) string {
+	return fmt.Sprintf("%s-preflight-%s-%d", ExternalDNSBaseName, hashString(externalDNS.Name+templateHash), attempt)
+}
+
+// ExternalDNSSyncJobName returns the name of the job which runs the sync
+// requested by the given value of the sync-now annotation.
+func ExternalDNSSyncJobName(externalDNS *operatorv1beta1.ExternalDNS, request string) string {
+	return ExternalDNSBaseName + "-sync-" + hashString(externalDNS.Name+request)
+}
+
+// ExternalDNSCleanupJobName returns the name of the job which removes the DNS records of the given ExternalDNS instance.
+func ExternalDNSCleanupJobName(externalDNS *operatorv1beta1.ExternalDNS) string {
+	return ExternalDNSBaseName + "-cleanup-" + hashString(externalDNS.Name)
+}
+
+// ExternalDNSReleasedOwnersCleanupJobName returns the name of the job which removes the DNS records
+// of the given TXT owners released by the given generation of the operand deployment.
+func ExternalDNSReleasedOwnersCleanupJobName(externalDNS *operatorv1beta1.ExternalDNS, owners []string, generation int64) string {
+	return fmt.Sprintf("%s-owner-cleanup-%s-%d", ExternalDNSBaseName, hashString(externalDNS.Name+strings.Join(owners, ",")), generation)
+}
+
 // ExternalDNSDestCredentialsSecretName returns the namespaced name of the destination (operand) credentials secret
 func ExternalDNSDestCredentialsSecretName(operandNamespace, extdnsName string) types.NamespacedName {
 	return types.NamespacedName{
@@ -75,6 +110,15 @@ func ExternalDNSDestCredentialsSecretName(operandNamespace, extdnsName string) t
 	}
 }
 
+// ExternalDNSDryRunPlanConfigMapName returns the namespaced name of the configmap
+// which lists the changes planned by the given ExternalDNS instance in the dry run mode.
+func ExternalDNSDryRunPlanConfigMapName(operandNamespace, extdnsName string) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: operandNamespace,
+		Name:      ExternalDNSBaseName + "-plan-" + extdnsName,
+	}
+}
+
 // ExternalDNSDestTrustedCAConfigMapName returns the namespaced name of the destination (operand) trusted CA configmap
 func ExternalDNSDestTrustedCAConfigMapName(operandNamespace string) types.NamespacedName {
 	return types.NamespacedName{
@@ -83,6 +127,24 @@ func ExternalDNSDestTrustedCAConfigMapName(operandNamespace string) types.Namesp
 	}
 }
 
+// ExternalDNSProviderTrustedCAConfigMapName returns the namespaced name of the operand configmap
+// containing the trusted CA bundle of the provider of the given ExternalDNS instance.
+func ExternalDNSProviderTrustedCAConfigMapName(operandNamespace string, externalDNS *operatorv1beta1.ExternalDNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: operandNamespace,
+		Name:      ExternalDNSBaseName + "-trusted-ca-" + externalDNS.Name,
+	}
+}
+
+// ExternalDNSProxyTrustedCAConfigMapName returns the namespaced name of the operand configmap
+// into which the trusted CA bundle of the OpenShift cluster-wide proxy is injected.
+func ExternalDNSProxyTrustedCAConfigMapName(operandNamespace string) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: operandNamespace,
+		Name:      ExternalDNSBaseName + "-proxy-trusted-ca",
+	}
+}
+
 func ExternalDNSCredentialsSourceNamespace(cfg *operatorconfig.Config) string {
 	// TODO: use openshift-config namespace for OpenShift?
 	return cfg.OperatorNamespace
@@ -96,7 +158,7 @@ func ExternalDNSCredentialsSecretNameFromProvider(externalDNS *operatorv1beta1.E
 			return externalDNS.Spec.Provider.AWS.Credentials.Name
 		}
 	case operatorv1beta1.ProviderTypeAzure:
-		if externalDNS.Spec.Provider.Azure != nil {
+		if externalDNS.Spec.Provider.Azure != nil && externalDNS.Spec.Provider.Azure.ConfigFile != nil {
 			return externalDNS.Spec.Provider.Azure.ConfigFile.Name
 		}
 	case operatorv1beta1.ProviderTypeGCP:
@@ -105,7 +167,13 @@ func ExternalDNSCredentialsSecretNameFromProvider(externalDNS *operatorv1beta1.E
 		}
 	case operatorv1beta1.ProviderTypeBlueCat:
 		if externalDNS.Spec.Provider.BlueCat != nil {
-			return externalDNS.Spec.Provider.BlueCat.ConfigFile.Name
+			if configFile := externalDNS.Spec.Provider.BlueCat.ConfigFile; configFile != nil && len(configFile.Name) != 0 {
+				return configFile.Name
+			}
+			if externalDNS.Spec.Provider.BlueCat.Credentials != nil {
+				// config file is generated from the options and the credentials
+				return externalDNS.Spec.Provider.BlueCat.Credentials.Name
+			}
 		}
 	case operatorv1beta1.ProviderTypeInfoblox:
 		if externalDNS.Spec.Provider.Infoblox != nil {
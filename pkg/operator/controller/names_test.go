@@ -57,3 +57,14 @@ func TestExternalDNSContainerName(t *testing.T) {
 		})
 	}
 }
+
+func TestExternalDNSNamespacedContainerName(t *testing.T) {
+	if got, expect := ExternalDNSNamespacedContainerName("abc123def234", ""), ExternalDNSContainerName("abc123def234"); got != expect {
+		t.Errorf("expect %s container name for all namespaces, got %s", expect, got)
+	}
+	tenantA := ExternalDNSNamespacedContainerName("abc123def234", "tenant-a")
+	tenantB := ExternalDNSNamespacedContainerName("abc123def234", "tenant-b")
+	if tenantA == tenantB || tenantA == ExternalDNSContainerName("abc123def234") {
+		t.Errorf("expect unique container names per namespace, got %s and %s", tenantA, tenantB)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	operatorconfig "github.com/openshift/external-dns-operator/pkg/operator/config"
+	ctrlutils "github.com/openshift/external-dns-operator/pkg/operator/controller/utils"
+)
+
+const (
+	controllerName = "platform_controller"
+)
+
+// Config holds all the things necessary for the controller to run.
+type Config struct {
+	// Platform holds the platform details kept up to date by the controller.
+	Platform *operatorconfig.PlatformDetails
+	// Changes receives an event every time the platform details change.
+	// The channel is expected to be buffered: the event is dropped
+	// if the previous one is not consumed yet.
+	Changes chan<- event.GenericEvent
+}
+
+type reconciler struct {
+	client client.Client
+	config Config
+	log    logr.Logger
+}
+
+// New creates a new controller that keeps the platform details
+// up to date with the OpenShift infrastructure, dns and proxy configs.
+func New(mgr manager.Manager, config Config) (controller.Controller, error) {
+	log := ctrl.Log.WithName(controllerName)
+	operatorCache := mgr.GetCache()
+
+	reconciler := &reconciler{
+		client: mgr.GetClient(),
+		config: config,
+		log:    log,
+	}
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler: reconciler,
+		// the platform details are used by the webhook
+		// which is served by all the replicas, not only by the leader
+		NeedLeaderElection: ptr.To(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the infrastructure, dns and proxy configs are reconciled as a whole
+	toClusterConfig := func(ctx context.Context, o client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: operatorconfig.OpenShiftClusterConfigName}}}
+	}
+
+	for _, o := range []client.Object{&configv1.Infrastructure{}, &configv1.DNS{}, &configv1.Proxy{}} {
+		if err := c.Watch(
+			source.Kind[client.Object](operatorCache, o,
+				handler.EnqueueRequestsFromMapFunc(toClusterConfig),
+				predicate.NewPredicateFuncs(ctrlutils.HasName(operatorconfig.OpenShiftClusterConfigName)),
+			)); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Reconcile refreshes the platform details and notifies about the changes.
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := r.log.WithValues("config", request.NamespacedName)
+	reqLogger.Info("reconciling platform details")
+
+	changed, err := r.config.Platform.Refresh(ctx, r.client)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to refresh the platform details: %w", err)
+	}
+	if !changed {
+		return reconcile.Result{}, nil
+	}
+
+	reqLogger.Info("platform details changed")
+	if r.config.Changes != nil {
+		select {
+		case r.config.Changes <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: request.Name}}}:
+		default:
+			// the previous change is not consumed yet, it triggers the same requeue
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	operatorconfig "github.com/openshift/external-dns-operator/pkg/operator/config"
+	"github.com/openshift/external-dns-operator/pkg/operator/controller/utils/test"
+)
+
+func TestReconcile(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		existingObjects        []runtime.Object
+		initialPlatformStatus  *configv1.PlatformStatus
+		initialClusterDNS      *configv1.DNSSpec
+		initialClusterProxy    *configv1.Proxy
+		expectedPlatformStatus *configv1.PlatformStatus
+		expectedClusterDNS     *configv1.DNSSpec
+		expectedClusterProxy   *configv1.Proxy
+		expectedChange         bool
+		errExpected            bool
+	}{
+		{
+			name:                   "Initial details",
+			existingObjects:        []runtime.Object{testInfrastructure("us-east-1"), testDNS("Z1")},
+			expectedPlatformStatus: testPlatformStatus("us-east-1"),
+			expectedClusterDNS:     testDNSSpec("Z1"),
+			expectedChange:         true,
+		},
+		{
+			name:                   "Details didn't change",
+			existingObjects:        []runtime.Object{testInfrastructure("us-east-1"), testDNS("Z1")},
+			initialPlatformStatus:  testPlatformStatus("us-east-1"),
+			initialClusterDNS:      testDNSSpec("Z1"),
+			expectedPlatformStatus: testPlatformStatus("us-east-1"),
+			expectedClusterDNS:     testDNSSpec("Z1"),
+		},
+		{
+			name:                   "Region changed",
+			existingObjects:        []runtime.Object{testInfrastructure("us-west-2"), testDNS("Z1")},
+			initialPlatformStatus:  testPlatformStatus("us-east-1"),
+			initialClusterDNS:      testDNSSpec("Z1"),
+			expectedPlatformStatus: testPlatformStatus("us-west-2"),
+			expectedClusterDNS:     testDNSSpec("Z1"),
+			expectedChange:         true,
+		},
+		{
+			name:                   "Public zone changed",
+			existingObjects:        []runtime.Object{testInfrastructure("us-east-1"), testDNS("Z2")},
+			initialPlatformStatus:  testPlatformStatus("us-east-1"),
+			initialClusterDNS:      testDNSSpec("Z1"),
+			expectedPlatformStatus: testPlatformStatus("us-east-1"),
+			expectedClusterDNS:     testDNSSpec("Z2"),
+			expectedChange:         true,
+		},
+		{
+			name:                   "Proxy changed",
+			existingObjects:        []runtime.Object{testInfrastructure("us-east-1"), testDNS("Z1"), testProxy("http://proxy.test:3128")},
+			initialPlatformStatus:  testPlatformStatus("us-east-1"),
+			initialClusterDNS:      testDNSSpec("Z1"),
+			initialClusterProxy:    testProxyConfig("http://proxy.old:3128"),
+			expectedPlatformStatus: testPlatformStatus("us-east-1"),
+			expectedClusterDNS:     testDNSSpec("Z1"),
+			expectedClusterProxy:   testProxyConfig("http://proxy.test:3128"),
+			expectedChange:         true,
+		},
+		{
+			name:                   "Proxy removed",
+			existingObjects:        []runtime.Object{testInfrastructure("us-east-1"), testDNS("Z1")},
+			initialPlatformStatus:  testPlatformStatus("us-east-1"),
+			initialClusterDNS:      testDNSSpec("Z1"),
+			initialClusterProxy:    testProxyConfig("http://proxy.test:3128"),
+			expectedPlatformStatus: testPlatformStatus("us-east-1"),
+			expectedClusterDNS:     testDNSSpec("Z1"),
+			expectedChange:         true,
+		},
+		{
+			name:                   "Missing dns config",
+			existingObjects:        []runtime.Object{testInfrastructure("us-west-2")},
+			initialPlatformStatus:  testPlatformStatus("us-east-1"),
+			initialClusterDNS:      testDNSSpec("Z1"),
+			expectedPlatformStatus: testPlatformStatus("us-east-1"),
+			expectedClusterDNS:     testDNSSpec("Z1"),
+			errExpected:            true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := make(chan event.GenericEvent, 1)
+			r := &reconciler{
+				client: fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(tc.existingObjects...).Build(),
+				config: Config{
+					Platform: operatorconfig.NewPlatformDetails(tc.initialPlatformStatus, tc.initialClusterDNS, tc.initialClusterProxy),
+					Changes:  changes,
+				},
+				log: zap.New(zap.UseDevMode(true)),
+			}
+
+			_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: operatorconfig.OpenShiftClusterConfigName}})
+			if err != nil && !tc.errExpected {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && tc.errExpected {
+				t.Fatalf("error expected but not received")
+			}
+
+			if diff := cmp.Diff(tc.expectedPlatformStatus, r.config.Platform.PlatformStatus()); diff != "" {
+				t.Errorf("unexpected platform status (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedClusterDNS, r.config.Platform.ClusterDNS()); diff != "" {
+				t.Errorf("unexpected cluster dns (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedClusterProxy, r.config.Platform.ClusterProxy()); diff != "" {
+				t.Errorf("unexpected cluster proxy (-want +got):\n%s", diff)
+			}
+
+			if gotChange := len(changes) == 1; gotChange != tc.expectedChange {
+				t.Errorf("expected change event %t, got %t", tc.expectedChange, gotChange)
+			}
+		})
+	}
+}
+
+func TestReconcileDropsPendingChange(t *testing.T) {
+	changes := make(chan event.GenericEvent, 1)
+	changes <- event.GenericEvent{}
+	r := &reconciler{
+		client: fake.NewClientBuilder().WithScheme(test.Scheme).WithRuntimeObjects(testInfrastructure("us-west-2"), testDNS("Z1")).Build(),
+		config: Config{
+			Platform: operatorconfig.NewPlatformDetails(testPlatformStatus("us-east-1"), testDNSSpec("Z1"), nil),
+			Changes:  changes,
+		},
+		log: zap.New(zap.UseDevMode(true)),
+	}
+
+	if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: operatorconfig.OpenShiftClusterConfigName}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Errorf("expected the single pending change event, got %d", len(changes))
+	}
+	if diff := cmp.Diff(testPlatformStatus("us-west-2"), r.config.Platform.PlatformStatus()); diff != "" {
+		t.Errorf("unexpected platform status (-want +got):\n%s", diff)
+	}
+}
+
+func testPlatformStatus(region string) *configv1.PlatformStatus {
+	return &configv1.PlatformStatus{
+		Type: configv1.AWSPlatformType,
+		AWS:  &configv1.AWSPlatformStatus{Region: region},
+	}
+}
+
+func testDNSSpec(zoneID string) *configv1.DNSSpec {
+	return &configv1.DNSSpec{
+		BaseDomain: "example.com",
+		PublicZone: &configv1.DNSZone{ID: zoneID},
+	}
+}
+
+func testInfrastructure(region string) *configv1.Infrastructure {
+	return &configv1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorconfig.OpenShiftClusterConfigName},
+		Status: configv1.InfrastructureStatus{
+			PlatformStatus: testPlatformStatus(region),
+		},
+	}
+}
+
+func testDNS(zoneID string) *configv1.DNS {
+	return &configv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorconfig.OpenShiftClusterConfigName},
+		Spec:       *testDNSSpec(zoneID),
+	}
+}
+
+func testProxyConfig(httpsProxy string) *configv1.Proxy {
+	return &configv1.Proxy{
+		Spec: configv1.ProxySpec{
+			HTTPSProxy: httpsProxy,
+			TrustedCA:  configv1.ConfigMapNameReference{Name: "user-ca-bundle"},
+		},
+		Status: configv1.ProxyStatus{
+			HTTPSProxy: httpsProxy,
+			NoProxy:    ".cluster.local,.svc",
+		},
+	}
+}
+
+func testProxy(httpsProxy string) *configv1.Proxy {
+	proxy := testProxyConfig(httpsProxy)
+	proxy.Name = operatorconfig.OpenShiftClusterConfigName
+	proxy.ResourceVersion = "42"
+	return proxy
+}
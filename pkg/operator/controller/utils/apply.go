@@ -0,0 +1,83 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// FieldManager is the field manager of the operand resources applied by the operator.
+const FieldManager = "external-dns-operator"
+
+// legacyFieldManagers are the field managers of the operand resources
+// created and updated by the operator before the server-side apply was used.
+var legacyFieldManagers = sets.New[string](FieldManager)
+
+// Apply applies the given object with the server-side apply.
+// The operator owns all the fields set in the given object:
+// the external changes to them are reverted, the owned fields missing in the given object are removed.
+// The given current object is the one from the cluster, nil if it doesn't exist.
+// The ownership of its fields is migrated from the updates made before the server-side apply was used.
+// The given object is updated with the applied one.
+// Returns true if the object was created or changed, and an error when relevant.
+func Apply(ctx context.Context, cl client.Client, current, obj client.Object, opts ...client.PatchOption) (bool, error) {
+	exists := current != nil && !reflect.ValueOf(current).IsNil()
+	if exists {
+		if err := upgradeManagedFields(ctx, cl, current); err != nil {
+			return false, err
+		}
+	}
+
+	// the apply configuration is serialized from the typed object
+	gvk, err := apiutil.GVKForObject(obj, cl.Scheme())
+	if err != nil {
+		return false, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	opts = append([]client.PatchOption{client.FieldOwner(FieldManager), client.ForceOwnership}, opts...)
+	if err := cl.Patch(ctx, obj, client.Apply, opts...); err != nil {
+		return false, err
+	}
+	return !exists || obj.GetResourceVersion() != current.GetResourceVersion(), nil
+}
+
+// upgradeManagedFields transfers the ownership of the fields managed by the legacy updates
+// to the operator's apply, otherwise the fields removed from the desired object would never be removed.
+func upgradeManagedFields(ctx context.Context, cl client.Client, current client.Object) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(current, legacyFieldManagers, FieldManager)
+	if err != nil {
+		return fmt.Errorf("failed to build the managed fields upgrade patch: %w", err)
+	}
+	if patch == nil {
+		return nil
+	}
+	if err := cl.Patch(ctx, current, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return fmt.Errorf("failed to upgrade the managed fields: %w", err)
+	}
+	return nil
+}
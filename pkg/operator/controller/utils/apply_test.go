@@ -0,0 +1,115 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestApply(t *testing.T) {
+	legacyManagedFields := []metav1.ManagedFieldsEntry{
+		{
+			Manager:    FieldManager,
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{".":{},"f:foo":{}}}}`)},
+		},
+	}
+
+	testCases := []struct {
+		name                 string
+		current              *corev1.ServiceAccount
+		expectedUpgradePatch bool
+	}{
+		{
+			name: "Does not exist",
+		},
+		{
+			name: "Exists",
+			current: &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", ResourceVersion: "1"},
+			},
+		},
+		{
+			name: "Exists with legacy managed fields",
+			current: &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", ResourceVersion: "1", ManagedFields: legacyManagedFields},
+			},
+			expectedUpgradePatch: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var applyOpts *client.PatchOptions
+			var applied *corev1.ServiceAccount
+			upgradePatched := false
+			cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					switch patch.Type() {
+					case types.ApplyPatchType:
+						applyOpts = &client.PatchOptions{}
+						applyOpts.ApplyOptions(opts)
+						applied = obj.(*corev1.ServiceAccount).DeepCopy()
+						obj.SetResourceVersion("2")
+					case types.JSONPatchType:
+						upgradePatched = true
+					default:
+						t.Errorf("unexpected patch type %q", patch.Type())
+					}
+					return nil
+				},
+			}).Build()
+
+			desired := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: map[string]string{"foo": "bar"}},
+			}
+			changed, err := Apply(context.TODO(), cl, tc.current, desired)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !changed {
+				t.Errorf("expected the object to be changed")
+			}
+			if upgradePatched != tc.expectedUpgradePatch {
+				t.Errorf("expected the managed fields upgrade patch %t, got %t", tc.expectedUpgradePatch, upgradePatched)
+			}
+			if applyOpts == nil {
+				t.Fatalf("expected the object to be applied")
+			}
+			if applyOpts.FieldManager != FieldManager {
+				t.Errorf("expected field manager %q, got %q", FieldManager, applyOpts.FieldManager)
+			}
+			if applyOpts.Force == nil || !*applyOpts.Force {
+				t.Errorf("expected the ownership to be forced")
+			}
+			if gvk := applied.GetObjectKind().GroupVersionKind(); gvk.Kind != "ServiceAccount" || gvk.Version != "v1" {
+				t.Errorf("expected the applied object to have its kind set, got %v", gvk)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// ServerSideApply emulates the server-side apply which is not supported by the fake client:
+// the applied object is created or replaces the current one unless it's the same.
+var ServerSideApply = interceptor.Funcs{
+	Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+		if patch.Type() != types.ApplyPatchType {
+			return cl.Patch(ctx, obj, patch, opts...)
+		}
+
+		patchOpts := &client.PatchOptions{}
+		patchOpts.ApplyOptions(opts)
+
+		current := obj.DeepCopyObject().(client.Object)
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			return cl.Create(ctx, obj, &client.CreateOptions{DryRun: patchOpts.DryRun})
+		}
+
+		// the fields set by the API are not part of the applied object
+		applied := obj.DeepCopyObject().(client.Object)
+		applied.GetObjectKind().SetGroupVersionKind(current.GetObjectKind().GroupVersionKind())
+		applied.SetResourceVersion(current.GetResourceVersion())
+		applied.SetUID(current.GetUID())
+		applied.SetCreationTimestamp(current.GetCreationTimestamp())
+		applied.SetGeneration(current.GetGeneration())
+		applied.SetManagedFields(current.GetManagedFields())
+		if equality.Semantic.DeepEqual(applied, current) {
+			reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(current).Elem())
+			return nil
+		}
+
+		obj.SetResourceVersion(current.GetResourceVersion())
+		return cl.Update(ctx, obj, &client.UpdateOptions{DryRun: patchOpts.DryRun})
+	},
+}
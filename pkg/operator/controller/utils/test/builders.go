@@ -45,6 +45,30 @@ func (b *ExternalDNSBuilder) WithAWS() *ExternalDNSBuilder {
 	return b.WithProviderType(operatorv1beta1.ProviderTypeAWS)
 }
 
+func (b *ExternalDNSBuilder) WithAWSAssumeRole(arn string) *ExternalDNSBuilder {
+	if b.extDNS.Spec.Provider.AWS == nil {
+		b.extDNS.Spec.Provider.AWS = &operatorv1beta1.ExternalDNSAWSProviderOptions{}
+	}
+	b.extDNS.Spec.Provider.AWS.AssumeRole = &operatorv1beta1.ExternalDNSAWSAssumeRoleOptions{
+		ARN: arn,
+	}
+	return b
+}
+
+func (b *ExternalDNSBuilder) WithAWSZoneAssumeRole(zone, arn, externalID string) *ExternalDNSBuilder {
+	if b.extDNS.Spec.Provider.AWS == nil {
+		b.extDNS.Spec.Provider.AWS = &operatorv1beta1.ExternalDNSAWSProviderOptions{}
+	}
+	b.extDNS.Spec.Provider.AWS.ZoneAssumeRoles = append(b.extDNS.Spec.Provider.AWS.ZoneAssumeRoles, operatorv1beta1.ExternalDNSAWSZoneAssumeRoleOptions{
+		ZoneID: zone,
+		ExternalDNSAWSAssumeRoleOptions: operatorv1beta1.ExternalDNSAWSAssumeRoleOptions{
+			ARN:        arn,
+			ExternalID: externalID,
+		},
+	})
+	return b
+}
+
 func (b *ExternalDNSBuilder) WithAzure() *ExternalDNSBuilder {
 	return b.WithProviderType(operatorv1beta1.ProviderTypeAzure)
 }
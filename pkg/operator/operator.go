@@ -24,7 +24,7 @@ import (
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metrics "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -35,6 +35,7 @@ import (
 	caconfigmapctrl "github.com/openshift/external-dns-operator/pkg/operator/controller/ca-configmap"
 	credsecretctrl "github.com/openshift/external-dns-operator/pkg/operator/controller/credentials-secret"
 	externaldnsctrl "github.com/openshift/external-dns-operator/pkg/operator/controller/externaldns"
+	platformctrl "github.com/openshift/external-dns-operator/pkg/operator/controller/platform"
 )
 
 // Operator holds the manager for the ExternalDNS opreator.
@@ -46,13 +47,21 @@ type Operator struct {
 // +kubebuilder:rbac:groups=externaldns.olm.openshift.io,resources=externaldnses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=externaldns.olm.openshift.io,resources=externaldnses/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=externaldns.olm.openshift.io,resources=externaldnses/finalizers,verbs=update
+// +kubebuilder:rbac:groups=externaldns.olm.openshift.io,resources=externaldnsconfigs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cloudcredential.openshift.io,resources=credentialsrequests;credentialsrequests/status;credentialsrequests/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;watch;list
-// +kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list
+// +kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures;dnses;proxies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings;rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=external-dns;external-dns-nodes
 // local role
 // +kubebuilder:rbac:groups="",namespace=external-dns-operator,resources=secrets;serviceaccounts;configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="apps",namespace=external-dns-operator,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",namespace=external-dns-operator,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",namespace=external-dns-operator,resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups="batch",namespace=external-dns-operator,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 
 // New creates a new operator from cliCfg and opCfg.
 func New(cliCfg *rest.Config, opCfg *operatorconfig.Config) (*Operator, error) {
@@ -78,18 +87,9 @@ func New(cliCfg *rest.Config, opCfg *operatorconfig.Config) (*Operator, error) {
 				opCfg.OperatorNamespace: {},
 				opCfg.OperandNamespace:  {},
 			},
-		},
-		// Use a non-caching client everywhere. The default split client does not
-		// promise to invalidate the cache during writes (nor does it promise
-		// sequential create/get coherence), and we have code which (probably
-		// incorrectly) assumes a get immediately following a create/update will
-		// return the updated resource. All client consumers will need audited to
-		// ensure they are tolerant of stale data (or we need a cache or client that
-		// makes stronger coherence guarantees).
-		NewClient: func(config *rest.Config, options client.Options) (client.Client, error) {
-			// Must override the cache, otherwise the client will use it.
-			options.Cache = nil
-			return client.New(config, options)
+			// The controllers read from the informer caches,
+			// only the objects the operator owns are cached for the kinds with the high cardinality.
+			ByObject: externaldnsctrl.CacheByObject(opCfg.IsOpenShift),
 		},
 		LeaderElection:   opCfg.EnableLeaderElection,
 		LeaderElectionID: "leaderelection.externaldns.olm.openshift.io",
@@ -101,8 +101,15 @@ func New(cliCfg *rest.Config, opCfg *operatorconfig.Config) (*Operator, error) {
 		return nil, fmt.Errorf("failed to create manager: %w", err)
 	}
 
+	// The platform details are needed by the defaulting webhook.
+	// The cache is not started yet, the API server is read directly.
+	// The details are kept up to date by the platform controller afterwards.
+	if err = opCfg.FillPlatformDetails(context.TODO(), mgr.GetAPIReader()); err != nil {
+		return nil, fmt.Errorf("failed to fill the platform details: %w", err)
+	}
+
 	if opCfg.EnableWebhook {
-		if err = (&operatorv1beta1.ExternalDNS{}).SetupWebhookWithManager(mgr, opCfg.IsOpenShift); err != nil {
+		if err = (&operatorv1beta1.ExternalDNS{}).SetupWebhookWithManager(mgr, opCfg.IsOpenShift, opCfg.Platform); err != nil {
 			return nil, fmt.Errorf("unable to setup webhook for ExternalDNS: %w", err)
 		}
 	}
@@ -112,19 +119,31 @@ func New(cliCfg *rest.Config, opCfg *operatorconfig.Config) (*Operator, error) {
 		return nil, fmt.Errorf("unable to setup ready check: %w", err)
 	}
 
-	if err = opCfg.FillPlatformDetails(context.TODO(), mgr.GetClient()); err != nil {
-		return nil, fmt.Errorf("failed to fill the platform details: %w", err)
+	// The instances are requeued when the platform details change.
+	var platformChanges chan event.GenericEvent
+	if opCfg.IsOpenShift {
+		platformChanges = make(chan event.GenericEvent, 1)
+		// Create and register the platform controller with the operator manager.
+		if _, err := platformctrl.New(mgr, platformctrl.Config{
+			Platform: opCfg.Platform,
+			Changes:  platformChanges,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create platform controller: %w", err)
+		}
 	}
 
 	// Create and register the externaldns controller with the operator manager.
 	if _, err := externaldnsctrl.New(mgr, externaldnsctrl.Config{
-		Namespace:         opCfg.OperandNamespace,
-		Image:             opCfg.ExternalDNSImage,
-		OperatorNamespace: opCfg.OperatorNamespace,
-		IsOpenShift:       opCfg.IsOpenShift,
-		PlatformStatus:    opCfg.PlatformStatus,
-		InjectTrustedCA:   opCfg.InjectTrustedCA(),
-		RequeuePeriod:     opCfg.RequeuePeriod(),
+		Namespace:                  opCfg.OperandNamespace,
+		Image:                      opCfg.ExternalDNSImage,
+		OperatorNamespace:          opCfg.OperatorNamespace,
+		CredentialsSourceNamespace: operatorctrl.ExternalDNSCredentialsSourceNamespace(opCfg),
+		IsOpenShift:                opCfg.IsOpenShift,
+		Platform:                   opCfg.Platform,
+		PlatformChanges:            platformChanges,
+		InjectTrustedCA:            opCfg.InjectTrustedCA(),
+		RequeuePeriod:              opCfg.RequeuePeriod(),
+		EnablePreflight:            opCfg.EnablePreflightCheck,
 	}); err != nil {
 		return nil, fmt.Errorf("failed to create externaldns controller: %w", err)
 	}
@@ -34,7 +34,7 @@ func ManagedCredentialsProvider(e *operatorv1beta1.ExternalDNS) bool {
 // EnvProxySupportedProvider returns true if the ExternalDNS provider supports the proxy settings via environment variables HTTP(S)_PROXY, NO_PROXY
 func EnvProxySupportedProvider(e *operatorv1beta1.ExternalDNS) bool {
 	switch e.Spec.Provider.Type {
-	case operatorv1beta1.ProviderTypeAWS, operatorv1beta1.ProviderTypeAzure, operatorv1beta1.ProviderTypeGCP, operatorv1beta1.ProviderTypeInfoblox:
+	case operatorv1beta1.ProviderTypeAWS, operatorv1beta1.ProviderTypeAzure, operatorv1beta1.ProviderTypeGCP, operatorv1beta1.ProviderTypeInfoblox, operatorv1beta1.ProviderTypeBlueCat:
 		return true
 	}
 	return false
@@ -136,7 +136,7 @@ func (a *azureTestHelper) buildExternalDNS(name, zoneID, zoneDomain string, cred
 	resource.Spec.Provider = operatorv1beta1.ExternalDNSProvider{
 		Type: operatorv1beta1.ProviderTypeAzure,
 		Azure: &operatorv1beta1.ExternalDNSAzureProviderOptions{
-			ConfigFile: operatorv1beta1.SecretReference{
+			ConfigFile: &operatorv1beta1.SecretReference{
 				Name: credsSecret.Name,
 			},
 		},